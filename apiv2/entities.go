@@ -0,0 +1,191 @@
+// Package apiv2 converts between extracted entities and the entities of
+// tweets returned by version 2 of the Twitter API, so that entities
+// supplied by the API and entities extracted from text can be used
+// interchangeably, e.g. with autolink.AutoLinkWithEntities:
+//
+//	entities := apiv2.ToTwitterEntities(tweet.Text, tweet.Entities)
+//	html := autolink.AutoLinkWithEntities(tweet.Text, entities)
+//
+// The start and end of each API entity are offsets into the tweet text in
+// UTF-16 code units. End is exclusive.
+package apiv2
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// The "entities" object of a tweet. A payload can be decoded directly into
+// this type using encoding/json.
+type Entities struct {
+	Urls     []UrlEntity     `json:"urls,omitempty"`
+	Hashtags []HashtagEntity `json:"hashtags,omitempty"`
+	Mentions []MentionEntity `json:"mentions,omitempty"`
+	Cashtags []CashtagEntity `json:"cashtags,omitempty"`
+}
+
+// A URL entity
+type UrlEntity struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Url         string `json:"url"`                    // The URL that appears in the text, e.g. a t.co URL
+	ExpandedUrl string `json:"expanded_url,omitempty"` // The fully expanded URL
+	DisplayUrl  string `json:"display_url,omitempty"`  // The URL to display to users
+	UnwoundUrl  string `json:"unwound_url,omitempty"`  // The URL after following redirects
+	MediaKey    string `json:"media_key,omitempty"`    // Set if the URL refers to attached media
+}
+
+// A hashtag entity. Tag does not include the leading #
+type HashtagEntity struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+// A mention entity. Username does not include the leading @
+type MentionEntity struct {
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	Username string `json:"username"`
+	Id       string `json:"id,omitempty"`
+}
+
+// A cashtag entity. Tag does not include the leading $
+type CashtagEntity struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Tag   string `json:"tag"`
+}
+
+// Converts API entities for text to extract entities, sorted by their
+// position in text. Entities whose offsets are out of range, split a
+// character, or overlap a preceding entity are dropped.
+func ToTwitterEntities(text string, entities Entities) []*extract.TwitterEntity {
+	offsets := newOffsets(text)
+
+	var result []*extract.TwitterEntity
+	for _, u := range entities.Urls {
+		if start, stop, ok := offsets.byteRange(u.Start, u.End); ok {
+			result = append(result, extract.NewUrlEntity(text, start, stop, u.DisplayUrl, u.ExpandedUrl))
+		}
+	}
+	for _, h := range entities.Hashtags {
+		if start, stop, ok := offsets.byteRange(h.Start, h.End); ok {
+			result = append(result, extract.NewHashtagEntity(text, start, stop, h.Tag))
+		}
+	}
+	for _, m := range entities.Mentions {
+		if start, stop, ok := offsets.byteRange(m.Start, m.End); ok {
+			result = append(result, extract.NewMentionEntity(text, start, stop, m.Username, ""))
+		}
+	}
+	for _, c := range entities.Cashtags {
+		if start, stop, ok := offsets.byteRange(c.Start, c.End); ok {
+			result = append(result, extract.NewCashtagEntity(text, start, stop, c.Tag))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ByteRange.Start < result[j].ByteRange.Start
+	})
+	n, prevStop := 0, 0
+	for _, e := range result {
+		if e.ByteRange.Start >= prevStop {
+			result[n] = e
+			n++
+			prevStop = e.ByteRange.Stop
+		}
+	}
+	return result[:n]
+}
+
+// Converts extract entities found in text to API entities. A mention of a
+// list becomes a mention of the list's owner, since the API has no list
+// entities, and emoji, which the API does not report, are dropped.
+// Entities whose byte ranges are out of range are also dropped.
+func FromTwitterEntities(text string, entities []*extract.TwitterEntity) Entities {
+	offsets := newOffsets(text)
+
+	var result Entities
+	for _, e := range entities {
+		r := e.ByteRange
+		if r.Start < 0 || r.Start > r.Stop || r.Stop > len(text) {
+			continue
+		}
+		start, end := offsets.utf16[r.Start], offsets.utf16[r.Stop]
+		if start < 0 || end < 0 {
+			continue
+		}
+		switch e.Type {
+		case extract.URL:
+			displayUrl, _ := e.DisplayUrl()
+			expandedUrl, _ := e.ExpandedUrl()
+			result.Urls = append(result.Urls, UrlEntity{Start: start, End: end, Url: e.Text, ExpandedUrl: expandedUrl, DisplayUrl: displayUrl})
+		case extract.HASH_TAG:
+			tag, _ := e.Hashtag()
+			result.Hashtags = append(result.Hashtags, HashtagEntity{start, end, tag})
+		case extract.MENTION:
+			screenName, _ := e.ScreenName()
+			if listSlug, ok := e.ListSlug(); ok {
+				end -= utf16Length(listSlug)
+			}
+			result.Mentions = append(result.Mentions, MentionEntity{Start: start, End: end, Username: screenName})
+		case extract.CASH_TAG:
+			tag, _ := e.Cashtag()
+			result.Cashtags = append(result.Cashtags, CashtagEntity{start, end, tag})
+		}
+	}
+	return result
+}
+
+// Maps between byte offsets and UTF-16 offsets in a text
+type offsets struct {
+	// The byte offset of each UTF-16 offset, including the end of the text,
+	// or -1 for an offset within a character
+	bytes []int
+
+	// The UTF-16 offset of each byte offset, including the end of the
+	// text, or -1 for an offset within a character
+	utf16 []int
+}
+
+func newOffsets(text string) *offsets {
+	o := &offsets{utf16: make([]int, len(text)+1)}
+	for i, r := range text {
+		o.utf16[i] = len(o.bytes)
+		_, size := utf8.DecodeRuneInString(text[i:])
+		for j := i + 1; j < i+size; j++ {
+			o.utf16[j] = -1
+		}
+		o.bytes = append(o.bytes, i)
+		if r >= 0x10000 {
+			o.bytes = append(o.bytes, -1)
+		}
+	}
+	o.utf16[len(text)] = len(o.bytes)
+	o.bytes = append(o.bytes, len(text))
+	return o
+}
+
+// Returns the byte range of the UTF-16 range [start, end), and whether it
+// is a non-empty range that does not split a character
+func (o *offsets) byteRange(start, end int) (int, int, bool) {
+	if start < 0 || start >= end || end >= len(o.bytes) || o.bytes[start] < 0 || o.bytes[end] < 0 {
+		return 0, 0, false
+	}
+	return o.bytes[start], o.bytes[end], true
+}
+
+// Returns the length of s in UTF-16 code units
+func utf16Length(s string) int {
+	n := 0
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			n++
+		}
+	}
+	return n
+}
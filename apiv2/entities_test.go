@@ -0,0 +1,97 @@
+package apiv2
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// A tweet as returned by the API. The emoji is two UTF-16 code units
+const payload = `{
+	"text": "😀 @user #tag $TAG https://t.co/abc",
+	"entities": {
+		"urls": [{"start": 19, "end": 35, "url": "https://t.co/abc", "expanded_url": "https://example.com/page", "display_url": "example.com/page"}],
+		"hashtags": [{"start": 9, "end": 13, "tag": "tag"}],
+		"mentions": [{"start": 3, "end": 8, "username": "user", "id": "12"}],
+		"cashtags": [{"start": 14, "end": 18, "tag": "TAG"}]
+	}
+}`
+
+func TestToTwitterEntities(t *testing.T) {
+	var tweet struct {
+		Text     string   `json:"text"`
+		Entities Entities `json:"entities"`
+	}
+	if err := json.Unmarshal([]byte(payload), &tweet); err != nil {
+		t.Fatal(err)
+	}
+
+	entities := ToTwitterEntities(tweet.Text, tweet.Entities)
+	expected := []string{"@user", "#tag", "$TAG", "https://t.co/abc"}
+	if len(entities) != len(expected) {
+		t.Fatalf("ToTwitterEntities returned incorrect number of entities for text [%s]. Expected:%d Got:%d", tweet.Text, len(expected), len(entities))
+	}
+	for i, e := range entities {
+		if e.Text != expected[i] {
+			t.Errorf("ToTwitterEntities returned incorrect entity %d for text [%s]. Expected:%s Got:%s", i, tweet.Text, expected[i], e.Text)
+		}
+	}
+	if expanded, _ := entities[3].ExpandedUrl(); expanded != "https://example.com/page" {
+		t.Errorf("ToTwitterEntities returned incorrect expanded URL. Expected:https://example.com/page Got:%s", expanded)
+	}
+
+	html := autolink.AutoLinkWithEntities(tweet.Text, entities)
+	if expected := autolink.AutoLink("\U0001F600 @user #tag $TAG"); len(html) <= len(expected) || html[:len(expected)] != expected {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for converted entities. Expected prefix:%s Got:%s", expected, html)
+	}
+}
+
+func TestToTwitterEntitiesInvalid(t *testing.T) {
+	text := "\U0001F600 #a #b"
+	entities := Entities{Hashtags: []HashtagEntity{
+		{1, 5, "a"},  // starts within the emoji
+		{3, 5, "a"},  // valid
+		{4, 6, "b"},  // overlaps the preceding entity
+		{6, 9, "b"},  // out of range
+		{6, 6, "b"},  // empty
+		{-1, 2, "b"}, // negative
+	}}
+	actual := ToTwitterEntities(text, entities)
+	if len(actual) != 1 || actual[0].Text != "#a" {
+		t.Errorf("ToTwitterEntities returned incorrect value for invalid offsets in text [%s]. Got:%v", text, actual)
+	}
+}
+
+func TestFromTwitterEntities(t *testing.T) {
+	text := "\U0001F600 @user/list #tag $TAG example.com \U0001F600"
+	expected := Entities{
+		Mentions: []MentionEntity{{Start: 3, End: 8, Username: "user"}},
+		Hashtags: []HashtagEntity{{14, 18, "tag"}},
+		Cashtags: []CashtagEntity{{19, 23, "TAG"}},
+		Urls:     []UrlEntity{{Start: 24, End: 35, Url: "example.com"}},
+	}
+	entities := append(extract.ExtractEntities(text), extract.ExtractEmoji(text)...)
+	if actual := FromTwitterEntities(text, entities); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FromTwitterEntities returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+
+	// Converting back yields the same entities
+	roundTrip := ToTwitterEntities(text, expected)
+	for i, e := range roundTrip {
+		if e.Text != []string{"@user", "#tag", "$TAG", "example.com"}[i] {
+			t.Errorf("ToTwitterEntities did not reverse FromTwitterEntities for text [%s]. Got:%v", text, roundTrip)
+		}
+	}
+}
+
+func TestFromTwitterEntitiesJSON(t *testing.T) {
+	text := "#tag"
+	b, err := json.Marshal(FromTwitterEntities(text, extract.ExtractEntities(text)))
+	expected := `{"hashtags":[{"start":0,"end":4,"tag":"tag"}]}`
+	if err != nil || string(b) != expected {
+		t.Errorf("FromTwitterEntities encoded incorrectly. Expected:%s Got:%s %v", expected, b, err)
+	}
+}
@@ -0,0 +1,123 @@
+package autolink
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// A ColorScheme specifies how each type of entity is styled by RenderANSI.
+// Each value is a list of ANSI SGR parameters, e.g. "1;36" for bold cyan.
+// Entities of a type with an empty value are not styled.
+type ColorScheme struct {
+	Mention string
+	List    string
+	Hashtag string
+	Cashtag string
+	Url     string
+}
+
+// Returns the default ColorScheme used by RenderANSI. To style an
+// Autolinker differently, modify the returned value and pass it with
+// WithColorScheme
+func DefaultColorScheme() ColorScheme {
+	return ColorScheme{
+		Mention: "36",   // cyan
+		List:    "36",   // cyan
+		Hashtag: "34",   // blue
+		Cashtag: "32",   // green
+		Url:     "4;34", // underlined blue
+	}
+}
+
+const ansiReset = "\x1b[0m"
+
+// Renders the given text for display in a terminal using the default
+// settings, styling each of the supplied entities with ANSI escape codes.
+// The entities must be sorted by their position within text and must not
+// overlap, as returned by extract.ExtractEntities.
+func RenderANSI(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	return NewAutolinker(opts...).RenderANSI(text, entities)
+}
+
+// Renders the given text for display in a terminal, styling each of the
+// supplied entities with ANSI escape codes according to the Autolinker's
+// ColorScheme. Control characters in the input other than newlines and
+// tabs are replaced so that the text cannot control the terminal. The
+// entities must be sorted by their
+// position within text and must not overlap, as returned by
+// extract.ExtractEntities.
+func (a *Autolinker) RenderANSI(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	a = a.with(opts)
+
+	buf := a.getBuffer()
+	defer a.putBuffer(buf)
+	offset := 0
+	for _, e := range entities {
+		buf.WriteString(ansiSanitize(text[offset:e.ByteRange.Start]))
+		if sgr := a.ColorScheme.sgrFor(e); sgr != "" {
+			buf.WriteString("\x1b[" + sgr + "m")
+			buf.WriteString(ansiSanitize(displayText(e)))
+			buf.WriteString(ansiReset)
+		} else {
+			buf.WriteString(ansiSanitize(displayText(e)))
+		}
+		offset = e.ByteRange.Stop
+	}
+	buf.WriteString(ansiSanitize(text[offset:]))
+	return buf.String()
+}
+
+// Returns the SGR parameters used to style the given entity
+func (c ColorScheme) sgrFor(e *extract.TwitterEntity) string {
+	switch e.Type {
+	case extract.MENTION:
+		if _, ok := e.ListSlug(); ok {
+			return c.List
+		}
+		return c.Mention
+	case extract.HASH_TAG:
+		return c.Hashtag
+	case extract.CASH_TAG:
+		return c.Cashtag
+	case extract.URL:
+		return c.Url
+	}
+	return ""
+}
+
+// Returns s with the control characters other than newlines and tabs
+// replaced, so that they are displayed rather than interpreted by the
+// terminal: C0 controls and DEL with their symbols from the Control
+// Pictures block, e.g. ␛ (SYMBOL FOR ESCAPE) for ESC, and C1 controls,
+// such as the single-character CSI U+009B, and bytes that are not valid
+// UTF-8, which some terminals read as C1 controls, with U+FFFD
+func ansiSanitize(s string) string {
+	var buf strings.Builder
+	last := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		replacement := rune(-1)
+		switch {
+		case r == '\n' || r == '\t':
+		case r < 0x20:
+			replacement = '\u2400' + r
+		case r == 0x7F:
+			replacement = '\u2421'
+		case 0x80 <= r && r < 0xA0, r == utf8.RuneError && size == 1:
+			replacement = utf8.RuneError
+		}
+		if replacement >= 0 {
+			buf.WriteString(s[last:i])
+			buf.WriteRune(replacement)
+			last = i + size
+		}
+		i += size
+	}
+	if last == 0 {
+		return s
+	}
+	buf.WriteString(s[last:])
+	return buf.String()
+}
@@ -0,0 +1,39 @@
+package autolink
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestRenderANSI(t *testing.T) {
+	tests := []struct {
+		text     string
+		scheme   ColorScheme
+		expected string
+	}{
+		{"hi @user @user/list #tag $TAG http://example.com", DefaultColorScheme(),
+			"hi \x1b[36m@user\x1b[0m \x1b[36m@user/list\x1b[0m \x1b[34m#tag\x1b[0m \x1b[32m$TAG\x1b[0m \x1b[4;34mhttp://example.com\x1b[0m"},
+		{"hi @user #tag", ColorScheme{Hashtag: "1"},
+			"hi @user \x1b[1m#tag\x1b[0m"},
+		{"evil \x1b[2J #tag", ColorScheme{},
+			"evil ␛[2J #tag"},
+		{"evil \u009b2J #tag", ColorScheme{},
+			"evil \ufffd2J #tag"},
+		{"evil \x9b2J #tag", ColorScheme{},
+			"evil \ufffd2J #tag"},
+		{"fake\rreal #tag", ColorScheme{},
+			"fake\u240dreal #tag"},
+		{"bell\a del\x7f nul\x00 #tag", ColorScheme{},
+			"bell\u2407 del\u2421 nul\u2400 #tag"},
+		{"lines\n\tand tabs #tag", DefaultColorScheme(),
+			"lines\n\tand tabs \x1b[34m#tag\x1b[0m"},
+	}
+
+	for _, test := range tests {
+		actual := RenderANSI(test.text, extract.ExtractEntities(test.text), WithColorScheme(test.scheme))
+		if actual != test.expected {
+			t.Errorf("RenderANSI returned incorrect value for text [%q]. Expected:[%q] Got:[%q]", test.text, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,1041 @@
+// Package autolink provides routines for converting the entities found in a
+// tweet into HTML links
+//
+// Unless the input is marked as already escaped, all text and attribute
+// values are HTML-escaped, so the output of the autolinker is safe to
+// include in a page even when the input is untrusted.
+//
+// The implementation and API are based on the Autolink classes in the set of
+// twitter-text-* libraries published by Twitter. Entities are located using
+// the extract package, so the links produced here always agree with what
+// extract considers to be a mention, list, hashtag, cashtag, or URL.
+//
+// In addition to HTML, an Autolinker can render entities as links in
+// other markup languages (see RenderMarkdown and RenderSlack) or
+// style them for display in a terminal (see RenderANSI). Clients that build
+// their own representation of a tweet, such as an attributed string, can
+// split it into plain text and link segments instead (see Segments).
+package autolink
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/internal/bufpool"
+)
+
+const (
+	// Default CSS class for auto-linked list URLs
+	DefaultListClass = "tweet-url list-slug"
+	// Default CSS class for auto-linked username URLs
+	DefaultUsernameClass = "tweet-url username"
+	// Default CSS class for auto-linked hashtag URLs
+	DefaultHashtagClass = "tweet-url hashtag"
+	// Default CSS class for auto-linked cashtag URLs
+	DefaultCashtagClass = "tweet-url cashtag"
+
+	// Default base URL for auto-linked usernames
+	DefaultUsernameUrlBase = "https://twitter.com/"
+	// Default base URL for auto-linked lists
+	DefaultListUrlBase = "https://twitter.com/"
+	// Default base URL for auto-linked hashtags
+	DefaultHashtagUrlBase = "https://twitter.com/search?q=%23"
+	// Default base URL for auto-linked cashtags
+	DefaultCashtagUrlBase = "https://twitter.com/search?q=%24"
+
+	// Default attributes for the spans used to hide the parts of an
+	// expanded URL that are not part of its display URL
+	DefaultInvisibleTagAttrs = "style='position:absolute;left:-9999px;'"
+
+	// CSS class of the spans used in place of InvisibleTagAttrs to hide
+	// parts of expanded URLs in SafeHTML mode. Style sheets using this
+	// mode should hide it, e.g. with .invisible { font-size: 0; }
+	SafeInvisibleClass = "invisible"
+)
+
+// Specifies how the autolinker handles media URLs: links to images and
+// videos attached to a tweet (pic.twitter.com URLs and attachment
+// permalinks such as https://twitter.com/user/status/1/photo/1)
+type MediaUrlMode int
+
+const (
+	MediaUrlLink   MediaUrlMode = iota // Link media URLs like any other URL (the default)
+	MediaUrlSkip                       // Leave media URLs in the output as plain text
+	MediaUrlRemove                     // Remove media URLs from the output
+)
+
+// Specifies how the autolinker protects the display of links in text that
+// contains right-to-left characters
+type BidiMode int
+
+const (
+	BidiNone    BidiMode = iota // Links are not modified (the default)
+	BidiMarks                   // Links are surrounded by LEFT-TO-RIGHT or RIGHT-TO-LEFT marks
+	BidiIsolate                 // Links are wrapped in <bdi> elements
+)
+
+const (
+	leftToRightMark = "\u200e"
+	rightToLeftMark = "\u200f"
+)
+
+var rtlCharacters = regexp.MustCompile("[\u0600-\u06FF\u0750-\u077F\u0590-\u05FF\uFE70-\uFEFF]")
+
+// Represents a single attribute of a generated anchor tag
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// An ordered list of anchor tag attributes. Attributes are rendered
+// in the order they were added.
+type Attributes []Attribute
+
+// Returns the value of the named attribute and a boolean indicating
+// whether the attribute is present
+func (a Attributes) Get(name string) (string, bool) {
+	for _, attr := range a {
+		if attr.Name == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Sets the value of the named attribute, replacing the existing value
+// if the attribute is already present
+func (a *Attributes) Set(name, value string) {
+	for i, attr := range *a {
+		if attr.Name == name {
+			(*a)[i].Value = value
+			return
+		}
+	}
+	*a = append(*a, Attribute{Name: name, Value: value})
+}
+
+// Removes the named attribute, if present
+func (a *Attributes) Del(name string) {
+	for i, attr := range *a {
+		if attr.Name == name {
+			*a = append((*a)[:i], (*a)[i+1:]...)
+			return
+		}
+	}
+}
+
+// A LinkAttributeModifier is called with each entity and the attributes
+// computed for its link just before the link is rendered. It may add,
+// modify, or remove attributes.
+type LinkAttributeModifier func(e *extract.TwitterEntity, attrs *Attributes)
+
+// A LinkTextModifier is called with each entity and the (HTML-escaped)
+// text of its link just before the link is rendered. The returned value
+// replaces the link text and is written to the output as-is, so it must
+// be escaped by the modifier if necessary.
+type LinkTextModifier func(e *extract.TwitterEntity, text string) string
+
+// An Autolinker converts entities within a tweet into HTML links. Use
+// NewAutolinker to obtain an Autolinker configured with the same defaults
+// as the reference implementation; the zero value has no base URLs and
+// produces relative links.
+type Autolinker struct {
+	UrlClass      string // CSS class for auto-linked URLs
+	ListClass     string // CSS class for auto-linked list URLs
+	UsernameClass string // CSS class for auto-linked username URLs
+	HashtagClass  string // CSS class for auto-linked hashtag URLs
+	CashtagClass  string // CSS class for auto-linked cashtag URLs
+
+	// Base URLs for auto-linked entities. The href of each link is the base
+	// URL followed by the username, username/list, hashtag, or cashtag
+	// (without the leading symbol)
+	UsernameUrlBase string
+	ListUrlBase     string
+	HashtagUrlBase  string
+	CashtagUrlBase  string
+
+	// If set, used in place of ListUrlBase to build the href of list links.
+	// The placeholders {user} and {slug} are replaced with the screen name
+	// and the list name, e.g. "https://twitter.com/{user}/lists/{slug}"
+	ListUrlTemplate string
+
+	// If non-nil, used in place of UsernameUrlBase to find the URL of the
+	// profile of each mentioned account, for federated services such as
+	// Mastodon where accounts live on many servers
+	MentionResolver MentionResolver
+
+	// Whether AutoLink and AutoLinkUsernamesAndLists link mentions of
+	// accounts on other servers, of the form @user@domain, which are not
+	// extracted by default. Their hrefs are found by the MentionResolver,
+	// or are https://domain/@user without one
+	FederatedMentions bool
+
+	// If non-nil, called to get the href of the link for each custom
+	// entity (Type=CUSTOM), e.g. the URL of an issue for an "issue" entity.
+	// Custom entities for which it returns "", and all custom entities if
+	// it is nil, are rendered as plain text
+	CustomUrl func(e *extract.TwitterEntity) string
+
+	// Attributes for the spans used to hide the parts of an expanded URL
+	// that are not part of its display URL
+	InvisibleTagAttrs string
+
+	// If set, the symbol (@, #, or $) and the text following it are
+	// wrapped in tags with these names, e.g. SymbolTag="s" and
+	// TextWithSymbolTag="b" produce <s>#</s><b>hashtag</b>
+	SymbolTag         string
+	TextWithSymbolTag string
+
+	// Whether the @ sign of mentions and lists is included in the link.
+	// By default it precedes the link
+	UsernameIncludeSymbol bool
+
+	// Whether the input text is already HTML-escaped. By default, all text
+	// is escaped before being written to the output; set this to avoid
+	// escaping pre-escaped text twice
+	TextIsEscaped bool
+
+	// How media URLs are handled, and the CSS class used for media links
+	// (in place of UrlClass) when they are linked
+	MediaUrlMode MediaUrlMode
+	MediaClass   string
+
+	// How links are protected in text containing right-to-left characters.
+	// Without protection, a left-to-right URL or mention embedded in
+	// right-to-left text (or vice versa) may be displayed with its
+	// characters reordered. Text that contains no right-to-left characters
+	// is never modified
+	BidiMode BidiMode
+
+	// The styles used by RenderANSI
+	ColorScheme ColorScheme
+
+	UrlTarget string // Value of the target attribute for auto-linked URLs (e.g. "_blank")
+	NoFollow  bool   // Whether to add rel="nofollow" to generated links
+	NoOpener  bool   // Whether to add rel="noopener" to generated links
+
+	// Additional rel values for username links (but not list links), such
+	// as "me" for links to the author's own profiles, which are used for
+	// identity verification, or "author"
+	UsernameRel []string
+
+	// If non-nil, called to get the title of each link in place of the
+	// default title, which is the #hashtag or $cashtag for hashtag and
+	// cashtag links and the expanded URL for URLs that have one. Links for
+	// which it returns "" have no title
+	Title LabelFunc
+
+	// If non-nil, called to get the aria-label of each link. Links for
+	// which it returns "" have no aria-label
+	AriaLabel LabelFunc
+
+	// Additional attributes for the links generated for each type of
+	// entity (mentions and lists share the MENTION type). They are added
+	// in order of name after the computed attributes, replacing computed
+	// attributes with the same name
+	EntityAttributes map[extract.EntityType]map[string]string
+
+	// If non-nil, called to customize the attributes of each link
+	LinkAttributeModifier LinkAttributeModifier
+
+	// If non-nil, called to customize the text of each link
+	LinkTextModifier LinkTextModifier
+
+	// Whether to restrict the HTML output to the elements and attributes
+	// returned by SafeHTMLAttributes, so that it passes through HTML
+	// sanitizers such as bluemonday unchanged and needs no inline styles.
+	// In this mode, text is always escaped (pre-escaped text is unescaped
+	// first), InvisibleTagAttrs is replaced by SafeInvisibleClass,
+	// SymbolTag, TextWithSymbolTag, and LinkTextModifier are ignored,
+	// BidiIsolate behaves as BidiMarks, other attributes are dropped from
+	// links, and entities whose href is not a relative, http, or https URL
+	// are rendered as text
+	SafeHTML bool
+
+	// Whether to allocate a new buffer for each rendered string. By
+	// default, buffers are taken from a pool shared by all Autolinkers and
+	// reused by later calls
+	NoBufferPool bool
+}
+
+// A MentionResolver finds the profile URLs of mentioned accounts
+type MentionResolver interface {
+	// Returns the URL of the profile of the account with the given screen
+	// name on the server with the given domain, or "" if it is not known,
+	// in which case the mention is not linked. The domain is empty for a
+	// mention that does not name a server, which refers to an account on
+	// the server the text was posted to. Mentions of the form
+	// @user@domain, which name a server, are only extracted if
+	// FederatedMentions is set
+	ProfileUrl(screenName, domain string) string
+}
+
+// A MentionResolver that builds Mastodon-style profile URLs of the form
+// https://domain/@user. Mentions that do not name a server are resolved
+// against Domain, the server the text was posted to, and are not linked
+// if Domain is empty
+type FediverseResolver struct {
+	Domain string
+}
+
+func (r FediverseResolver) ProfileUrl(screenName, domain string) string {
+	if domain == "" {
+		domain = r.Domain
+	}
+	if domain == "" {
+		return ""
+	}
+	return "https://" + domain + "/@" + screenName
+}
+
+// A LabelFunc returns a human-readable label for the link to an entity,
+// such as its title or aria-label
+type LabelFunc func(e *extract.TwitterEntity) string
+
+// Returns a LabelFunc that formats labels using the given template for each
+// type of entity (mentions and lists share the MENTION type). The
+// placeholder {text} in a template is replaced with the entity's display
+// text, e.g. "#hashtag" or "@username". This allows labels to be localized:
+//
+//	autolink.WithAriaLabel(autolink.Labels(map[extract.EntityType]string{
+//		extract.HASH_TAG: "Buscar {text}",
+//		extract.MENTION:  "Perfil de {text}",
+//	}))
+//
+// Entities of types without a template get no label.
+func Labels(templates map[extract.EntityType]string) LabelFunc {
+	return func(e *extract.TwitterEntity) string {
+		if t, ok := templates[e.Type]; ok {
+			return strings.Replace(t, "{text}", displayText(e), -1)
+		}
+		return ""
+	}
+}
+
+// Returns a new Autolinker configured with the default CSS classes,
+// twitter.com base URLs, and rel="nofollow"
+func NewAutolinker(opts ...Option) *Autolinker {
+	a := &Autolinker{
+		ListClass:       DefaultListClass,
+		UsernameClass:   DefaultUsernameClass,
+		HashtagClass:    DefaultHashtagClass,
+		CashtagClass:    DefaultCashtagClass,
+		UsernameUrlBase: DefaultUsernameUrlBase,
+		ListUrlBase:     DefaultListUrlBase,
+		HashtagUrlBase:  DefaultHashtagUrlBase,
+		CashtagUrlBase:  DefaultCashtagUrlBase,
+
+		InvisibleTagAttrs: DefaultInvisibleTagAttrs,
+		ColorScheme:       DefaultColorScheme(),
+		NoFollow:          true,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// An Option overrides a single Autolinker setting. Options may be passed
+// to NewAutolinker, to the package-level AutoLink* functions, or to the
+// AutoLink* methods of an existing Autolinker, in which case they apply
+// to that call only.
+type Option func(*Autolinker)
+
+// Sets the CSS class for auto-linked URLs
+func WithUrlClass(class string) Option {
+	return func(a *Autolinker) { a.UrlClass = class }
+}
+
+// Sets the CSS class for auto-linked list URLs
+func WithListClass(class string) Option {
+	return func(a *Autolinker) { a.ListClass = class }
+}
+
+// Sets the CSS class for auto-linked username URLs
+func WithUsernameClass(class string) Option {
+	return func(a *Autolinker) { a.UsernameClass = class }
+}
+
+// Sets the CSS class for auto-linked hashtag URLs
+func WithHashtagClass(class string) Option {
+	return func(a *Autolinker) { a.HashtagClass = class }
+}
+
+// Sets the CSS class for auto-linked cashtag URLs
+func WithCashtagClass(class string) Option {
+	return func(a *Autolinker) { a.CashtagClass = class }
+}
+
+// Sets the base URL for auto-linked usernames
+func WithUsernameUrlBase(base string) Option {
+	return func(a *Autolinker) { a.UsernameUrlBase = base }
+}
+
+// Sets the base URL for auto-linked lists
+func WithListUrlBase(base string) Option {
+	return func(a *Autolinker) { a.ListUrlBase = base }
+}
+
+// Sets the URL template for auto-linked lists
+func WithListUrlTemplate(template string) Option {
+	return func(a *Autolinker) { a.ListUrlTemplate = template }
+}
+
+// Sets the resolver used to find the URLs of mentioned accounts' profiles,
+// e.g. WithMentionResolver(FediverseResolver{Domain: "mastodon.social"})
+func WithMentionResolver(r MentionResolver) Option {
+	return func(a *Autolinker) { a.MentionResolver = r }
+}
+
+// Sets whether mentions of the form @user@domain are linked
+func WithFederatedMentions(federated bool) Option {
+	return func(a *Autolinker) { a.FederatedMentions = federated }
+}
+
+// Sets the function that returns the href of each custom entity
+func WithCustomUrl(f func(e *extract.TwitterEntity) string) Option {
+	return func(a *Autolinker) { a.CustomUrl = f }
+}
+
+// Sets whether the HTML output is restricted to SafeHTMLAttributes
+func WithSafeHTML(safe bool) Option {
+	return func(a *Autolinker) { a.SafeHTML = safe }
+}
+
+// Sets the base URL for auto-linked hashtags
+func WithHashtagUrlBase(base string) Option {
+	return func(a *Autolinker) { a.HashtagUrlBase = base }
+}
+
+// Sets the base URL for auto-linked cashtags
+func WithCashtagUrlBase(base string) Option {
+	return func(a *Autolinker) { a.CashtagUrlBase = base }
+}
+
+// Sets the attributes of the spans used to hide parts of expanded URLs
+func WithInvisibleTagAttrs(attrs string) Option {
+	return func(a *Autolinker) { a.InvisibleTagAttrs = attrs }
+}
+
+// Sets the tag used to wrap the symbol (@, #, or $) of an entity
+func WithSymbolTag(tag string) Option {
+	return func(a *Autolinker) { a.SymbolTag = tag }
+}
+
+// Sets the tag used to wrap the text following the symbol of an entity
+func WithTextWithSymbolTag(tag string) Option {
+	return func(a *Autolinker) { a.TextWithSymbolTag = tag }
+}
+
+// Specifies whether the @ sign of mentions and lists is included in the link
+func WithUsernameIncludeSymbol(include bool) Option {
+	return func(a *Autolinker) { a.UsernameIncludeSymbol = include }
+}
+
+// Specifies whether the input text is already HTML-escaped
+func WithTextIsEscaped(escaped bool) Option {
+	return func(a *Autolinker) { a.TextIsEscaped = escaped }
+}
+
+// Sets whether rendering reuses pooled buffers (the default) or allocates
+// a new buffer for each call
+func WithBufferPool(enabled bool) Option {
+	return func(a *Autolinker) { a.NoBufferPool = !enabled }
+}
+
+// Sets how media URLs are handled
+func WithMediaUrlMode(mode MediaUrlMode) Option {
+	return func(a *Autolinker) { a.MediaUrlMode = mode }
+}
+
+// Sets the CSS class for auto-linked media URLs
+func WithMediaClass(class string) Option {
+	return func(a *Autolinker) { a.MediaClass = class }
+}
+
+// Sets how links are protected in text containing right-to-left characters
+func WithBidiMode(mode BidiMode) Option {
+	return func(a *Autolinker) { a.BidiMode = mode }
+}
+
+// Sets the styles used by RenderANSI
+func WithColorScheme(scheme ColorScheme) Option {
+	return func(a *Autolinker) { a.ColorScheme = scheme }
+}
+
+// Sets the target attribute for auto-linked URLs
+func WithUrlTarget(target string) Option {
+	return func(a *Autolinker) { a.UrlTarget = target }
+}
+
+// Enables or disables rel="nofollow" on generated links
+func WithNoFollow(noFollow bool) Option {
+	return func(a *Autolinker) { a.NoFollow = noFollow }
+}
+
+// Enables or disables rel="noopener" on generated links
+func WithNoOpener(noOpener bool) Option {
+	return func(a *Autolinker) { a.NoOpener = noOpener }
+}
+
+// Sets the additional rel values for username links, e.g.
+// WithUsernameRel("me")
+func WithUsernameRel(rel ...string) Option {
+	return func(a *Autolinker) { a.UsernameRel = rel }
+}
+
+// Sets the function used to get the title of each link
+func WithTitle(f LabelFunc) Option {
+	return func(a *Autolinker) { a.Title = f }
+}
+
+// Sets the function used to get the aria-label of each link
+func WithAriaLabel(f LabelFunc) Option {
+	return func(a *Autolinker) { a.AriaLabel = f }
+}
+
+// Sets additional attributes for the links generated for the given type of
+// entity. The Autolinker's existing EntityAttributes map is not modified
+func WithEntityAttributes(t extract.EntityType, attrs map[string]string) Option {
+	return func(a *Autolinker) {
+		m := make(map[extract.EntityType]map[string]string, len(a.EntityAttributes)+1)
+		for k, v := range a.EntityAttributes {
+			m[k] = v
+		}
+		m[t] = attrs
+		a.EntityAttributes = m
+	}
+}
+
+// Sets the function used to customize the attributes of each link
+func WithLinkAttributeModifier(f LinkAttributeModifier) Option {
+	return func(a *Autolinker) { a.LinkAttributeModifier = f }
+}
+
+// Sets the function used to customize the text of each link
+func WithLinkTextModifier(f LinkTextModifier) Option {
+	return func(a *Autolinker) { a.LinkTextModifier = f }
+}
+
+// Returns a copy of the Autolinker with the given options applied, or the
+// Autolinker itself if there are no options
+func (a *Autolinker) with(opts []Option) *Autolinker {
+	if len(opts) == 0 {
+		return a
+	}
+	c := *a
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// Auto-link all usernames, lists, hashtags, cashtags, and URLs in the
+// given text using the default settings, overridden by any supplied
+// options
+func AutoLink(text string, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLink(text)
+}
+
+// Auto-link @username and @username/list references in the given text
+// using the default settings
+func AutoLinkUsernamesAndLists(text string, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkUsernamesAndLists(text)
+}
+
+// Auto-link #hashtag references in the given text using the default
+// settings
+func AutoLinkHashtags(text string, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkHashtags(text)
+}
+
+// Auto-link $cashtag references in the given text using the default
+// settings
+func AutoLinkCashtags(text string, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkCashtags(text)
+}
+
+// Auto-link URLs in the given text using the default settings
+func AutoLinkUrls(text string, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkUrls(text)
+}
+
+// Auto-link all usernames, lists, hashtags, cashtags, and URLs in the
+// given text
+func (a *Autolinker) AutoLink(text string, opts ...Option) string {
+	a = a.with(opts)
+	if a.FederatedMentions {
+		x := extract.Extractor{FederatedMentions: true}
+		return a.autoLinkEntities(text, x.ExtractEntities(text))
+	}
+	return a.autoLinkEntities(text, extract.ExtractEntities(text))
+}
+
+// Auto-link @username and @username/list references in the given text
+func (a *Autolinker) AutoLinkUsernamesAndLists(text string, opts ...Option) string {
+	a = a.with(opts)
+	if a.FederatedMentions {
+		x := extract.Extractor{FederatedMentions: true}
+		return a.autoLinkEntities(text, x.ExtractMentionsOrLists(text))
+	}
+	return a.autoLinkEntities(text, extract.ExtractMentionsOrLists(text))
+}
+
+// Auto-link #hashtag references in the given text
+func (a *Autolinker) AutoLinkHashtags(text string, opts ...Option) string {
+	return a.with(opts).autoLinkEntities(text, extract.ExtractHashtags(text))
+}
+
+// Auto-link $cashtag references in the given text
+func (a *Autolinker) AutoLinkCashtags(text string, opts ...Option) string {
+	return a.with(opts).autoLinkEntities(text, extract.ExtractCashtags(text))
+}
+
+// Auto-link URLs in the given text
+func (a *Autolinker) AutoLinkUrls(text string, opts ...Option) string {
+	return a.with(opts).autoLinkEntities(text, extract.ExtractUrls(text))
+}
+
+// Replaces each of the supplied entities with a link. The entities
+// must be sorted by their position within text and must not overlap.
+func (a *Autolinker) autoLinkEntities(text string, entities []*extract.TwitterEntity) string {
+	buf := a.getBuffer()
+	defer a.putBuffer(buf)
+	offset := 0
+	bidi := a.BidiMode != BidiNone && rtlCharacters.MatchString(text)
+	for _, e := range entities {
+		preceding := text[offset:e.ByteRange.Start]
+		offset = e.ByteRange.Stop
+
+		if e.Type == extract.URL && a.MediaUrlMode != MediaUrlLink && isMediaUrl(e) {
+			if a.MediaUrlMode == MediaUrlRemove {
+				// Don't leave trailing whitespace behind when removing
+				// media URLs from the end of the text
+				if strings.TrimSpace(text[offset:]) == "" {
+					preceding = strings.TrimRightFunc(preceding, unicode.IsSpace)
+					offset = len(text)
+				}
+				buf.WriteString(a.escape(preceding))
+			} else {
+				buf.WriteString(a.escape(preceding))
+				buf.WriteString(a.escape(e.Text))
+			}
+			continue
+		}
+		if a.isUnlinked(e) {
+			buf.WriteString(a.escape(preceding))
+			buf.WriteString(a.escape(e.Text))
+			continue
+		}
+
+		buf.WriteString(a.escape(preceding))
+		if bidi {
+			buf.WriteString(a.bidiOpen(e))
+		}
+		switch e.Type {
+		case extract.URL:
+			a.linkToUrl(e, text, buf)
+		case extract.HASH_TAG:
+			a.linkToHashtag(e, text, buf)
+		case extract.MENTION:
+			a.linkToMentionAndList(e, text, buf)
+		case extract.CASH_TAG:
+			a.linkToCashtag(e, text, buf)
+		case extract.CUSTOM:
+			a.linkToCustom(e, text, buf)
+		}
+		if bidi {
+			buf.WriteString(a.bidiClose(e))
+		}
+	}
+	buf.WriteString(a.escape(text[offset:]))
+	return buf.String()
+}
+
+func (a *Autolinker) linkToUrl(e *extract.TwitterEntity, text string, buf *bytes.Buffer) {
+	linkText := a.escape(e.Text)
+	displayUrl, hasDisplayUrl := e.DisplayUrl()
+	expandedUrl, hasExpandedUrl := e.ExpandedUrl()
+	if hasDisplayUrl && hasExpandedUrl {
+		linkText = a.displayUrlLinkText(displayUrl, expandedUrl)
+	} else if hasDisplayUrl {
+		linkText = html.EscapeString(displayUrl)
+	}
+	a.linkToText(e, linkText, a.attributesFor(e, text), buf)
+}
+
+// Returns the attributes of the link for an entity, in the order in which
+// they are rendered
+func (a *Autolinker) attributesFor(e *extract.TwitterEntity, text string) Attributes {
+	var attrs Attributes
+	switch e.Type {
+	case extract.URL:
+		attrs.Set("href", a.hrefFor(e))
+		if expandedUrl, ok := e.ExpandedUrl(); ok {
+			attrs.Set("title", expandedUrl)
+			attrs.Set("data-expanded-url", expandedUrl)
+		}
+		if a.MediaClass != "" && isMediaUrl(e) {
+			attrs.Set("class", a.MediaClass)
+		} else if a.UrlClass != "" {
+			attrs.Set("class", a.UrlClass)
+		}
+		if a.UrlTarget != "" {
+			attrs.Set("target", a.UrlTarget)
+		}
+	case extract.HASH_TAG:
+		hashtag, _ := e.Hashtag()
+		class := a.HashtagClass
+		if rtlCharacters.MatchString(text) {
+			if class != "" {
+				class += " "
+			}
+			class += "rtl"
+		}
+		attrs.Set("href", a.hrefFor(e))
+		attrs.Set("title", "#"+hashtag)
+		if class != "" {
+			attrs.Set("class", class)
+		}
+	case extract.CASH_TAG:
+		cashtag, _ := e.Cashtag()
+		attrs.Set("href", a.hrefFor(e))
+		attrs.Set("title", "$"+cashtag)
+		if a.CashtagClass != "" {
+			attrs.Set("class", a.CashtagClass)
+		}
+	case extract.CUSTOM:
+		attrs.Set("href", a.hrefFor(e))
+	case extract.MENTION:
+		class := a.UsernameClass
+		if _, ok := e.ListSlug(); ok {
+			class = a.ListClass
+		}
+		if class != "" {
+			attrs.Set("class", class)
+		}
+		attrs.Set("href", a.hrefFor(e))
+	}
+	if a.Title != nil {
+		if title := a.Title(e); title != "" {
+			attrs.Set("title", title)
+		} else {
+			attrs.Del("title")
+		}
+	}
+	if a.AriaLabel != nil {
+		if label := a.AriaLabel(e); label != "" {
+			attrs.Set("aria-label", label)
+		}
+	}
+
+	var rel []string
+	if a.NoFollow {
+		rel = append(rel, "nofollow")
+	}
+	if a.NoOpener {
+		rel = append(rel, "noopener")
+	}
+	if _, isList := e.ListSlug(); e.Type == extract.MENTION && !isList {
+		rel = append(rel, a.UsernameRel...)
+	}
+	if len(rel) > 0 {
+		attrs.Set("rel", strings.Join(rel, " "))
+	}
+	if extra := a.EntityAttributes[e.Type]; len(extra) > 0 {
+		names := make([]string, 0, len(extra))
+		for name := range extra {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			attrs.Set(name, extra[name])
+		}
+	}
+	if a.LinkAttributeModifier != nil {
+		a.LinkAttributeModifier(e, &attrs)
+	}
+	return attrs
+}
+
+// Returns the URL an entity should be linked to
+func (a *Autolinker) hrefFor(e *extract.TwitterEntity) string {
+	switch e.Type {
+	case extract.URL:
+		// URLs without a protocol, e.g. example.com, would otherwise be
+		// relative links
+		url := a.unescape(e.Text)
+		if !strings.Contains(url, "://") {
+			url = "http://" + url
+		}
+		return url
+	case extract.HASH_TAG:
+		hashtag, _ := e.Hashtag()
+		return a.HashtagUrlBase + hashtag
+	case extract.CASH_TAG:
+		cashtag, _ := e.Cashtag()
+		return a.CashtagUrlBase + cashtag
+	case extract.MENTION:
+		screenName, _ := e.ScreenName()
+		if slug, ok := e.ListSlug(); ok {
+			if a.ListUrlTemplate != "" {
+				r := strings.NewReplacer("{user}", screenName, "{slug}", strings.TrimPrefix(slug, "/"))
+				return r.Replace(a.ListUrlTemplate)
+			}
+			return a.ListUrlBase + screenName + slug
+		}
+		domain, _ := e.Domain()
+		if a.MentionResolver != nil {
+			return a.MentionResolver.ProfileUrl(screenName, domain)
+		}
+		if domain != "" {
+			return FediverseResolver{}.ProfileUrl(screenName, domain)
+		}
+		return a.UsernameUrlBase + screenName
+	case extract.CUSTOM:
+		if a.CustomUrl != nil {
+			return a.CustomUrl(e)
+		}
+	}
+	return ""
+}
+
+// Reports whether an entity is rendered as plain text rather than a link:
+// a custom entity, or a mention whose profile URL is not known, with no
+// href
+func (a *Autolinker) isUnlinked(e *extract.TwitterEntity) bool {
+	return (e.Type == extract.CUSTOM || e.Type == extract.MENTION) && a.hrefFor(e) == ""
+}
+
+// Builds the link text for a URL with a display URL and an expanded URL.
+//
+// Goal: If a user copies and pastes a tweet containing a t.co'ed link, the
+// resulting paste should contain the full original URL (the expanded URL),
+// not the display URL.
+//
+// Method: Whenever possible, emit HTML that contains the expanded URL, and
+// hide those parts that are not part of the display URL using invisible
+// spans. Invisible elements are still copied (display:none would not be).
+//
+// Additionally, the ellipses should be displayed but not copied. They are
+// wrapped in spans with the tco-ellipsis class so that a client-side copy
+// handler can hide them before the copy happens.
+//
+// As an example: The user tweets "hi http://longdomainname.com/foo"
+// This gets shortened to "hi http://t.co/xyzabc", with display url "…nname.com/foo"
+// This will get rendered as:
+//
+//	<span class='tco-ellipsis'> <!-- This stuff should get displayed but not copied -->
+//	  …
+//	  <!-- There's a chance the copy handler might not fire. In case that
+//	       happens, include an &nbsp; here so that the … doesn't bump up
+//	       against the URL and ruin it. -->
+//	  <span style='position:absolute;left:-9999px;'>&nbsp;</span>
+//	</span>
+//	<span style='position:absolute;left:-9999px;'> <!-- This stuff should get copied but not displayed -->
+//	  http://longdomai
+//	</span>
+//	<span class='js-display-url'> <!-- This stuff should get displayed *and* copied -->
+//	  nname.com/foo
+//	</span>
+//	<span class='tco-ellipsis'> <!-- This stuff should get displayed but not copied -->
+//	  <span style='position:absolute;left:-9999px;'>&nbsp;</span>
+//	  …
+//	</span>
+//
+// Exception: for pic.twitter.com images the display URL is not a substring
+// of the expanded URL, so the display URL is used as-is.
+func (a *Autolinker) displayUrlLinkText(displayUrl, expandedUrl string) string {
+	displayUrlSansEllipses := strings.Replace(displayUrl, "…", "", -1)
+	i := strings.Index(expandedUrl, displayUrlSansEllipses)
+	if i < 0 {
+		return html.EscapeString(displayUrl)
+	}
+
+	beforeDisplayUrl := expandedUrl[:i]
+	afterDisplayUrl := expandedUrl[i+len(displayUrlSansEllipses):]
+	var precedingEllipsis, followingEllipsis string
+	if strings.HasPrefix(displayUrl, "…") {
+		precedingEllipsis = "…"
+	}
+	if strings.HasSuffix(displayUrl, "…") {
+		followingEllipsis = "…"
+	}
+	invisibleSpan := "<span " + a.InvisibleTagAttrs + ">"
+	ellipsisSpan := "<span class='tco-ellipsis'>"
+	displayUrlSpan := "<span class='js-display-url'>"
+	nbsp := "&nbsp;"
+	if a.SafeHTML {
+		// Written as sanitizers would rewrite them
+		invisibleSpan = `<span class="` + SafeInvisibleClass + `">`
+		ellipsisSpan = `<span class="tco-ellipsis">`
+		displayUrlSpan = `<span class="js-display-url">`
+		nbsp = "\u00a0"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(ellipsisSpan)
+	buf.WriteString(precedingEllipsis)
+	buf.WriteString(invisibleSpan + nbsp + "</span></span>")
+	buf.WriteString(invisibleSpan + html.EscapeString(beforeDisplayUrl) + "</span>")
+	buf.WriteString(displayUrlSpan + html.EscapeString(displayUrlSansEllipses) + "</span>")
+	buf.WriteString(invisibleSpan + html.EscapeString(afterDisplayUrl) + "</span>")
+	buf.WriteString(ellipsisSpan + invisibleSpan + nbsp + "</span>")
+	buf.WriteString(followingEllipsis)
+	buf.WriteString("</span>")
+	return buf.String()
+}
+
+func (a *Autolinker) linkToHashtag(e *extract.TwitterEntity, text string, buf *bytes.Buffer) {
+	hashtag, _ := e.Hashtag()
+	a.linkToTextWithSymbol(e, symbolOf(e, text), hashtag, a.attributesFor(e, text), buf)
+}
+
+func (a *Autolinker) linkToCashtag(e *extract.TwitterEntity, text string, buf *bytes.Buffer) {
+	cashtag, _ := e.Cashtag()
+	a.linkToTextWithSymbol(e, symbolOf(e, text), cashtag, a.attributesFor(e, text), buf)
+}
+
+func (a *Autolinker) linkToMentionAndList(e *extract.TwitterEntity, text string, buf *bytes.Buffer) {
+	mention, _ := e.ScreenName()
+	if slug, ok := e.ListSlug(); ok {
+		mention += slug
+	} else if domain, ok := e.Domain(); ok {
+		mention += "@" + domain
+	}
+	a.linkToTextWithSymbol(e, symbolOf(e, text), mention, a.attributesFor(e, text), buf)
+}
+
+// Links an entity whose text is preceded by a symbol (@, #, or $),
+// wrapping the symbol and text in SymbolTag and TextWithSymbolTag if
+// set. The @ sign of mentions and lists is left outside of the link
+// unless UsernameIncludeSymbol is set
+func (a *Autolinker) linkToTextWithSymbol(e *extract.TwitterEntity, symbol, text string, attrs Attributes, buf *bytes.Buffer) {
+	taggedSymbol := symbol
+	taggedText := a.escape(text)
+	if !a.SafeHTML {
+		taggedSymbol = wrapInTag(a.SymbolTag, symbol)
+		taggedText = wrapInTag(a.TextWithSymbolTag, taggedText)
+	}
+
+	if a.UsernameIncludeSymbol || e.Type != extract.MENTION {
+		a.linkToText(e, taggedSymbol+taggedText, attrs, buf)
+	} else {
+		buf.WriteString(taggedSymbol)
+		a.linkToText(e, taggedText, attrs, buf)
+	}
+}
+
+func (a *Autolinker) linkToCustom(e *extract.TwitterEntity, text string, buf *bytes.Buffer) {
+	a.linkToText(e, a.escape(e.Text), a.attributesFor(e, text), buf)
+}
+
+func (a *Autolinker) linkToText(e *extract.TwitterEntity, linkText string, attrs Attributes, buf *bytes.Buffer) {
+	if a.SafeHTML {
+		attrs = safeAttributes(attrs)
+		if href, _ := attrs.Get("href"); !isSafeHref(href) {
+			buf.WriteString(linkText)
+			return
+		}
+	} else if a.LinkTextModifier != nil {
+		linkText = a.LinkTextModifier(e, linkText)
+	}
+
+	buf.WriteString("<a")
+	for _, attr := range attrs {
+		buf.WriteString(" ")
+		buf.WriteString(html.EscapeString(attr.Name))
+		buf.WriteString(`="`)
+		buf.WriteString(html.EscapeString(attr.Value))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">")
+	buf.WriteString(linkText)
+	buf.WriteString("</a>")
+}
+
+// Returns the text that precedes a link (including its symbol, if the
+// symbol is outside of the link) in text with right-to-left characters.
+// Links whose text contains right-to-left characters (e.g. hashtags in
+// Arabic or Hebrew) are marked as right-to-left, all others as
+// left-to-right
+func (a *Autolinker) bidiOpen(e *extract.TwitterEntity) string {
+	if a.BidiMode == BidiIsolate && !a.SafeHTML {
+		return "<bdi>"
+	}
+	return bidiMark(e)
+}
+
+// Returns the text that follows a link in text with right-to-left characters
+func (a *Autolinker) bidiClose(e *extract.TwitterEntity) string {
+	if a.BidiMode == BidiIsolate && !a.SafeHTML {
+		return "</bdi>"
+	}
+	return bidiMark(e)
+}
+
+func bidiMark(e *extract.TwitterEntity) string {
+	if rtlCharacters.MatchString(e.Text) {
+		return rightToLeftMark
+	}
+	return leftToRightMark
+}
+
+// Wraps text in the named tag, or returns text unchanged if tag is empty
+func wrapInTag(tag, text string) string {
+	if tag == "" {
+		return text
+	}
+	return "<" + tag + ">" + text + "</" + tag + ">"
+}
+
+// Escapes text taken from the input for inclusion in the output, unless
+// the input is already escaped
+func (a *Autolinker) escape(s string) string {
+	if a.TextIsEscaped {
+		if a.SafeHTML {
+			return html.EscapeString(html.UnescapeString(s))
+		}
+		return s
+	}
+	return html.EscapeString(s)
+}
+
+// Returns the unescaped form of text taken from the input. Attribute
+// values are always escaped when rendered, so values derived from
+// pre-escaped input must be unescaped first
+func (a *Autolinker) unescape(s string) string {
+	if a.TextIsEscaped {
+		return html.UnescapeString(s)
+	}
+	return s
+}
+
+// Returns the original symbol (@, #, $, or a full-width equivalent)
+// that precedes the entity in text
+func symbolOf(e *extract.TwitterEntity, text string) string {
+	for i := range text[e.ByteRange.Start:] {
+		if i > 0 {
+			return text[e.ByteRange.Start : e.ByteRange.Start+i]
+		}
+	}
+	return text[e.ByteRange.Start:]
+}
+
+// Returns an empty buffer for rendering output, from the buffer pool
+// unless NoBufferPool is set
+func (a *Autolinker) getBuffer() *bytes.Buffer {
+	return bufpool.Get(!a.NoBufferPool)
+}
+
+// Returns a buffer obtained from getBuffer to the pool
+func (a *Autolinker) putBuffer(buf *bytes.Buffer) {
+	if !a.NoBufferPool {
+		bufpool.Put(buf)
+	}
+}
@@ -0,0 +1,717 @@
+package autolink
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func ExampleAutoLink() {
+	fmt.Println(AutoLink("hello @jack #twitter"))
+	// Output:
+	// hello @<a class="tweet-url username" href="https://twitter.com/jack" rel="nofollow">jack</a> <a href="https://twitter.com/search?q=%23twitter" title="#twitter" class="tweet-url hashtag" rel="nofollow">#twitter</a>
+}
+
+func TestAutoLinkDefaults(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"text @username",
+			`text @<a class="tweet-url username" href="https://twitter.com/username" rel="nofollow">username</a>`},
+		{"text @username/list",
+			`text @<a class="tweet-url list-slug" href="https://twitter.com/username/list" rel="nofollow">username/list</a>`},
+		{"text #hashtag",
+			`text <a href="https://twitter.com/search?q=%23hashtag" title="#hashtag" class="tweet-url hashtag" rel="nofollow">#hashtag</a>`},
+		{"text $STOCK",
+			`text <a href="https://twitter.com/search?q=%24STOCK" title="$STOCK" class="tweet-url cashtag" rel="nofollow">$STOCK</a>`},
+		{"text http://example.com",
+			`text <a href="http://example.com" rel="nofollow">http://example.com</a>`},
+		{"full-width ＠username",
+			`full-width ＠<a class="tweet-url username" href="https://twitter.com/username" rel="nofollow">username</a>`},
+		{"שלום #hashtag",
+			`שלום <a href="https://twitter.com/search?q=%23hashtag" title="#hashtag" class="tweet-url hashtag rtl" rel="nofollow">#hashtag</a>`},
+		{"no entities here", "no entities here"},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(test.text)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkClasses(t *testing.T) {
+	a := NewAutolinker(
+		WithUrlClass("u"),
+		WithListClass("l"),
+		WithUsernameClass("m"),
+		WithHashtagClass("h"),
+		WithCashtagClass("c"),
+		WithNoFollow(false))
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"@user", `@<a class="m" href="https://twitter.com/user">user</a>`},
+		{"@user/list", `@<a class="l" href="https://twitter.com/user/list">user/list</a>`},
+		{"#tag", `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="h">#tag</a>`},
+		{"$TAG", `<a href="https://twitter.com/search?q=%24TAG" title="$TAG" class="c">$TAG</a>`},
+		{"http://example.com", `<a href="http://example.com" class="u">http://example.com</a>`},
+	}
+
+	for _, test := range tests {
+		actual := a.AutoLink(test.text)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkNoClasses(t *testing.T) {
+	a := NewAutolinker(WithUsernameClass(""), WithHashtagClass(""), WithNoFollow(false))
+	text := "@user #tag"
+	expected := `@<a href="https://twitter.com/user">user</a> <a href="https://twitter.com/search?q=%23tag" title="#tag">#tag</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkUrlBases(t *testing.T) {
+	a := NewAutolinker(
+		WithUsernameUrlBase("https://example.com/users/"),
+		WithListUrlBase("https://example.com/lists/"),
+		WithHashtagUrlBase("https://example.com/tags/"),
+		WithCashtagUrlBase("https://example.com/quote?symbol="),
+		WithNoFollow(false))
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"@user", `@<a class="tweet-url username" href="https://example.com/users/user">user</a>`},
+		{"@user/list", `@<a class="tweet-url list-slug" href="https://example.com/lists/user/list">user/list</a>`},
+		{"#tag", `<a href="https://example.com/tags/tag" title="#tag" class="tweet-url hashtag">#tag</a>`},
+		{"$TAG", `<a href="https://example.com/quote?symbol=TAG" title="$TAG" class="tweet-url cashtag">$TAG</a>`},
+	}
+
+	for _, test := range tests {
+		actual := a.AutoLink(test.text)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkRelAndTarget(t *testing.T) {
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil,
+			`<a href="http://example.com" rel="nofollow">http://example.com</a>`},
+		{[]Option{WithNoFollow(false)},
+			`<a href="http://example.com">http://example.com</a>`},
+		{[]Option{WithNoOpener(true)},
+			`<a href="http://example.com" rel="nofollow noopener">http://example.com</a>`},
+		{[]Option{WithNoFollow(false), WithNoOpener(true), WithUrlTarget("_blank")},
+			`<a href="http://example.com" target="_blank" rel="noopener">http://example.com</a>`},
+	}
+
+	text := "http://example.com"
+	for _, test := range tests {
+		actual := AutoLinkUrls(text, test.opts...)
+		if actual != test.expected {
+			t.Errorf("AutoLinkUrls returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkUsernameRel(t *testing.T) {
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{[]Option{WithUsernameRel("me")},
+			`@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow me">user</a> @<a class="tweet-url list-slug" href="https://twitter.com/user/list" rel="nofollow">user/list</a> <a href="http://example.com" rel="nofollow">http://example.com</a>`},
+		{[]Option{WithNoFollow(false), WithUsernameRel("me", "author")},
+			`@<a class="tweet-url username" href="https://twitter.com/user" rel="me author">user</a> @<a class="tweet-url list-slug" href="https://twitter.com/user/list">user/list</a> <a href="http://example.com">http://example.com</a>`},
+	}
+
+	text := "@user @user/list http://example.com"
+	for _, test := range tests {
+		actual := AutoLink(text, test.opts...)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkPerCallOptions(t *testing.T) {
+	a := NewAutolinker(WithUrlTarget("_blank"))
+	text := "http://example.com"
+
+	expected := `<a href="http://example.com" rel="nofollow">http://example.com</a>`
+	if actual := a.AutoLink(text, WithUrlTarget("")); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	// Per-call options must not modify the Autolinker
+	expected = `<a href="http://example.com" target="_blank" rel="nofollow">http://example.com</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkAttributeModifier(t *testing.T) {
+	modifier := func(e *extract.TwitterEntity, attrs *Attributes) {
+		switch e.Type {
+		case extract.HASH_TAG:
+			attrs.Set("data-tag", e.Text)
+			attrs.Set("class", "custom")
+		case extract.URL:
+			attrs.Del("rel")
+		}
+	}
+
+	text := "#tag http://example.com"
+	expected := `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="custom" rel="nofollow" data-tag="#tag">#tag</a> ` +
+		`<a href="http://example.com">http://example.com</a>`
+	if actual := AutoLink(text, WithLinkAttributeModifier(modifier)); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkTextModifier(t *testing.T) {
+	modifier := func(e *extract.TwitterEntity, text string) string {
+		if e.Type == extract.HASH_TAG {
+			return "<s>" + text + "</s>"
+		}
+		return text
+	}
+
+	text := "#tag @user"
+	expected := `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow"><s>#tag</s></a> ` +
+		`@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual := AutoLink(text, WithLinkTextModifier(modifier)); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkEntities(t *testing.T) {
+	payload := `{
+		"hashtags": [{"text": "tag", "indices": [6, 10]}],
+		"symbols": [],
+		"urls": [{
+			"url": "https://t.co/abc",
+			"display_url": "example.com/a…",
+			"expanded_url": "https://example.com/a/long/path",
+			"indices": [11, 27]
+		}],
+		"user_mentions": [{"screen_name": "jack", "name": "Jack", "id": 12, "id_str": "12", "indices": [0, 5]}],
+		"media": [{
+			"url": "https://t.co/pic",
+			"display_url": "pic.twitter.com/pic",
+			"expanded_url": "https://twitter.com/jack/status/1/photo/1",
+			"indices": [28, 44],
+			"type": "photo"
+		}]
+	}`
+
+	var entities Entities
+	if err := json.Unmarshal([]byte(payload), &entities); err != nil {
+		t.Fatalf("Error unmarshaling entities: %v", err)
+	}
+
+	text := "@jack #tag https://t.co/abc https://t.co/pic"
+	expected := `@<a class="tweet-url username" href="https://twitter.com/jack" rel="nofollow">jack</a> ` +
+		`<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow">#tag</a> ` +
+		`<a href="https://t.co/abc" title="https://example.com/a/long/path" data-expanded-url="https://example.com/a/long/path" rel="nofollow">` +
+		`<span class='tco-ellipsis'><span style='position:absolute;left:-9999px;'>&nbsp;</span></span>` +
+		`<span style='position:absolute;left:-9999px;'>https://</span>` +
+		`<span class='js-display-url'>example.com/a</span>` +
+		`<span style='position:absolute;left:-9999px;'>/long/path</span>` +
+		`<span class='tco-ellipsis'><span style='position:absolute;left:-9999px;'>&nbsp;</span>…</span></a> ` +
+		`<a href="https://t.co/pic" title="https://twitter.com/jack/status/1/photo/1" data-expanded-url="https://twitter.com/jack/status/1/photo/1" rel="nofollow">pic.twitter.com/pic</a>`
+	if actual := AutoLinkEntities(text, entities); actual != expected {
+		t.Errorf("AutoLinkEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkEntitiesInvalidIndices(t *testing.T) {
+	entities := Entities{
+		Hashtags: []HashtagEntity{
+			{Text: "tag", Indices: [2]int{2, 6}},
+			{Text: "overlap", Indices: [2]int{4, 8}},
+			{Text: "out of range", Indices: [2]int{7, 50}},
+		},
+	}
+
+	text := "é #tag"
+	expected := `é <a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow">#tag</a>`
+	if actual := AutoLinkEntities(text, entities); actual != expected {
+		t.Errorf("AutoLinkEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkDisplayUrl(t *testing.T) {
+	entities := Entities{
+		Urls: []UrlEntity{{
+			Url:         "http://t.co/0JG5Mcq",
+			DisplayUrl:  "…nname.com/foo",
+			ExpandedUrl: "http://longdomainname.com/foo",
+			Indices:     [2]int{3, 22},
+		}},
+	}
+
+	text := "hi http://t.co/0JG5Mcq"
+	expected := `hi <a href="http://t.co/0JG5Mcq" title="http://longdomainname.com/foo" data-expanded-url="http://longdomainname.com/foo" rel="nofollow">` +
+		`<span class='tco-ellipsis'>…<span class='x'>&nbsp;</span></span>` +
+		`<span class='x'>http://longdomai</span>` +
+		`<span class='js-display-url'>nname.com/foo</span>` +
+		`<span class='x'></span>` +
+		`<span class='tco-ellipsis'><span class='x'>&nbsp;</span></span></a>`
+	if actual := AutoLinkEntities(text, entities, WithInvisibleTagAttrs("class='x'")); actual != expected {
+		t.Errorf("AutoLinkEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkEscaping(t *testing.T) {
+	tests := []struct {
+		text     string
+		escaped  bool
+		expected string
+	}{
+		{`<script>alert("@user")</script>`, false,
+			`&lt;script&gt;alert(&#34;@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>&#34;)&lt;/script&gt;`},
+		{`x http://example.com/?a=1&b='2' & y`, false,
+			`x <a href="http://example.com/?a=1&amp;b=&#39;2" rel="nofollow">http://example.com/?a=1&amp;b=&#39;2</a>&#39; &amp; y`},
+		{`x http://example.com/?a=1&amp;b=2 &lt;b&gt;`, true,
+			`x <a href="http://example.com/?a=1&amp;b=2" rel="nofollow">http://example.com/?a=1&amp;b=2</a> &lt;b&gt;`},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(test.text, WithTextIsEscaped(test.escaped))
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkSymbolTags(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"@user", `<s>@</s><a class="tweet-url username" href="https://twitter.com/user" rel="nofollow"><b>user</b></a>`},
+		{"@user/list", `<s>@</s><a class="tweet-url list-slug" href="https://twitter.com/user/list" rel="nofollow"><b>user/list</b></a>`},
+		{"#tag", `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow"><s>#</s><b>tag</b></a>`},
+		{"$TAG", `<a href="https://twitter.com/search?q=%24TAG" title="$TAG" class="tweet-url cashtag" rel="nofollow"><s>$</s><b>TAG</b></a>`},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(test.text, WithSymbolTag("s"), WithTextWithSymbolTag("b"))
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkUsernameIncludeSymbol(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"@user", `<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">@user</a>`},
+		{"＠user/list", `<a class="tweet-url list-slug" href="https://twitter.com/user/list" rel="nofollow">＠user/list</a>`},
+		{"#tag", `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow">#tag</a>`},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(test.text, WithUsernameIncludeSymbol(true))
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkWithEntities(t *testing.T) {
+	text := "#tag @user http://example.com"
+	entities := extract.ExtractEntities(text)
+
+	// Reverse the entities and add an invalid one to ensure they are
+	// sorted and filtered
+	reversed := []*extract.TwitterEntity{extract.NewHashtagEntity("#toolong", 0, 8, "toolong")}
+	for i := len(entities) - 1; i >= 0; i-- {
+		reversed = append(reversed, entities[i])
+	}
+
+	expected := AutoLink(text)
+	if actual := AutoLinkWithEntities(text, entities); actual != expected {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+	if actual := AutoLinkWithEntities(text, reversed[1:]); actual != expected {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+	if actual := AutoLinkWithEntities("#tag", reversed); actual != AutoLink("#tag") {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for text [#tag]. Expected:[%s] Got:[%s]", AutoLink("#tag"), actual)
+	}
+	if reversed[1].Type != extract.URL {
+		t.Errorf("AutoLinkWithEntities modified its input")
+	}
+}
+
+func TestAutoLinkUrlsWithoutProtocol(t *testing.T) {
+	text := "visit example.com or www.example.org/a_b now"
+	entities := extract.ExtractEntities(text)
+
+	expected := `visit <a href="http://example.com" rel="nofollow">example.com</a> or ` +
+		`<a href="http://www.example.org/a_b" rel="nofollow">www.example.org/a_b</a> now`
+	if actual := AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+	if actual := AutoLinkUrls(text, WithSafeHTML(true)); actual != expected {
+		t.Errorf("AutoLinkUrls returned incorrect value for text [%s] in SafeHTML mode. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	// URLs with a protocol are linked as they are
+	text2 := "see https://example.com and HTTP://example.org"
+	expected = `see <a href="https://example.com" rel="nofollow">https://example.com</a> and <a href="HTTP://example.org" rel="nofollow">HTTP://example.org</a>`
+	if actual := AutoLink(text2); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text2, expected, actual)
+	}
+
+	expected = `visit [example\.com](http://example.com) or [www\.example\.org/a\_b](http://www.example.org/a_b) now`
+	if actual := RenderMarkdown(text, entities); actual != expected {
+		t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	expected = `visit <http://example.com|example.com> or <http://www.example.org/a_b|www.example.org/a_b> now`
+	if actual := RenderSlack(text, entities); actual != expected {
+		t.Errorf("RenderSlack returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	segments := Segments(text, entities)
+	if len(segments) != 5 || segments[1].Href != "http://example.com" || segments[3].Href != "http://www.example.org/a_b" {
+		t.Errorf("Segments returned incorrect hrefs for text [%s]. Got:%+v", text, segments)
+	} else if href, _ := segments[1].Attributes.Get("href"); href != "http://example.com" {
+		t.Errorf("Segments returned incorrect href attribute for text [%s]. Expected:[http://example.com] Got:[%s]", text, href)
+	}
+}
+
+func TestAutoLinkMediaUrls(t *testing.T) {
+	text := "look http://example.com pic.twitter.com/abc123 "
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{nil,
+			`look <a href="http://example.com" rel="nofollow">http://example.com</a> <a href="http://pic.twitter.com/abc123" rel="nofollow">pic.twitter.com/abc123</a> `},
+		{[]Option{WithMediaClass("media")},
+			`look <a href="http://example.com" rel="nofollow">http://example.com</a> <a href="http://pic.twitter.com/abc123" class="media" rel="nofollow">pic.twitter.com/abc123</a> `},
+		{[]Option{WithMediaUrlMode(MediaUrlSkip)},
+			`look <a href="http://example.com" rel="nofollow">http://example.com</a> pic.twitter.com/abc123 `},
+		{[]Option{WithMediaUrlMode(MediaUrlRemove)},
+			`look <a href="http://example.com" rel="nofollow">http://example.com</a>`},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(text, test.opts...)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, test.expected, actual)
+		}
+	}
+
+	// Media entities from the API are detected by their display URL
+	text = "photo https://t.co/pic done"
+	entities := Entities{
+		Media: []MediaEntity{{UrlEntity: UrlEntity{
+			Url:         "https://t.co/pic",
+			DisplayUrl:  "pic.twitter.com/pic",
+			ExpandedUrl: "https://twitter.com/jack/status/1/photo/1",
+			Indices:     [2]int{6, 22},
+		}}},
+	}
+	expected := "photo  done"
+	if actual := AutoLinkEntities(text, entities, WithMediaUrlMode(MediaUrlRemove)); actual != expected {
+		t.Errorf("AutoLinkEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkBidi(t *testing.T) {
+	tests := []struct {
+		text     string
+		mode     BidiMode
+		expected string
+	}{
+		{"שלום @user", BidiMarks,
+			"שלום \u200e@<a class=\"tweet-url username\" href=\"https://twitter.com/user\" rel=\"nofollow\">user</a>\u200e"},
+		{"שלום #שלום", BidiMarks,
+			"שלום \u200f<a href=\"https://twitter.com/search?q=%23שלום\" title=\"#שלום\" class=\"tweet-url hashtag rtl\" rel=\"nofollow\">#שלום</a>\u200f"},
+		{"שלום http://example.com", BidiIsolate,
+			"שלום <bdi><a href=\"http://example.com\" rel=\"nofollow\">http://example.com</a></bdi>"},
+		{"hello @user", BidiIsolate,
+			"hello @<a class=\"tweet-url username\" href=\"https://twitter.com/user\" rel=\"nofollow\">user</a>"},
+		{"שלום @user", BidiNone,
+			"שלום @<a class=\"tweet-url username\" href=\"https://twitter.com/user\" rel=\"nofollow\">user</a>"},
+	}
+
+	for _, test := range tests {
+		actual := AutoLink(test.text, WithBidiMode(test.mode))
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%q] Got:[%q]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkEntityAttributes(t *testing.T) {
+	a := NewAutolinker(
+		WithEntityAttributes(extract.HASH_TAG, map[string]string{"data-query": "tag", "class": "h"}),
+		WithEntityAttributes(extract.URL, map[string]string{"referrerpolicy": "no-referrer"}))
+
+	text := "#tag http://example.com @user"
+	expected := `<a href="https://twitter.com/search?q=%23tag" title="#tag" class="h" rel="nofollow" data-query="tag">#tag</a> ` +
+		`<a href="http://example.com" rel="nofollow" referrerpolicy="no-referrer">http://example.com</a> ` +
+		`@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	// Per-call options must not modify the Autolinker's attributes
+	a.AutoLink(text, WithEntityAttributes(extract.MENTION, map[string]string{"data-x": "y"}))
+	if _, ok := a.EntityAttributes[extract.MENTION]; ok {
+		t.Errorf("WithEntityAttributes modified the Autolinker's attributes")
+	}
+}
+
+func TestAutoLinkListUrlTemplate(t *testing.T) {
+	a := NewAutolinker(
+		WithListUrlTemplate("https://example.com/{user}/lists/{slug}"),
+		WithListClass("list"),
+		WithUsernameUrlBase("https://example.com/@"))
+
+	text := "@user/my-list @user"
+	expected := `@<a class="list" href="https://example.com/user/lists/my-list" rel="nofollow">user/my-list</a> ` +
+		`@<a class="tweet-url username" href="https://example.com/@user" rel="nofollow">user</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkMentionResolver(t *testing.T) {
+	a := NewAutolinker(WithMentionResolver(FediverseResolver{Domain: "mastodon.social"}))
+
+	text := "@user @user/list"
+	expected := `@<a class="tweet-url username" href="https://mastodon.social/@user" rel="nofollow">user</a> ` +
+		`@<a class="tweet-url list-slug" href="https://twitter.com/user/list" rel="nofollow">user/list</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	expected = "[@user](https://mastodon.social/@user) [@user/list](https://twitter.com/user/list)"
+	if actual := RenderMarkdown(text, extract.ExtractEntities(text), WithMentionResolver(FediverseResolver{Domain: "mastodon.social"})); actual != expected {
+		t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	if actual := (FediverseResolver{Domain: "mastodon.social"}).ProfileUrl("user", "example.org"); actual != "https://example.org/@user" {
+		t.Errorf("ProfileUrl returned incorrect value for a mention naming a server. Expected:[https://example.org/@user] Got:[%s]", actual)
+	}
+
+	// Without a Domain, mentions that do not name a server are not linked
+	if actual := (FediverseResolver{}).ProfileUrl("user", ""); actual != "" {
+		t.Errorf("ProfileUrl returned incorrect value for a mention without a server. Expected:[] Got:[%s]", actual)
+	}
+	text = "hi @user"
+	expected = "hi @user"
+	if actual := AutoLink(text, WithMentionResolver(FediverseResolver{})); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkFederatedMentions(t *testing.T) {
+	text := "hi @Gargron@mastodon.social and @user #fediverse"
+
+	// Not linked by default, like the reference implementations
+	expected := `hi @Gargron@mastodon.social and @<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a> ` +
+		`<a href="https://twitter.com/search?q=%23fediverse" title="#fediverse" class="tweet-url hashtag" rel="nofollow">#fediverse</a>`
+	if actual := AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	a := NewAutolinker(WithFederatedMentions(true), WithMentionResolver(FediverseResolver{Domain: "example.social"}))
+	expected = `hi @<a class="tweet-url username" href="https://mastodon.social/@Gargron" rel="nofollow">Gargron@mastodon.social</a> and ` +
+		`@<a class="tweet-url username" href="https://example.social/@user" rel="nofollow">user</a> ` +
+		`<a href="https://twitter.com/search?q=%23fediverse" title="#fediverse" class="tweet-url hashtag" rel="nofollow">#fediverse</a>`
+	if actual := a.AutoLink(text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	expected = `hi @<a class="tweet-url username" href="https://mastodon.social/@Gargron" rel="nofollow">Gargron@mastodon.social</a> and ` +
+		`@<a class="tweet-url username" href="https://example.social/@user" rel="nofollow">user</a> #fediverse`
+	if actual := a.AutoLinkUsernamesAndLists(text); actual != expected {
+		t.Errorf("AutoLinkUsernamesAndLists returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	// Without a resolver, federated mentions link to their servers and
+	// other mentions to UsernameUrlBase
+	text = "@Gargron@mastodon.social @user"
+	expected = `@<a class="tweet-url username" href="https://mastodon.social/@Gargron" rel="nofollow">Gargron@mastodon.social</a> ` +
+		`@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual := AutoLink(text, WithFederatedMentions(true)); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	// The resolver is given the domain of each mention
+	var domains []string
+	resolver := resolverFunc(func(screenName, domain string) string {
+		domains = append(domains, domain)
+		return ""
+	})
+	expected = text
+	if actual := AutoLink(text, WithFederatedMentions(true), WithMentionResolver(resolver)); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+	if len(domains) == 0 || domains[0] != "mastodon.social" || domains[len(domains)-1] != "" {
+		t.Errorf("AutoLink passed incorrect domains to the MentionResolver for text [%s]. Got:%q", text, domains)
+	}
+
+	x := extract.Extractor{FederatedMentions: true}
+	expected = `[@Gargron@mastodon\.social](https://mastodon.social/@Gargron) [@user](https://twitter.com/user)`
+	if actual := RenderMarkdown(text, x.ExtractEntities(text)); actual != expected {
+		t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+// Adapts a function to the MentionResolver interface
+type resolverFunc func(screenName, domain string) string
+
+func (f resolverFunc) ProfileUrl(screenName, domain string) string {
+	return f(screenName, domain)
+}
+
+func TestAutoLinkCustomEntities(t *testing.T) {
+	var x extract.Extractor
+	x.Register("issue", extract.RegexpMatcher(regexp.MustCompile(`(?:^|\s)(#\d+)\b`)))
+	x.Register("code", extract.RegexpMatcher(regexp.MustCompile(`\bGO-\d+\b`)))
+	issueUrl := func(e *extract.TwitterEntity) string {
+		if kind, _ := e.Kind(); kind == "issue" {
+			return "https://example.com/issues/" + strings.TrimPrefix(e.Text, "#")
+		}
+		return ""
+	}
+
+	text := "fixes #12 & GO-3 for @user"
+	entities := x.ExtractEntities(text)
+	a := NewAutolinker(WithCustomUrl(issueUrl), WithEntityAttributes(extract.CUSTOM, map[string]string{"class": "issue"}))
+
+	expected := `fixes <a href="https://example.com/issues/12" rel="nofollow" class="issue">#12</a> &amp; GO-3 for ` +
+		`@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual := a.AutoLinkWithEntities(text, entities); actual != expected {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	expected = `fixes [\#12](https://example.com/issues/12) & GO\-3 for [@user](https://twitter.com/user)`
+	if actual := a.RenderMarkdown(text, entities); actual != expected {
+		t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	expected = `fixes <https://example.com/issues/12|#12> &amp; GO-3 for <https://twitter.com/user|@user>`
+	if actual := a.RenderSlack(text, entities); actual != expected {
+		t.Errorf("RenderSlack returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+
+	var actual []string
+	for _, s := range a.Segments(text, entities) {
+		actual = append(actual, s.Text+"|"+s.Href)
+	}
+	expectedSegments := []string{"fixes |", "#12|https://example.com/issues/12", " & GO-3 for |", "@user|https://twitter.com/user"}
+	if strings.Join(actual, "\n") != strings.Join(expectedSegments, "\n") {
+		t.Errorf("Segments returned incorrect value for text [%s]. Expected:%q Got:%q", text, expectedSegments, actual)
+	}
+
+	// Without CustomUrl, custom entities are plain text
+	expected = `fixes #12 &amp; GO-3 for @<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual := AutoLinkWithEntities(text, entities); actual != expected {
+		t.Errorf("AutoLinkWithEntities returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestAutoLinkLabels(t *testing.T) {
+	labels := Labels(map[extract.EntityType]string{
+		extract.HASH_TAG: "Buscar {text}",
+		extract.MENTION:  "Perfil de {text}",
+	})
+
+	tests := []struct {
+		opts     []Option
+		expected string
+	}{
+		{[]Option{WithAriaLabel(labels)},
+			`@<a class="tweet-url username" href="https://twitter.com/user" aria-label="Perfil de @user">user</a> <a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" aria-label="Buscar #tag">#tag</a> <a href="http://example.com">http://example.com</a>`},
+		{[]Option{WithTitle(labels)},
+			`@<a class="tweet-url username" href="https://twitter.com/user" title="Perfil de @user">user</a> <a href="https://twitter.com/search?q=%23tag" title="Buscar #tag" class="tweet-url hashtag">#tag</a> <a href="http://example.com">http://example.com</a>`},
+		{[]Option{WithTitle(func(e *extract.TwitterEntity) string { return "" })},
+			`@<a class="tweet-url username" href="https://twitter.com/user">user</a> <a href="https://twitter.com/search?q=%23tag" class="tweet-url hashtag">#tag</a> <a href="http://example.com">http://example.com</a>`},
+	}
+
+	text := "@user #tag http://example.com"
+	for _, test := range tests {
+		actual := AutoLink(text, append(test.opts, WithNoFollow(false))...)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkBufferPool(t *testing.T) {
+	texts := []string{
+		"hello @jack #twitter",
+		"a url http://example.com and $TWTR",
+		strings.Repeat("a long tweet with @mentions and #hashtags ", 50),
+		"",
+	}
+	pooled := NewAutolinker()
+	unpooled := NewAutolinker(WithBufferPool(false))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				for _, text := range texts {
+					expected := unpooled.AutoLink(text)
+					if actual := pooled.AutoLink(text); actual != expected {
+						t.Errorf("AutoLink returned incorrect value with pooled buffers for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+					}
+					entities := extract.ExtractEntities(text)
+					if actual, expected := pooled.RenderMarkdown(text, entities), unpooled.RenderMarkdown(text, entities); actual != expected {
+						t.Errorf("RenderMarkdown returned incorrect value with pooled buffers for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkRenderMarkdown(b *testing.B) {
+	text := strings.Repeat("hello @jack #twitter http://example.com $TWTR ", 3)
+	entities := extract.ExtractEntities(text)
+	for _, pool := range []bool{true, false} {
+		a := NewAutolinker(WithBufferPool(pool))
+		b.Run(fmt.Sprintf("pool=%t", pool), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				a.RenderMarkdown(text, entities)
+			}
+		})
+	}
+}
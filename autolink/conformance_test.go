@@ -0,0 +1,37 @@
+package autolink
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Runs autolink.yml against an Autolinker. Like the reference
+// implementations, the tests are run without rel="nofollow"
+type conformanceAutolinker struct {
+	a *Autolinker
+}
+
+func (c conformanceAutolinker) AutoLinkUsernamesAndLists(text string) string {
+	return c.a.AutoLinkUsernamesAndLists(text)
+}
+
+func (c conformanceAutolinker) AutoLinkHashtags(text string) string {
+	return c.a.AutoLinkHashtags(text)
+}
+
+func (c conformanceAutolinker) AutoLinkCashtags(text string) string {
+	return c.a.AutoLinkCashtags(text)
+}
+
+func (c conformanceAutolinker) AutoLinkUrls(text string) string {
+	return c.a.AutoLinkUrls(text)
+}
+
+func (c conformanceAutolinker) AutoLink(text string) string {
+	return c.a.AutoLink(text)
+}
+
+func TestAutoLinkConformance(t *testing.T) {
+	conformance.RunAutolink(t, conformanceAutolinker{NewAutolinker(WithNoFollow(false))})
+}
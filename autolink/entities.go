@@ -0,0 +1,173 @@
+package autolink
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// Entities as they appear in the "entities" object of a tweet returned by
+// the Twitter API. A payload can be decoded directly into this type using
+// encoding/json.
+//
+// Indices are offsets into the tweet text in characters (runes), as
+// returned by the API.
+type Entities struct {
+	Hashtags     []HashtagEntity     `json:"hashtags"`
+	Symbols      []SymbolEntity      `json:"symbols"`
+	Urls         []UrlEntity         `json:"urls"`
+	UserMentions []UserMentionEntity `json:"user_mentions"`
+	Media        []MediaEntity       `json:"media"`
+}
+
+// A hashtag entity from an API payload. Text does not include the leading #
+type HashtagEntity struct {
+	Text    string `json:"text"`
+	Indices [2]int `json:"indices"`
+}
+
+// A cashtag (symbol) entity from an API payload. Text does not include
+// the leading $
+type SymbolEntity struct {
+	Text    string `json:"text"`
+	Indices [2]int `json:"indices"`
+}
+
+// A URL entity from an API payload
+type UrlEntity struct {
+	Url         string `json:"url"`          // The t.co URL that appears in the text
+	DisplayUrl  string `json:"display_url"`  // The URL to display to users
+	ExpandedUrl string `json:"expanded_url"` // The fully expanded URL
+	Indices     [2]int `json:"indices"`
+}
+
+// A user mention entity from an API payload
+type UserMentionEntity struct {
+	ScreenName string `json:"screen_name"`
+	Name       string `json:"name"`
+	Id         int64  `json:"id"`
+	IdStr      string `json:"id_str"`
+	Indices    [2]int `json:"indices"`
+}
+
+// A media entity from an API payload. Media entities are linked the same
+// way as URL entities
+type MediaEntity struct {
+	UrlEntity
+	Id            int64  `json:"id"`
+	IdStr         string `json:"id_str"`
+	MediaUrl      string `json:"media_url"`
+	MediaUrlHttps string `json:"media_url_https"`
+	Type          string `json:"type"`
+}
+
+// Auto-link the given text using entities supplied by the Twitter API
+// instead of extracting them, using the default settings overridden by
+// any supplied options
+func AutoLinkEntities(text string, entities Entities, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkEntities(text, entities)
+}
+
+// Auto-link the given text using entities supplied by the Twitter API
+// instead of extracting them. URLs and media are linked to their t.co URL
+// using the display URL as the visible link text. The expanded URL is
+// included in the title and data-expanded-url attributes, and, where the
+// display URL is part of the expanded URL, in invisible spans so that
+// copying the link text yields the full URL. Entities whose indices are
+// out of range or overlap a preceding entity are ignored.
+func (a *Autolinker) AutoLinkEntities(text string, entities Entities, opts ...Option) string {
+	return a.with(opts).autoLinkEntities(text, entities.TwitterEntities(text))
+}
+
+// Converts API entities for text to extract entities, sorted by their
+// position in text, so that they can be used with the functions that take
+// extracted entities. Entities whose indices are out of range or overlap
+// a preceding entity are dropped.
+func (entities Entities) TwitterEntities(text string) []*extract.TwitterEntity {
+	// Map rune offsets to byte offsets, including the offset of the end of text
+	offsets := make([]int, 0, utf8.RuneCountInString(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	byteRange := func(indices [2]int) (int, int, bool) {
+		if indices[0] < 0 || indices[0] >= indices[1] || indices[1] >= len(offsets) {
+			return 0, 0, false
+		}
+		return offsets[indices[0]], offsets[indices[1]], true
+	}
+
+	var result []*extract.TwitterEntity
+	for _, h := range entities.Hashtags {
+		if start, stop, ok := byteRange(h.Indices); ok {
+			result = append(result, extract.NewHashtagEntity(text, start, stop, h.Text))
+		}
+	}
+	for _, s := range entities.Symbols {
+		if start, stop, ok := byteRange(s.Indices); ok {
+			result = append(result, extract.NewCashtagEntity(text, start, stop, s.Text))
+		}
+	}
+	for _, u := range entities.Urls {
+		if start, stop, ok := byteRange(u.Indices); ok {
+			result = append(result, extract.NewUrlEntity(text, start, stop, u.DisplayUrl, u.ExpandedUrl))
+		}
+	}
+	for _, m := range entities.Media {
+		if start, stop, ok := byteRange(m.Indices); ok {
+			result = append(result, extract.NewUrlEntity(text, start, stop, m.DisplayUrl, m.ExpandedUrl))
+		}
+	}
+	for _, m := range entities.UserMentions {
+		if start, stop, ok := byteRange(m.Indices); ok {
+			result = append(result, extract.NewMentionEntity(text, start, stop, m.ScreenName, ""))
+		}
+	}
+
+	return sortEntities(result, len(text))
+}
+
+// Auto-link the given text using previously extracted entities (e.g. from
+// extract.ExtractEntities or from storage) instead of extracting them
+// again, using the default settings overridden by any supplied options
+func AutoLinkWithEntities(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	return NewAutolinker(opts...).AutoLinkWithEntities(text, entities)
+}
+
+// Auto-link the given text using previously extracted entities (e.g. from
+// extract.ExtractEntities or from storage) instead of extracting them
+// again. The entities need not be sorted; entities whose byte ranges are
+// out of range or overlap a preceding entity are ignored. The supplied
+// slice is not modified.
+func (a *Autolinker) AutoLinkWithEntities(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	sorted := make([]*extract.TwitterEntity, len(entities))
+	copy(sorted, entities)
+	return a.with(opts).autoLinkEntities(text, sortEntities(sorted, len(text)))
+}
+
+// Sorts entities by their position in the text, and removes entities
+// whose byte ranges are invalid for a text of length textLen or that
+// overlap a preceding entity. The slice is modified in place.
+func sortEntities(entities []*extract.TwitterEntity, textLen int) []*extract.TwitterEntity {
+	sort.Stable(byStart(entities))
+
+	var prevStop int
+	n := 0
+	for _, e := range entities {
+		r := e.ByteRange
+		if r.Start >= prevStop && r.Start <= r.Stop && r.Stop <= textLen {
+			entities[n] = e
+			n++
+			prevStop = r.Stop
+		}
+	}
+	return entities[:n]
+}
+
+type byStart []*extract.TwitterEntity
+
+func (e byStart) Len() int           { return len(e) }
+func (e byStart) Less(i, j int) bool { return e[i].ByteRange.Start < e[j].ByteRange.Start }
+func (e byStart) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
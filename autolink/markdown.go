@@ -0,0 +1,66 @@
+package autolink
+
+import (
+	"strings"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+var (
+	// Escapes characters that have special meaning in Markdown text
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `{`, `\{`, `}`, `\}`,
+		`[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`, `#`, `\#`, `+`, `\+`,
+		`-`, `\-`, `.`, `\.`, `!`, `\!`, `|`, `\|`, `<`, `\<`, `>`, `\>`,
+		`~`, `\~`)
+
+	// Escapes characters that would terminate a Markdown link destination
+	markdownUrlEscaper = strings.NewReplacer(
+		`(`, `%28`, `)`, `%29`, ` `, `%20`, `<`, `%3C`, `>`, `%3E`)
+)
+
+// Renders the given text as Markdown using the default settings, converting
+// each of the supplied entities into a [text](url) link. All other text is
+// escaped so that it is displayed literally. The entities must be sorted by
+// their position within text and must not overlap, as returned by
+// extract.ExtractEntities.
+func RenderMarkdown(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	return NewAutolinker(opts...).RenderMarkdown(text, entities)
+}
+
+// Renders the given text as Markdown, converting each of the supplied
+// entities into a [text](url) link that uses the Autolinker's base URLs.
+// All other text is escaped so that it is displayed literally. The
+// entities must be sorted by their position within text and must not
+// overlap, as returned by extract.ExtractEntities.
+func (a *Autolinker) RenderMarkdown(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	a = a.with(opts)
+
+	buf := a.getBuffer()
+	defer a.putBuffer(buf)
+	offset := 0
+	for _, e := range entities {
+		buf.WriteString(markdownEscaper.Replace(text[offset:e.ByteRange.Start]))
+		offset = e.ByteRange.Stop
+		if a.isUnlinked(e) {
+			buf.WriteString(markdownEscaper.Replace(e.Text))
+			continue
+		}
+		buf.WriteString("[")
+		buf.WriteString(markdownEscaper.Replace(displayText(e)))
+		buf.WriteString("](")
+		buf.WriteString(markdownUrlEscaper.Replace(a.hrefFor(e)))
+		buf.WriteString(")")
+	}
+	buf.WriteString(markdownEscaper.Replace(text[offset:]))
+	return buf.String()
+}
+
+// Returns the text to display for an entity in plain-text renderers: the
+// display URL for URLs that have one, and the entity text otherwise
+func displayText(e *extract.TwitterEntity) string {
+	if displayUrl, ok := e.DisplayUrl(); ok {
+		return displayUrl
+	}
+	return e.Text
+}
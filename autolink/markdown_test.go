@@ -0,0 +1,38 @@
+package autolink
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"hello @user_name #tag $TAG",
+			`hello [@user\_name](https://twitter.com/user_name) [\#tag](https://twitter.com/search?q=%23tag) [$TAG](https://twitter.com/search?q=%24TAG)`},
+		{"list @user/my-list",
+			`list [@user/my\-list](https://twitter.com/user/my-list)`},
+		{"*not bold* see http://example.com/a_(b)",
+			`\*not bold\* see [http://example\.com/a\_\(b\)](http://example.com/a_%28b%29)`},
+		{"no entities [here]", `no entities \[here\]`},
+	}
+
+	for _, test := range tests {
+		actual := RenderMarkdown(test.text, extract.ExtractEntities(test.text))
+		if actual != test.expected {
+			t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestRenderMarkdownDisplayUrl(t *testing.T) {
+	text := "see https://t.co/abc"
+	entities := []*extract.TwitterEntity{extract.NewUrlEntity(text, 4, 20, "example.com/a…", "https://example.com/a/b")}
+	expected := `see [example\.com/a…](https://t.co/abc)`
+	if actual := RenderMarkdown(text, entities); actual != expected {
+		t.Errorf("RenderMarkdown returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
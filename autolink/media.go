@@ -0,0 +1,31 @@
+package autolink
+
+import (
+	"regexp"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+var (
+	mediaUrl          = regexp.MustCompile(`(?i)\A(?:https?://)?(?:www\.)?pic\.(?:twitter|x)\.com/`)
+	mediaPermalinkUrl = regexp.MustCompile(`(?i)\A(?:https?://)?(?:www\.|mobile\.)?(?:twitter|x)\.com/[^/]+/status/[0-9]+/(?:photo|video)/[0-9]+\z`)
+)
+
+// Returns true if the given URL entity refers to media attached to a
+// tweet. The URL itself, its display URL, and its expanded URL are checked
+func isMediaUrl(e *extract.TwitterEntity) bool {
+	urls := []string{e.Text}
+	if displayUrl, ok := e.DisplayUrl(); ok {
+		urls = append(urls, displayUrl)
+	}
+	if expandedUrl, ok := e.ExpandedUrl(); ok {
+		urls = append(urls, expandedUrl)
+	}
+
+	for _, url := range urls {
+		if mediaUrl.MatchString(url) || mediaPermalinkUrl.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package autolink
+
+import (
+	"net/url"
+	"strings"
+)
+
+// The attributes allowed on links in SafeHTML mode
+var safeLinkAttributes = []string{"href", "title", "class", "rel"}
+
+// Returns the elements that may appear in the HTML output of an
+// Autolinker in SafeHTML mode, with the attributes allowed on each. Spans
+// only have one of the classes "tco-ellipsis", "js-display-url", or
+// SafeInvisibleClass. Sanitizers can be configured to match, e.g. with
+// bluemonday:
+//
+//	p := bluemonday.NewPolicy()
+//	p.AllowStandardURLs()
+//	for element, attrs := range autolink.SafeHTMLAttributes() {
+//		p.AllowAttrs(attrs...).OnElements(element)
+//	}
+func SafeHTMLAttributes() map[string][]string {
+	return map[string][]string{
+		"a":    append([]string(nil), safeLinkAttributes...),
+		"span": {"class"},
+	}
+}
+
+// Returns the attributes of a link that are allowed in SafeHTML mode,
+// keeping the first of any with the same name
+func safeAttributes(attrs Attributes) Attributes {
+	var result Attributes
+	for _, attr := range attrs {
+		if _, ok := result.Get(attr.Name); ok {
+			continue
+		}
+		for _, name := range safeLinkAttributes {
+			if attr.Name == name {
+				result = append(result, attr)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Reports whether href is a relative, http, or https URL, which
+// sanitizers allow in links
+func isSafeHref(href string) bool {
+	if href == "" {
+		return false
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package autolink
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// Matches issue references such as #123
+var regexpIssue = regexp.MustCompile(`(?:^|\s)(#\d+)\b`)
+
+// Returns a sanitizer allowing only SafeHTMLAttributes
+func safePolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	for element, attrs := range SafeHTMLAttributes() {
+		p.AllowAttrs(attrs...).OnElements(element)
+	}
+	return p
+}
+
+// Reports an error if the output contains elements or attributes other
+// than those in SafeHTMLAttributes, or spans with other classes
+func checkSafeElements(t *testing.T, output string) {
+	allowed := SafeHTMLAttributes()
+	spanClasses := map[string]bool{"tco-ellipsis": true, "js-display-url": true, SafeInvisibleClass: true}
+	z := html.NewTokenizer(strings.NewReader(output))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			attrs, ok := allowed[token.Data]
+			if !ok {
+				t.Errorf("Output contains element <%s>: %s", token.Data, output)
+				continue
+			}
+			for _, attr := range token.Attr {
+				if !strings.Contains(" "+strings.Join(attrs, " ")+" ", " "+attr.Key+" ") {
+					t.Errorf("Output contains attribute %s on <%s>: %s", attr.Key, token.Data, output)
+				}
+				if token.Data == "span" && !spanClasses[attr.Val] {
+					t.Errorf("Output contains span with class [%s]: %s", attr.Val, output)
+				}
+			}
+		}
+	}
+}
+
+func TestSafeHTML(t *testing.T) {
+	// Options that would otherwise produce other elements and attributes
+	hostile := []Option{
+		WithSymbolTag("s"),
+		WithTextWithSymbolTag("b"),
+		WithInvisibleTagAttrs("style='display:none' onclick='x()'"),
+		WithBidiMode(BidiIsolate),
+		WithUrlTarget("_blank"),
+		WithAriaLabel(func(e *extract.TwitterEntity) string { return "label" }),
+		WithEntityAttributes(extract.HASH_TAG, map[string]string{"onclick": "x()", "class": "tag", "style": "color:red"}),
+		WithLinkAttributeModifier(func(e *extract.TwitterEntity, attrs *Attributes) {
+			attrs.Set("data-x", "y")
+			if _, ok := e.Cashtag(); ok {
+				attrs.Set("href", "javascript:alert(1)")
+			}
+			*attrs = append(*attrs, Attribute{"href", "javascript:alert(2)"})
+		}),
+		WithLinkTextModifier(func(e *extract.TwitterEntity, text string) string { return "<img src=x>" + text }),
+		WithCustomUrl(func(e *extract.TwitterEntity) string { return "javascript:alert(3)" }),
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"hello @user #tag $TWTR <script>", `hello @<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a> ` +
+			`<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tag" rel="nofollow">#tag</a> $TWTR &lt;script&gt;`},
+		{"see http://example.com/a?b=c&d=\"e\"", `see <a href="http://example.com/a?b=c&amp;d=" rel="nofollow">http://example.com/a?b=c&amp;d=</a>&#34;e&#34;`},
+		{"שלום @user", "שלום ‎@<a class=\"tweet-url username\" href=\"https://twitter.com/user\" rel=\"nofollow\">user</a>‎"},
+	}
+	for _, test := range tests {
+		actual := AutoLink(test.text, append(hostile, WithSafeHTML(true))...)
+		if actual != test.expected {
+			t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+		checkSafeElements(t, actual)
+		if sanitized := safePolicy().Sanitize(actual); sanitized != actual {
+			t.Errorf("AutoLink returned output changed by the sanitizer for text [%s]. Expected:[%s] Got:[%s]", test.text, actual, sanitized)
+		}
+	}
+}
+
+func TestSafeHTMLSanitizers(t *testing.T) {
+	p := safePolicy()
+	entities := Entities{
+		Urls: []UrlEntity{{
+			Url:         "https://t.co/abc",
+			DisplayUrl:  "example.com/a/b…",
+			ExpandedUrl: "https://example.com/a/b/c?d=\"e\"&f=<g>",
+			Indices:     [2]int{6, 22},
+		}},
+	}
+	var issues extract.Extractor
+	issues.Register("issue", extract.RegexpMatcher(regexpIssue))
+
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"AutoLink", AutoLink("hi @user/list @user #tag $TWTR http://x.com/<b> & é <i>", WithSafeHTML(true))},
+		{"AutoLink with pre-escaped text", AutoLink("&lt;b&gt; <script> &amp; #tag &quot;", WithSafeHTML(true), WithTextIsEscaped(true))},
+		{"AutoLink with symbol tags", AutoLink("#tag @user $TWTR", WithSafeHTML(true), WithSymbolTag("s"), WithTextWithSymbolTag("b"))},
+		{"AutoLink with bidi isolation", AutoLink("مرحبا @user #وسم", WithSafeHTML(true), WithBidiMode(BidiIsolate))},
+		{"AutoLinkEntities", AutoLinkEntities("check https://t.co/abc now", entities, WithSafeHTML(true))},
+		{"AutoLinkEntities with attributes", AutoLinkEntities("check https://t.co/abc now", entities, WithSafeHTML(true), WithInvisibleTagAttrs("style='x'"), WithUrlTarget("_blank"))},
+		{"AutoLinkWithEntities with custom entities", AutoLinkWithEntities("fixes #12", issues.ExtractEntities("fixes #12"), WithSafeHTML(true),
+			WithCustomUrl(func(e *extract.TwitterEntity) string { return "/issues/" + strings.TrimPrefix(e.Text, "#") }))},
+	}
+	for _, test := range tests {
+		if !strings.Contains(test.output, "<a ") {
+			t.Errorf("%s returned no links: %s", test.name, test.output)
+		}
+		checkSafeElements(t, test.output)
+		if sanitized := p.Sanitize(test.output); sanitized != test.output {
+			t.Errorf("%s returned output changed by the sanitizer. Expected:[%s] Got:[%s]", test.name, test.output, sanitized)
+		}
+	}
+
+	// Without SafeHTML, the sanitizer changes the output
+	output := AutoLinkEntities("check https://t.co/abc now", entities)
+	if p.Sanitize(output) == output {
+		t.Errorf("AutoLinkEntities returned output unchanged by the sanitizer without SafeHTML: %s", output)
+	}
+}
+
+func TestSafeHTMLUnsafeHrefs(t *testing.T) {
+	tests := []struct {
+		href     string
+		expected bool
+	}{
+		{"https://example.com/", true},
+		{"http://example.com/", true},
+		{"HTTPS://example.com/", true},
+		{"/relative?q=1", true},
+		{"example.com", true},
+		{"", false},
+		{"javascript:alert(1)", false},
+		{"JavaScript:alert(1)", false},
+		{"data:text/html,<b>", false},
+		{"vbscript:x", false},
+		{" javascript:x", false},
+	}
+	for _, test := range tests {
+		if actual := isSafeHref(test.href); actual != test.expected {
+			t.Errorf("isSafeHref returned incorrect value for href [%s]. Expected:%v Got:%v", test.href, test.expected, actual)
+		}
+	}
+
+	text := "@user #tag"
+	expected := "@user #tag"
+	actual := AutoLink(text, WithSafeHTML(true), WithUsernameUrlBase("javascript:"), WithHashtagUrlBase("data:"))
+	if actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
@@ -0,0 +1,90 @@
+package autolink
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// A Segment is a run of text in the output of Segments. Segments that
+// correspond to an entity are links; all others are plain text.
+type Segment struct {
+	Text       string                 // The text to display, unescaped
+	Entity     *extract.TwitterEntity // The linked entity, or nil for plain text
+	Href       string                 // The URL the segment links to, or "" for plain text
+	Attributes Attributes             // The attributes of the link, including href, or nil for plain text
+}
+
+// Returns true if the segment is a link to an entity
+func (s Segment) IsLink() bool {
+	return s.Entity != nil
+}
+
+// Splits the given text into plain text and link segments using the
+// default settings. The entities must be sorted by their position within
+// text and must not overlap, as returned by extract.ExtractEntities.
+func Segments(text string, entities []*extract.TwitterEntity, opts ...Option) []Segment {
+	return NewAutolinker(opts...).Segments(text, entities)
+}
+
+// Splits the given text into plain text and link segments, for clients
+// that build their own representation of a tweet (e.g. an attributed
+// string) instead of rendering HTML. Each link segment has the href and
+// attributes the Autolinker would render for the entity; its text is the
+// entity text, or the display URL for URLs that have one. Media URLs are
+// handled according to MediaUrlMode, and custom entities with no href are
+// plain text. Text is never escaped, so
+// TextIsEscaped, BidiMode, and the tag settings have no effect. The
+// entities must be sorted by their position within text and must not
+// overlap, as returned by extract.ExtractEntities.
+func (a *Autolinker) Segments(text string, entities []*extract.TwitterEntity, opts ...Option) []Segment {
+	a = a.with(opts)
+
+	var segments []Segment
+	appendText := func(s string) {
+		if s == "" {
+			return
+		}
+		if n := len(segments); n > 0 && !segments[n-1].IsLink() {
+			segments[n-1].Text += s
+			return
+		}
+		segments = append(segments, Segment{Text: s})
+	}
+
+	offset := 0
+	for _, e := range entities {
+		preceding := text[offset:e.ByteRange.Start]
+		offset = e.ByteRange.Stop
+
+		if e.Type == extract.URL && a.MediaUrlMode != MediaUrlLink && isMediaUrl(e) {
+			if a.MediaUrlMode == MediaUrlRemove {
+				if strings.TrimSpace(text[offset:]) == "" {
+					preceding = strings.TrimRightFunc(preceding, unicode.IsSpace)
+					offset = len(text)
+				}
+				appendText(preceding)
+			} else {
+				appendText(preceding + e.Text)
+			}
+			continue
+		}
+		if a.isUnlinked(e) {
+			appendText(preceding + e.Text)
+			continue
+		}
+
+		appendText(preceding)
+		attrs := a.attributesFor(e, text)
+		href, _ := attrs.Get("href")
+		segments = append(segments, Segment{
+			Text:       displayText(e),
+			Entity:     e,
+			Href:       href,
+			Attributes: attrs,
+		})
+	}
+	appendText(text[offset:])
+	return segments
+}
@@ -0,0 +1,71 @@
+package autolink
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestSegments(t *testing.T) {
+	text := "hi @user #tag <b>"
+	segments := Segments(text, extract.ExtractEntities(text), WithNoFollow(false))
+
+	expected := []struct {
+		text string
+		href string
+	}{
+		{"hi ", ""},
+		{"@user", "https://twitter.com/user"},
+		{" ", ""},
+		{"#tag", "https://twitter.com/search?q=%23tag"},
+		{" <b>", ""},
+	}
+	if len(segments) != len(expected) {
+		t.Fatalf("Segments returned %d segments for text [%s]. Expected:[%d]", len(segments), text, len(expected))
+	}
+	for i, s := range segments {
+		if s.Text != expected[i].text || s.Href != expected[i].href {
+			t.Errorf("Segments returned incorrect segment %d for text [%s]. Expected:[%s %s] Got:[%s %s]", i, text, expected[i].text, expected[i].href, s.Text, s.Href)
+		}
+		if s.IsLink() != (expected[i].href != "") {
+			t.Errorf("Segments returned incorrect IsLink for segment %d of text [%s]", i, text)
+		}
+	}
+
+	expectedAttrs := Attributes{
+		{"href", "https://twitter.com/search?q=%23tag"},
+		{"title", "#tag"},
+		{"class", "tweet-url hashtag"},
+	}
+	if !reflect.DeepEqual(segments[3].Attributes, expectedAttrs) {
+		t.Errorf("Segments returned incorrect attributes for text [%s]. Expected:[%v] Got:[%v]", text, expectedAttrs, segments[3].Attributes)
+	}
+	if segments[3].Entity.Type != extract.HASH_TAG {
+		t.Errorf("Segments returned incorrect entity type for text [%s]. Expected:[%v] Got:[%v]", text, extract.HASH_TAG, segments[3].Entity.Type)
+	}
+}
+
+func TestSegmentsMedia(t *testing.T) {
+	text := "photo pic.twitter.com/abc123"
+	entities := extract.ExtractEntities(text)
+
+	tests := []struct {
+		mode     MediaUrlMode
+		expected []string
+	}{
+		{MediaUrlLink, []string{"photo ", "pic.twitter.com/abc123"}},
+		{MediaUrlSkip, []string{"photo pic.twitter.com/abc123"}},
+		{MediaUrlRemove, []string{"photo"}},
+	}
+
+	for _, test := range tests {
+		var actual []string
+		for _, s := range Segments(text, entities, WithMediaUrlMode(test.mode)) {
+			actual = append(actual, s.Text)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Segments returned incorrect value for text [%s] and mode %d. Expected:[%q] Got:[%q]", text, test.mode, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package autolink
+
+import (
+	"strings"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+var (
+	// Escapes the control characters of Slack's mrkdwn format
+	slackEscaper = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`)
+
+	// Escapes URLs for use within a Slack <url|text> link. The | character
+	// separates the URL from the link text, so it must be percent-encoded
+	slackUrlEscaper = strings.NewReplacer(`&`, `&amp;`, `<`, `%3C`, `>`, `%3E`, `|`, `%7C`)
+)
+
+// Renders the given text in Slack's mrkdwn format using the default
+// settings, converting each of the supplied entities into a <url|text> link.
+// The entities must be sorted by their position within text and must not
+// overlap, as returned by extract.ExtractEntities.
+func RenderSlack(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	return NewAutolinker(opts...).RenderSlack(text, entities)
+}
+
+// Renders the given text in Slack's mrkdwn format, converting each of the
+// supplied entities into a <url|text> link that uses the Autolinker's base
+// URLs. &, <, and > are escaped as required by Slack. The entities must be
+// sorted by their position within text and must not overlap, as returned
+// by extract.ExtractEntities.
+func (a *Autolinker) RenderSlack(text string, entities []*extract.TwitterEntity, opts ...Option) string {
+	a = a.with(opts)
+
+	buf := a.getBuffer()
+	defer a.putBuffer(buf)
+	offset := 0
+	for _, e := range entities {
+		buf.WriteString(slackEscaper.Replace(text[offset:e.ByteRange.Start]))
+		offset = e.ByteRange.Stop
+		if a.isUnlinked(e) {
+			buf.WriteString(slackEscaper.Replace(e.Text))
+			continue
+		}
+		buf.WriteString("<")
+		buf.WriteString(slackUrlEscaper.Replace(a.hrefFor(e)))
+		buf.WriteString("|")
+		buf.WriteString(slackEscaper.Replace(displayText(e)))
+		buf.WriteString(">")
+	}
+	buf.WriteString(slackEscaper.Replace(text[offset:]))
+	return buf.String()
+}
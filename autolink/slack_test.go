@@ -0,0 +1,27 @@
+package autolink
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestRenderSlack(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"hello @user #tag $TAG",
+			`hello <https://twitter.com/user|@user> <https://twitter.com/search?q=%23tag|#tag> <https://twitter.com/search?q=%24TAG|$TAG>`},
+		{"a < b & c > d http://example.com/?a=1&b=2|3",
+			`a &lt; b &amp; c &gt; d <http://example.com/?a=1&amp;b=2%7C3|http://example.com/?a=1&amp;b=2|3>`},
+		{"no entities", "no entities"},
+	}
+
+	for _, test := range tests {
+		actual := RenderSlack(test.text, extract.ExtractEntities(test.text))
+		if actual != test.expected {
+			t.Errorf("RenderSlack returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
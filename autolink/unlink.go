@@ -0,0 +1,142 @@
+package autolink
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Converts HTML produced by the autolinker (or any Twitter-style anchors)
+// back into plain tweet text. All tags are removed and HTML entities are
+// unescaped. Links to URLs that were rendered using a display URL are
+// replaced by their href (the URL that originally appeared in the text),
+// and the text of all other links is kept as-is, so @mentions, #hashtags,
+// and $cashtags are restored along with their symbols. The LEFT-TO-RIGHT
+// and RIGHT-TO-LEFT marks written around links in BidiMarks mode are
+// removed.
+func Unlink(s string) string {
+	var (
+		buf bytes.Buffer
+
+		inAnchor    bool
+		anchorText  bytes.Buffer
+		anchorHref  string
+		hasDisplay  bool
+		hiddenDepth int
+		spanDepth   int
+		afterAnchor bool // whether the last token closed an anchor
+	)
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		t := z.Token()
+		if tt == html.TextToken && afterAnchor {
+			t.Data = trimBidiMark(t.Data)
+		}
+		afterAnchor = false
+		switch tt {
+		case html.TextToken:
+			if hiddenDepth > 0 {
+				continue
+			}
+			if inAnchor {
+				anchorText.WriteString(t.Data)
+			} else {
+				buf.WriteString(t.Data)
+			}
+		case html.StartTagToken:
+			switch t.Data {
+			case "a":
+				if !inAnchor {
+					trimBidiMarkBeforeLink(&buf)
+				}
+				inAnchor = true
+				anchorText.Reset()
+				anchorHref = attrValue(t, "href")
+				hasDisplay = false
+			case "span":
+				spanDepth++
+				classes := strings.Fields(attrValue(t, "class"))
+				for _, class := range classes {
+					switch class {
+					case "tco-ellipsis":
+						// Ellipses are displayed but are not part of the text
+						if hiddenDepth == 0 {
+							hiddenDepth = spanDepth
+						}
+						hasDisplay = true
+					case "js-display-url":
+						hasDisplay = true
+					}
+				}
+			}
+		case html.EndTagToken:
+			switch t.Data {
+			case "a":
+				if !inAnchor {
+					continue
+				}
+				if hasDisplay && anchorHref != "" {
+					buf.WriteString(anchorHref)
+				} else {
+					buf.Write(anchorText.Bytes())
+				}
+				inAnchor = false
+				afterAnchor = true
+			case "span":
+				if hiddenDepth == spanDepth {
+					hiddenDepth = 0
+				}
+				if spanDepth > 0 {
+					spanDepth--
+				}
+			}
+		}
+	}
+
+	// Unterminated anchor
+	if inAnchor {
+		buf.Write(anchorText.Bytes())
+	}
+	return buf.String()
+}
+
+// Returns s without the bidi mark at its start, if any
+func trimBidiMark(s string) string {
+	if strings.HasPrefix(s, leftToRightMark) || strings.HasPrefix(s, rightToLeftMark) {
+		return s[len(leftToRightMark):]
+	}
+	return s
+}
+
+// Removes the bidi mark written before a link from the end of buf. The
+// mark precedes the at sign of a mention, which is outside of the link
+func trimBidiMarkBeforeLink(buf *bytes.Buffer) {
+	b := buf.Bytes()
+	symbol := 0
+	if r, size := utf8.DecodeLastRune(b); r == '@' || r == '＠' {
+		symbol = size
+	}
+	text := b[:len(b)-symbol]
+	if !bytes.HasSuffix(text, []byte(leftToRightMark)) && !bytes.HasSuffix(text, []byte(rightToLeftMark)) {
+		return
+	}
+	start := len(text) - len(leftToRightMark)
+	buf.Truncate(start + copy(b[start:], b[len(text):]))
+}
+
+func attrValue(t html.Token, name string) string {
+	for _, attr := range t.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package autolink
+
+import (
+	"testing"
+)
+
+func TestUnlink(t *testing.T) {
+	tests := []string{
+		"hello @user and @user/list #tag $TAG http://example.com/?a=1&b=2",
+		"<b>not a tag</b> & #tag",
+		"full-width ＠user ＃tag",
+		"no entities",
+		"",
+	}
+
+	for _, text := range tests {
+		linked := AutoLink(text)
+		if actual := Unlink(linked); actual != text {
+			t.Errorf("Unlink returned incorrect value for HTML [%s]. Expected:[%s] Got:[%s]", linked, text, actual)
+		}
+
+		linked = AutoLink(text, WithSymbolTag("s"), WithTextWithSymbolTag("b"), WithUsernameIncludeSymbol(true))
+		if actual := Unlink(linked); actual != text {
+			t.Errorf("Unlink returned incorrect value for HTML [%s]. Expected:[%s] Got:[%s]", linked, text, actual)
+		}
+	}
+}
+
+func TestUnlinkBidiMarks(t *testing.T) {
+	tests := []string{
+		"שלום @user and @user/list",
+		"שלום #שלום #tag $TAG http://example.com",
+		"مرحبا ＠user, #وسم.",
+		"שלום @user@mastodon.social",
+		"‎שלום‏ @user",
+		"no right-to-left text @user",
+	}
+
+	for _, text := range tests {
+		for _, opts := range [][]Option{
+			{WithBidiMode(BidiMarks)},
+			{WithBidiMode(BidiMarks), WithSymbolTag("s"), WithUsernameIncludeSymbol(true)},
+			{WithBidiMode(BidiMarks), WithFederatedMentions(true)},
+		} {
+			linked := AutoLink(text, opts...)
+			if actual := Unlink(linked); actual != text {
+				t.Errorf("Unlink returned incorrect value for HTML [%q]. Expected:[%q] Got:[%q]", linked, text, actual)
+			}
+		}
+	}
+}
+
+func TestUnlinkDisplayUrl(t *testing.T) {
+	text := "hi http://t.co/0JG5Mcq #tag"
+	entities := Entities{
+		Urls: []UrlEntity{{
+			Url:         "http://t.co/0JG5Mcq",
+			DisplayUrl:  "…nname.com/foo",
+			ExpandedUrl: "http://longdomainname.com/foo",
+			Indices:     [2]int{3, 22},
+		}},
+		Hashtags: []HashtagEntity{{Text: "tag", Indices: [2]int{23, 27}}},
+	}
+
+	linked := AutoLinkEntities(text, entities)
+	if actual := Unlink(linked); actual != text {
+		t.Errorf("Unlink returned incorrect value for HTML [%s]. Expected:[%s] Got:[%s]", linked, text, actual)
+	}
+}
+
+func TestUnlinkTwitterAnchors(t *testing.T) {
+	html := `RT <a href="/jack" class="twitter-atreply">@jack</a>: check <a href="https://t.co/x" title="https://example.com">example.com</a>`
+	expected := "RT @jack: check example.com"
+	if actual := Unlink(html); actual != expected {
+		t.Errorf("Unlink returned incorrect value for HTML [%s]. Expected:[%s] Got:[%s]", html, expected, actual)
+	}
+}
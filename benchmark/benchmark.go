@@ -0,0 +1,211 @@
+// Package benchmark is a harness for measuring the performance of
+// extraction and validation over a corpus of tweets. It runs the same
+// benchmarks over the conformance suites and over corpora supplied by the
+// caller, reporting allocations as well as time, so that one release can be
+// compared with another on the text an application actually handles.
+//
+// Within a test binary, Run adds the benchmarks to a testing.B:
+//
+//	func BenchmarkTwitterText(b *testing.B) {
+//		f, err := os.Open("testdata/timeline.jsonl")
+//		...
+//		corpus, err := benchmark.ReadJSONLines("timeline", f)
+//		...
+//		benchmark.Run(b, corpus, benchmark.Default()...)
+//	}
+//
+// Elsewhere, e.g. in a release script, Measure runs them directly. In both
+// cases the results are in the format of go test -bench, so two runs can be
+// compared with a tool such as benchstat.
+package benchmark
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// A Corpus is a named collection of tweets that benchmarks are run over
+type Corpus struct {
+	Name  string
+	Texts []string
+}
+
+// Returns the total length of the texts in bytes
+func (c Corpus) Bytes() int {
+	n := 0
+	for _, text := range c.Texts {
+		n += len(text)
+	}
+	return n
+}
+
+// Returns a corpus for each of the conformance suites, holding the text of
+// every test in the suite. The corpora are named after the suites, e.g.
+// "conformance/extract"
+func Conformance() ([]Corpus, error) {
+	names, err := fs.Glob(conformance.Files, "*.yml")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var result []Corpus
+	for _, name := range names {
+		suite, err := conformance.Load(name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Map iteration order is random; sort the sections so that the
+		// corpus is the same from one run to the next
+		sections := make([]string, 0, len(suite.Tests))
+		for section := range suite.Tests {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+
+		corpus := Corpus{Name: "conformance/" + strings.TrimSuffix(name, path.Ext(name))}
+		for _, section := range sections {
+			for _, test := range suite.Tests[section] {
+				corpus.Texts = append(corpus.Texts, test.Text)
+			}
+		}
+		result = append(result, corpus)
+	}
+	return result, nil
+}
+
+// Returns a corpus holding each non-empty line read from r as a tweet
+func ReadLines(name string, r io.Reader) (Corpus, error) {
+	corpus := Corpus{Name: name}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			corpus.Texts = append(corpus.Texts, line)
+		}
+	}
+	return corpus, scanner.Err()
+}
+
+// Returns a corpus holding the tweets in a stream of JSON objects read from
+// r, such as one saved from the Twitter API. The text of each tweet is
+// taken from its full_text field, or from its text field if it has no
+// full_text
+func ReadJSONLines(name string, r io.Reader) (Corpus, error) {
+	corpus := Corpus{Name: name}
+	decoder := json.NewDecoder(r)
+	for {
+		var tweet struct {
+			Text     string `json:"text"`
+			FullText string `json:"full_text"`
+		}
+		if err := decoder.Decode(&tweet); err == io.EOF {
+			return corpus, nil
+		} else if err != nil {
+			return corpus, fmt.Errorf("error reading tweet %d of %s: %v", len(corpus.Texts)+1, name, err)
+		}
+		if tweet.FullText != "" {
+			corpus.Texts = append(corpus.Texts, tweet.FullText)
+		} else {
+			corpus.Texts = append(corpus.Texts, tweet.Text)
+		}
+	}
+}
+
+// A Benchmark is an operation that is timed over each text of a corpus
+type Benchmark struct {
+	Name string
+	Func func(text string)
+}
+
+// Returns benchmarks for each of the extract package's Extract functions
+func Extraction() []Benchmark {
+	return []Benchmark{
+		{"ExtractEntities", func(text string) { extract.ExtractEntities(text) }},
+		{"ExtractUrls", func(text string) { extract.ExtractUrls(text) }},
+		{"ExtractMentionsOrLists", func(text string) { extract.ExtractMentionsOrLists(text) }},
+		{"ExtractHashtags", func(text string) { extract.ExtractHashtags(text) }},
+		{"ExtractCashtags", func(text string) { extract.ExtractCashtags(text) }},
+	}
+}
+
+// Returns benchmarks for weighing and validating tweets with the validate
+// package, using the given options
+func Validation(opts ...validate.Option) []Benchmark {
+	return []Benchmark{
+		{"TweetLength", func(text string) { validate.TweetLength(text, opts...) }},
+		{"ParseTweet", func(text string) { validate.ParseTweet(text, opts...) }},
+	}
+}
+
+// Returns the extraction benchmarks followed by the validation benchmarks
+// under the default configuration
+func Default() []Benchmark {
+	return append(Extraction(), Validation()...)
+}
+
+// Runs each of the benchmarks over the corpus as a sub-benchmark of b
+// named after the corpus and the benchmark. Each operation processes every
+// text in the corpus once, so the reported bytes per second is the rate at
+// which the corpus's text is processed
+func Run(b *testing.B, corpus Corpus, benchmarks ...Benchmark) {
+	for _, bm := range benchmarks {
+		b.Run(corpus.Name+"/"+bm.Name, benchmarkFunc(corpus, bm))
+	}
+}
+
+func benchmarkFunc(corpus Corpus, bm Benchmark) func(*testing.B) {
+	bytes := int64(corpus.Bytes())
+	return func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(bytes)
+		for i := 0; i < b.N; i++ {
+			for _, text := range corpus.Texts {
+				bm.Func(text)
+			}
+		}
+	}
+}
+
+// The result of running a benchmark over a corpus
+type Result struct {
+	Corpus    string
+	Benchmark string
+	testing.BenchmarkResult
+}
+
+// Returns the result as a line of go test -bench output, including
+// allocations
+func (r Result) String() string {
+	name := strings.Join(strings.Fields("Benchmark"+r.Corpus+"/"+r.Benchmark), "_")
+	return fmt.Sprintf("%s\t%s\t%s", name, r.BenchmarkResult.String(), r.MemString())
+}
+
+// Runs each of the benchmarks over each of the corpora outside of a test
+// binary and returns the results, in order by corpus, then benchmark. Each
+// benchmark runs for about a second
+func Measure(corpora []Corpus, benchmarks ...Benchmark) []Result {
+	var results []Result
+	for _, corpus := range corpora {
+		for _, bm := range benchmarks {
+			results = append(results, Result{
+				Corpus:          corpus.Name,
+				Benchmark:       bm.Name,
+				BenchmarkResult: testing.Benchmark(benchmarkFunc(corpus, bm)),
+			})
+		}
+	}
+	return results
+}
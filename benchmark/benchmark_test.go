@@ -0,0 +1,106 @@
+package benchmark
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	corpora, err := Conformance()
+	if err != nil {
+		t.Fatalf("Conformance returned an error: %v", err)
+	}
+
+	names := make([]string, len(corpora))
+	for i, corpus := range corpora {
+		names[i] = corpus.Name
+		if len(corpus.Texts) == 0 {
+			t.Errorf("Conformance returned an empty corpus [%s]", corpus.Name)
+		}
+	}
+	// One corpus per vendored suite
+	files, err := fs.Glob(conformance.Files, "*.yml")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Glob returned incorrect value. Expected suites Got:%v %v", files, err)
+	}
+	var expected []string
+	for _, file := range files {
+		expected = append(expected, "conformance/"+strings.TrimSuffix(file, ".yml"))
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Conformance returned incorrect corpora. Expected:%v Got:%v", expected, names)
+	}
+
+	again, err := Conformance()
+	if err != nil {
+		t.Fatalf("Conformance returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(corpora, again) {
+		t.Errorf("Conformance returned different corpora on a second call")
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	corpus, err := ReadLines("lines", strings.NewReader("I am a Tweet\n\n#hashtag @mention\r\nhttp://example.com"))
+	if err != nil {
+		t.Fatalf("ReadLines returned an error: %v", err)
+	}
+	expected := Corpus{Name: "lines", Texts: []string{"I am a Tweet", "#hashtag @mention", "http://example.com"}}
+	if !reflect.DeepEqual(corpus, expected) {
+		t.Errorf("ReadLines returned incorrect value. Expected:%q Got:%q", expected, corpus)
+	}
+	if corpus.Bytes() != 47 {
+		t.Errorf("Bytes returned incorrect value. Expected:[47] Got:[%d]", corpus.Bytes())
+	}
+}
+
+func TestReadJSONLines(t *testing.T) {
+	input := `{"id": 1, "text": "I am a Tweet"}
+{"id": 2, "text": "truncated…", "full_text": "not truncated\nat all"}
+{"id": 3, "text": "", "user": {"screen_name": "jack"}}
+`
+	corpus, err := ReadJSONLines("tweets", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadJSONLines returned an error: %v", err)
+	}
+	expected := Corpus{Name: "tweets", Texts: []string{"I am a Tweet", "not truncated\nat all", ""}}
+	if !reflect.DeepEqual(corpus, expected) {
+		t.Errorf("ReadJSONLines returned incorrect value. Expected:%q Got:%q", expected, corpus)
+	}
+
+	if _, err := ReadJSONLines("tweets", strings.NewReader(`{"text": "ok"} {"text": `)); err == nil {
+		t.Errorf("ReadJSONLines did not return an error for truncated input")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Measure runs for about a second")
+	}
+	corpus := Corpus{Name: "small corpus", Texts: []string{"#hashtag @mention http://example.com"}}
+	results := Measure([]Corpus{corpus}, Extraction()[1])
+	if len(results) != 1 {
+		t.Fatalf("Measure returned %d results, expected 1", len(results))
+	}
+	r := results[0]
+	if r.Corpus != "small corpus" || r.Benchmark != "ExtractUrls" || r.N == 0 || r.Bytes != int64(corpus.Bytes()) {
+		t.Errorf("Measure returned incorrect result: %+v", r)
+	}
+	if s := r.String(); !strings.HasPrefix(s, "Benchmarksmall_corpus/ExtractUrls\t") || !strings.HasSuffix(s, "allocs/op") {
+		t.Errorf("String returned incorrect value [%s]", s)
+	}
+}
+
+func BenchmarkConformance(b *testing.B) {
+	corpora, err := Conformance()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, corpus := range corpora {
+		Run(b, corpus, Default()...)
+	}
+}
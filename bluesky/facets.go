@@ -0,0 +1,179 @@
+// Package bluesky converts the entities found in a text into Bluesky rich
+// text facets, for clients that post the same text to Twitter and Bluesky.
+//
+// Facets locate entities by UTF-8 byte offsets rather than characters, and
+// identify a mentioned account by its DID rather than its handle. The
+// offsets are taken from the entities' byte ranges, and DIDs are left to a
+// Resolver, since looking one up needs a request to a Bluesky server:
+//
+//	facets, err := bluesky.Facets(text, bluesky.WithResolver(func(handle string) (string, error) {
+//		return lookUpDID(ctx, handle)
+//	}))
+//
+// The facets can be encoded directly into the "facets" field of an
+// app.bsky.feed.post record using encoding/json.
+package bluesky
+
+import (
+	"strings"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// The types of facet features
+const (
+	MentionFeature = "app.bsky.richtext.facet#mention"
+	LinkFeature    = "app.bsky.richtext.facet#link"
+	TagFeature     = "app.bsky.richtext.facet#tag"
+)
+
+// The longest tag a tag feature may hold, in bytes
+const maxTagLength = 640
+
+// An app.bsky.richtext.facet: a range of a text and what it refers to
+type Facet struct {
+	Index    ByteSlice `json:"index"`
+	Features []Feature `json:"features"`
+}
+
+// The range of a facet in a text, in UTF-8 byte offsets. ByteEnd is
+// exclusive
+type ByteSlice struct {
+	ByteStart int `json:"byteStart"`
+	ByteEnd   int `json:"byteEnd"`
+}
+
+// A feature of a facet. Type is one of MentionFeature, LinkFeature, or
+// TagFeature, and determines which of the other fields is set
+type Feature struct {
+	Type string `json:"$type"`
+	Did  string `json:"did,omitempty"` // The DID of the mentioned account
+	Uri  string `json:"uri,omitempty"` // The URI a link refers to
+	Tag  string `json:"tag,omitempty"` // The tag, without the leading #
+}
+
+// Resolves the handle of a mentioned account, without the @, to the
+// account's DID. Returns "" if there is no such account, in which case the
+// mention is left as plain text. An error stops the conversion
+type Resolver func(handle string) (did string, err error)
+
+// Resolves the text of a URL entity to the URI of its link feature.
+// Returns "" to leave the URL as plain text
+type LinkResolver func(url string) (uri string, err error)
+
+// Configures how entities are converted
+type Option func(*converter)
+
+// Sets the resolver used to look up mentioned accounts. Without one,
+// mentions are left as plain text
+func WithResolver(r Resolver) Option {
+	return func(c *converter) { c.resolver = r }
+}
+
+// Sets the resolver used to find the URIs of links. By default, a URL
+// without a scheme is linked with https
+func WithLinkResolver(r LinkResolver) Option {
+	return func(c *converter) { c.linkResolver = r }
+}
+
+type converter struct {
+	resolver     Resolver
+	linkResolver LinkResolver
+}
+
+// Returns the facets for the mentions, URLs, and hashtags in text
+func Facets(text string, opts ...Option) ([]Facet, error) {
+	return FacetsFromEntities(text, extract.ExtractEntities(text), opts...)
+}
+
+// Returns the facets for the given mentions, URLs, and hashtags, which
+// were extracted from text. Other entities, including cashtags, which
+// Bluesky does not link, are ignored.
+//
+// Bluesky handles are domain names, such as alice.bsky.social, but the
+// extract package finds only the first label of such a handle, so a
+// mention is extended over any further labels that follow it. Lists are
+// not linked, so a mention of a list refers to its owner.
+func FacetsFromEntities(text string, entities []*extract.TwitterEntity, opts ...Option) ([]Facet, error) {
+	c := &converter{linkResolver: defaultLinkResolver}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var facets []Facet
+	for _, e := range entities {
+		var (
+			feature Feature
+			index   = ByteSlice{e.ByteRange.Start, e.ByteRange.Stop}
+		)
+		switch e.Type {
+		case extract.MENTION:
+			if c.resolver == nil {
+				continue
+			}
+			listSlug, _ := e.ListSlug()
+			index.ByteEnd -= len(listSlug)
+			if listSlug == "" {
+				index.ByteEnd = handleEnd(text, index.ByteEnd)
+			}
+			screenName, _ := e.ScreenName()
+			handle := screenName + text[e.ByteRange.Stop-len(listSlug):index.ByteEnd]
+			did, err := c.resolver(handle)
+			if err != nil {
+				return nil, err
+			}
+			if did == "" {
+				continue
+			}
+			feature = Feature{Type: MentionFeature, Did: did}
+		case extract.URL:
+			uri, err := c.linkResolver(e.Text)
+			if err != nil {
+				return nil, err
+			}
+			if uri == "" {
+				continue
+			}
+			feature = Feature{Type: LinkFeature, Uri: uri}
+		case extract.HASH_TAG:
+			tag, _ := e.Hashtag()
+			if len(tag) > maxTagLength {
+				continue
+			}
+			feature = Feature{Type: TagFeature, Tag: tag}
+		default:
+			continue
+		}
+		facets = append(facets, Facet{Index: index, Features: []Feature{feature}})
+	}
+	return facets, nil
+}
+
+// Returns the end of the handle whose first label ends at i: the end of
+// any labels that follow, each preceded by a dot
+func handleEnd(text string, i int) int {
+	for i < len(text) && text[i] == '.' {
+		j := i + 1
+		for j < len(text) && isLabelByte(text[j]) {
+			j++
+		}
+		if j == i+1 {
+			break
+		}
+		i = j
+	}
+	return i
+}
+
+// Reports whether b may appear in a label of a handle
+func isLabelByte(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '-'
+}
+
+// Returns url, with https if it has no scheme
+func defaultLinkResolver(url string) (string, error) {
+	if strings.Contains(url, "://") {
+		return url, nil
+	}
+	return "https://" + url, nil
+}
@@ -0,0 +1,92 @@
+package bluesky
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Resolves handles to DIDs for the tests
+func testResolver(handle string) (string, error) {
+	switch handle {
+	case "alice.bsky.social":
+		return "did:plc:alice", nil
+	case "bob":
+		return "did:plc:bob", nil
+	}
+	return "", nil
+}
+
+func TestFacets(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []Facet
+	}{
+		{"no entities", nil},
+		{"hi @alice.bsky.social!", []Facet{
+			{ByteSlice{3, 21}, []Feature{{Type: MentionFeature, Did: "did:plc:alice"}}},
+		}},
+		// A dot at the end of a sentence is not part of the handle
+		{"thanks @bob.", []Facet{
+			{ByteSlice{7, 11}, []Feature{{Type: MentionFeature, Did: "did:plc:bob"}}},
+		}},
+		{"@bob/list @carol", []Facet{
+			{ByteSlice{0, 4}, []Feature{{Type: MentionFeature, Did: "did:plc:bob"}}},
+		}},
+		// Offsets are in bytes
+		{"日本 #タグ see example.com/a and https://x.org $CASH", []Facet{
+			{ByteSlice{7, 14}, []Feature{{Type: TagFeature, Tag: "タグ"}}},
+			{ByteSlice{19, 32}, []Feature{{Type: LinkFeature, Uri: "https://example.com/a"}}},
+			{ByteSlice{37, 50}, []Feature{{Type: LinkFeature, Uri: "https://x.org"}}},
+		}},
+	}
+
+	for _, test := range tests {
+		actual, err := Facets(test.text, WithResolver(testResolver))
+		if err != nil || !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Facets returned incorrect value for text [%s]. Expected:%+v Got:%+v %v", test.text, test.expected, actual, err)
+		}
+		for _, f := range actual {
+			if f.Features[0].Type == MentionFeature && test.text[f.Index.ByteStart] != '@' {
+				t.Errorf("Facets returned a mention facet that does not start with @ for text [%s]: %+v", test.text, f)
+			}
+		}
+	}
+}
+
+func TestFacetsOptions(t *testing.T) {
+	// Mentions are left as text without a resolver
+	text := "@bob #tag"
+	expected := []Facet{{ByteSlice{5, 9}, []Feature{{Type: TagFeature, Tag: "tag"}}}}
+	if actual, err := Facets(text); err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Facets returned incorrect value for text [%s] without a resolver. Expected:%+v Got:%+v %v", text, expected, actual, err)
+	}
+
+	resolveErr := errors.New("lookup failed")
+	_, err := Facets(text, WithResolver(func(string) (string, error) { return "", resolveErr }))
+	if err != resolveErr {
+		t.Errorf("Facets returned incorrect error for a failed lookup. Expected:%v Got:%v", resolveErr, err)
+	}
+
+	text = "a.com b.com"
+	expected = []Facet{{ByteSlice{6, 11}, []Feature{{Type: LinkFeature, Uri: "http://b.com"}}}}
+	actual, err := Facets(text, WithLinkResolver(func(url string) (string, error) {
+		if url == "a.com" {
+			return "", nil
+		}
+		return "http://" + url, nil
+	}))
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Facets returned incorrect value for text [%s] with a link resolver. Expected:%+v Got:%+v %v", text, expected, actual, err)
+	}
+}
+
+func TestFacetJSON(t *testing.T) {
+	facets, _ := Facets("#go", WithResolver(testResolver))
+	b, err := json.Marshal(facets)
+	expected := `[{"index":{"byteStart":0,"byteEnd":3},"features":[{"$type":"app.bsky.richtext.facet#tag","tag":"go"}]}]`
+	if err != nil || string(b) != expected {
+		t.Errorf("Facets encoded incorrectly. Expected:%s Got:%s %v", expected, b, err)
+	}
+}
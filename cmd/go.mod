@@ -0,0 +1,31 @@
+module github.com/kylemcc/twitter-text-go/cmd
+
+go 1.19
+
+require (
+	github.com/kylemcc/twitter-text-go v0.0.0-00010101000000-000000000000
+	github.com/kylemcc/twitter-text-go/rpc v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.12.2
+	google.golang.org/grpc v1.56.3
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/client_model v0.2.0 // indirect
+	github.com/prometheus/common v0.32.1 // indirect
+	github.com/prometheus/procfs v0.7.3 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+	google.golang.org/protobuf v1.31.0 // indirect
+)
+
+replace (
+	github.com/kylemcc/twitter-text-go => ../
+	github.com/kylemcc/twitter-text-go/rpc => ../rpc
+)
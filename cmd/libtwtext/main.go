@@ -0,0 +1,165 @@
+// Command libtwtext builds the twitter-text functions as a C shared
+// library, so that services in other languages can call this
+// implementation through their foreign function interfaces. Build it with:
+//
+//	go build -buildmode=c-shared -o libtwtext.so github.com/kylemcc/twitter-text-go/cmd/libtwtext
+//
+// which also writes libtwtext.h, declaring:
+//
+//	typedef struct {
+//		int weighted_length;
+//		int permillage;
+//		int valid;
+//	} twtext_parse_results;
+//
+//	int twtext_parse_tweet(const char *text, const char *config, twtext_parse_results *results);
+//	int twtext_validate_tweet(const char *text, const char *config, char **error);
+//	char *twtext_extract_entities(const char *text);
+//	char *twtext_extract_urls(const char *text);
+//	char *twtext_extract_hashtags(const char *text);
+//	char *twtext_extract_mentions_or_lists(const char *text);
+//	char *twtext_extract_cashtags(const char *text);
+//	char *twtext_autolink(const char *text);
+//	void twtext_free(char *s);
+//
+// Texts are UTF-8 and NUL-terminated. config is NULL or empty for the
+// twitter-v3 configuration, the name of another preset, or a JSON
+// configuration. twtext_parse_tweet returns 0, or -1 if config is invalid.
+// twtext_validate_tweet returns 1 if the text is valid and 0 if not, in
+// which case it stores the reason in *error if error is not NULL, or -1 if
+// config is invalid.
+//
+// The extract functions return a JSON array of entities, as written by the
+// twtext command:
+//
+//	[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]
+//
+// Indices count characters (code points), as Python and Ruby strings do.
+// Strings returned by the library must be freed with twtext_free. From
+// Python, for example:
+//
+//	lib = ctypes.CDLL("./libtwtext.so")
+//	lib.twtext_extract_entities.restype = ctypes.c_void_p
+//	p = lib.twtext_extract_entities("Hello @world".encode())
+//	entities = json.loads(ctypes.string_at(p))
+//	lib.twtext_free(ctypes.c_void_p(p))
+package main
+
+/*
+#include <stdlib.h>
+
+typedef struct {
+	int weighted_length;
+	int permillage;
+	int valid;
+} twtext_parse_results;
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"strings"
+	"unsafe"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/textjson"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// Required by -buildmode=c-shared, but never called
+func main() {}
+
+//export twtext_parse_tweet
+func twtext_parse_tweet(text, configSpec *C.char, results *C.twtext_parse_results) C.int {
+	c, err := configFor(configSpec)
+	if err != nil {
+		return -1
+	}
+	r := validate.ParseTweetWithConfig(C.GoString(text), c)
+	results.weighted_length = C.int(r.WeightedLength)
+	results.permillage = C.int(r.Permillage)
+	results.valid = 0
+	if r.IsValid {
+		results.valid = 1
+	}
+	return 0
+}
+
+//export twtext_validate_tweet
+func twtext_validate_tweet(text, configSpec *C.char, errorMsg **C.char) C.int {
+	c, err := configFor(configSpec)
+	if err != nil {
+		return -1
+	}
+	if err := validate.ValidateTweetWithConfig(C.GoString(text), c); err != nil {
+		if errorMsg != nil {
+			*errorMsg = C.CString(err.Error())
+		}
+		return 0
+	}
+	return 1
+}
+
+//export twtext_extract_entities
+func twtext_extract_entities(text *C.char) *C.char {
+	return extractJSON(text, extract.ExtractEntities)
+}
+
+//export twtext_extract_urls
+func twtext_extract_urls(text *C.char) *C.char {
+	return extractJSON(text, extract.ExtractUrls)
+}
+
+//export twtext_extract_hashtags
+func twtext_extract_hashtags(text *C.char) *C.char {
+	return extractJSON(text, extract.ExtractHashtags)
+}
+
+//export twtext_extract_mentions_or_lists
+func twtext_extract_mentions_or_lists(text *C.char) *C.char {
+	return extractJSON(text, extract.ExtractMentionsOrLists)
+}
+
+//export twtext_extract_cashtags
+func twtext_extract_cashtags(text *C.char) *C.char {
+	return extractJSON(text, extract.ExtractCashtags)
+}
+
+//export twtext_autolink
+func twtext_autolink(text *C.char) *C.char {
+	return C.CString(autolink.AutoLink(C.GoString(text)))
+}
+
+//export twtext_free
+func twtext_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// Returns the entities f extracts from text as a JSON array in memory
+// allocated by C
+func extractJSON(text *C.char, f func(string) []*extract.TwitterEntity) *C.char {
+	b, err := json.Marshal(textjson.NewEntities(f(C.GoString(text))))
+	if err != nil {
+		// Entities always marshal
+		panic(err)
+	}
+	return C.CString(string(b))
+}
+
+// Returns the configuration given by a preset name or a JSON
+// configuration, or the default configuration if spec is NULL or empty
+func configFor(spec *C.char) (*config.Config, error) {
+	s := ""
+	if spec != nil {
+		s = C.GoString(spec)
+	}
+	switch {
+	case s == "":
+		return config.V3(), nil
+	case strings.HasPrefix(s, "{"):
+		return config.LoadConfig(strings.NewReader(s))
+	}
+	return config.Preset(s)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Builds the shared library and a C program that calls it, and compares
+// the program's output
+func TestSharedLibrary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building the shared library is slow")
+	}
+	if _, err := exec.LookPath("gcc"); err != nil {
+		t.Skip("gcc is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "libtwtext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lib := filepath.Join(dir, "libtwtext.so")
+	if out, err := exec.Command("go", "build", "-buildmode=c-shared", "-o", lib, ".").CombinedOutput(); err != nil {
+		t.Fatalf("Error building the shared library: %v\n%s", err, out)
+	}
+	prog := filepath.Join(dir, "libtwtext_test")
+	if out, err := exec.Command("gcc", "-o", prog, "-I", dir, filepath.Join("testdata", "libtwtext_test.c"), lib).CombinedOutput(); err != nil {
+		t.Fatalf("Error building the test program: %v\n%s", err, out)
+	}
+	cmd := exec.Command(prog)
+	cmd.Env = append(os.Environ(), "LD_LIBRARY_PATH="+dir, "DYLD_LIBRARY_PATH="+dir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Error running the test program: %v\n%s", err, out)
+	}
+
+	expected := []string{
+		"parse: 0 12 42 1",
+		"parse v1: 0 2 14 1",
+		"parse json: 0 11 1100 0",
+		"parse unknown: -1",
+		"validate: 1",
+		"validate empty: 0 Tweets may not be empty",
+		"validate no error: 0",
+		`entities: [{"type":"mention","text":"@a/b","indices":[2,6],"screenName":"a","listSlug":"/b"},{"type":"hashtag","text":"#c","indices":[7,9],"hashtag":"c"},{"type":"cashtag","text":"$D","indices":[10,12],"cashtag":"D"},{"type":"url","text":"http://e.com","indices":[13,25],"url":"http://e.com"}]`,
+		`urls: [{"type":"url","text":"example.com","indices":[4,15],"url":"example.com"}]`,
+		`hashtags: [{"type":"hashtag","text":"#a","indices":[0,2],"hashtag":"a"}]`,
+		`mentions: [{"type":"mention","text":"@b","indices":[3,5],"screenName":"b"}]`,
+		"cashtags: []",
+		`autolink: <a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow">#tag</a>`,
+	}
+	actual := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	for i := range expected {
+		if i >= len(actual) || actual[i] != expected[i] {
+			t.Errorf("The shared library returned incorrect output. Expected:%s Got:%s", strings.Join(expected, "\n"), out)
+			break
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Calls each function in libtwtext, writing the results to standard output
+// for TestSharedLibrary to compare.
+#include <stdio.h>
+#include "libtwtext.h"
+
+static void print_and_free(const char *name, char *s) {
+	printf("%s: %s\n", name, s);
+	twtext_free(s);
+}
+
+int main(void) {
+	twtext_parse_results results;
+	int status = twtext_parse_tweet("Hello @world", NULL, &results);
+	printf("parse: %d %d %d %d\n", status, results.weighted_length, results.permillage, results.valid);
+	status = twtext_parse_tweet("\xe6\x97\xa5\xe6\x9c\xac", "twitter-v1", &results);
+	printf("parse v1: %d %d %d %d\n", status, results.weighted_length, results.permillage, results.valid);
+	status = twtext_parse_tweet("Hello world", "{\"version\":1,\"maxWeightedTweetLength\":10,\"scale\":1,\"defaultWeight\":1,\"transformedURLLength\":23}", &results);
+	printf("parse json: %d %d %d %d\n", status, results.weighted_length, results.permillage, results.valid);
+	printf("parse unknown: %d\n", twtext_parse_tweet("Hello", "unknown", &results));
+
+	char *error = NULL;
+	printf("validate: %d\n", twtext_validate_tweet("Hello", "", &error));
+	status = twtext_validate_tweet("", NULL, &error);
+	printf("validate empty: %d %s\n", status, error);
+	twtext_free(error);
+	printf("validate no error: %d\n", twtext_validate_tweet("", NULL, NULL));
+
+	print_and_free("entities", twtext_extract_entities("\xe6\x97\xa5 @a/b #c $D http://e.com"));
+	print_and_free("urls", twtext_extract_urls("see example.com"));
+	print_and_free("hashtags", twtext_extract_hashtags("#a @b"));
+	print_and_free("mentions", twtext_extract_mentions_or_lists("#a @b"));
+	print_and_free("cashtags", twtext_extract_cashtags("none"));
+	print_and_free("autolink", twtext_autolink("#tag"));
+	return 0;
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/kylemcc/twitter-text-go/internal/cmdutil"
+	"github.com/kylemcc/twitter-text-go/textjson"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+var extractCommand = &command{
+	summary: "list the mentions, lists, hashtags, cashtags, and URLs in each text",
+	setup: func(fs *flag.FlagSet, o *options) func(io.Writer, string) (bool, error) {
+		kind := fs.String("type", "all", "the `type` of entities to extract: all, mentions, hashtags, cashtags, urls, or emoji")
+		return func(w io.Writer, text string) (bool, error) {
+			extractor, ok := cmdutil.Extractors[*kind]
+			if !ok {
+				return false, fmt.Errorf("invalid type %q", *kind)
+			}
+			entities := extractor(text)
+			if o.format == "text" {
+				for _, e := range entities {
+					fmt.Fprintln(w, e.Text)
+				}
+				return true, nil
+			}
+			return true, writeJSON(w, textjson.NewEntities(entities))
+		}
+	},
+}
+
+var validateCommand = &command{
+	summary: "report whether each text is a valid tweet",
+	setup: func(fs *flag.FlagSet, o *options) func(io.Writer, string) (bool, error) {
+		return func(w io.Writer, text string) (bool, error) {
+			err := validate.ValidateTweetWithConfig(text, o.config)
+			if o.format == "text" {
+				if err != nil {
+					_, werr := fmt.Fprintln(w, err)
+					return false, werr
+				}
+				_, werr := fmt.Fprintln(w, "valid")
+				return true, werr
+			}
+			return err == nil, writeJSON(w, textjson.NewValidation(err))
+		}
+	},
+}
+
+var parseCommand = &command{
+	summary: "report the weighted length, permillage, and validity of each text",
+	setup: func(fs *flag.FlagSet, o *options) func(io.Writer, string) (bool, error) {
+		return func(w io.Writer, text string) (bool, error) {
+			results := validate.ParseTweetWithConfig(text, o.config)
+			if o.format == "text" {
+				_, err := fmt.Fprintln(w, results.WeightedLength, results.Permillage, results.IsValid)
+				return true, err
+			}
+			return true, writeJSON(w, textjson.NewParseResults(results))
+		}
+	},
+}
+
+var autolinkCommand = &command{
+	summary: "convert the entities in each text to HTML links",
+	setup: func(fs *flag.FlagSet, o *options) func(io.Writer, string) (bool, error) {
+		render := fs.String("render", "html", "how to render entities: html, ansi, markdown, or slack")
+		return func(w io.Writer, text string) (bool, error) {
+			renderer, ok := cmdutil.Renderers[*render]
+			if !ok {
+				return false, fmt.Errorf("invalid rendering %q", *render)
+			}
+			result := renderer(text)
+			if o.format == "text" {
+				_, err := fmt.Fprintln(w, result)
+				return true, err
+			}
+			return true, writeJSON(w, result)
+		}
+	},
+}
+
+// Writes v to w as JSON followed by a newline. HTML is not escaped, since
+// autolink results are HTML
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
@@ -0,0 +1,199 @@
+// Command twtext extracts entities from, validates, parses, and auto-links
+// tweets from the command line, for use in shell pipelines.
+//
+// Usage:
+//
+//	twtext command [flags] [text ...]
+//
+// The commands are:
+//
+//	extract    list the mentions, lists, hashtags, cashtags, and URLs in each text
+//	validate   report whether each text is a valid tweet
+//	parse      report the weighted length, permillage, and validity of each text
+//	autolink   convert the entities in each text to HTML links
+//
+// If text arguments are given, they are joined with spaces into a single
+// text. Otherwise, each line read from standard input is a separate text,
+// or, with -whole, all of standard input is a single text.
+//
+// Results are written one per line, as JSON by default, in the shapes used
+// by the twitter-text libraries:
+//
+//	$ twtext parse 'Hello @world'
+//	{"weightedLength":12,"permillage":42,"valid":true}
+//	$ twtext extract 'Hello @world'
+//	[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]
+//
+// Indices count characters (code points), as in the extract package.
+// validate writes {"valid":false,"error":"..."} for an invalid text, and
+// autolink writes the HTML as a JSON string. extract -type limits the
+// entities to mentions, hashtags, cashtags, urls, or emoji, and autolink
+// -render renders them as ansi, markdown, or slack rather than html.
+//
+// With -format text, results are written as plain text instead: extract
+// writes the text of each entity on its own line, validate writes "valid"
+// or the reason the text is invalid, parse writes the weighted length,
+// permillage, and validity separated by spaces, and autolink writes the
+// rendered text.
+//
+// Texts are weighed under the twitter-v3 configuration unless -config
+// names another preset or a JSON configuration file. validate exits with
+// status 1 if any text is invalid.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/kylemcc/twitter-text-go/config"
+)
+
+// The commands, by name
+var commands = map[string]*command{
+	"extract":  extractCommand,
+	"validate": validateCommand,
+	"parse":    parseCommand,
+	"autolink": autolinkCommand,
+}
+
+// A command processes texts and writes a result for each
+type command struct {
+	summary string
+
+	// Adds the command's own flags to fs, and returns a function that
+	// processes a text, writing its result to w. The function reports
+	// whether the text is acceptable, which only validate uses
+	setup func(fs *flag.FlagSet, o *options) func(w io.Writer, text string) (bool, error)
+}
+
+// The flags shared by all commands
+type options struct {
+	format string
+	whole  bool
+	config *config.Config
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Runs the command given by args, returning the exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || commands[args[0]] == nil {
+		usage(stderr)
+		return 2
+	}
+	name, cmd := args[0], commands[args[0]]
+
+	fs := flag.NewFlagSet("twtext "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	o := &options{config: config.V3()}
+	fs.StringVar(&o.format, "format", "json", "output `format`: json or text")
+	fs.BoolVar(&o.whole, "whole", false, "read all of standard input as a single text")
+	fs.Var(configFlag{o}, "config", "the `preset` or JSON configuration file to weigh texts under")
+	process := cmd.setup(fs, o)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: twtext %s [flags] [text ...]\n\n%s.\n\nFlags:\n", name, cmd.summary)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if o.format != "json" && o.format != "text" {
+		fmt.Fprintf(stderr, "twtext %s: invalid format %q\n", name, o.format)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	ok, err := forEachText(fs.Args(), stdin, o.whole, func(text string) (bool, error) {
+		return process(out, text)
+	})
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "twtext %s: %v\n", name, err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// Writes the list of commands
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: twtext command [flags] [text ...]\n\nCommands:\n")
+	for _, name := range []string{"extract", "validate", "parse", "autolink"} {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun twtext command -h for the command's flags.\n")
+}
+
+// Calls f for each text: the arguments joined with spaces, if there are
+// any, and otherwise each line of stdin, or all of stdin if whole is true.
+// Reports whether f returned true for every text, and stops at the first
+// error
+func forEachText(args []string, stdin io.Reader, whole bool, f func(text string) (bool, error)) (bool, error) {
+	if len(args) > 0 {
+		return f(strings.Join(args, " "))
+	}
+	if whole {
+		b, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return false, err
+		}
+		return f(string(b))
+	}
+
+	allOK := true
+	r := bufio.NewReader(stdin)
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			ok, ferr := f(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+			if ferr != nil {
+				return false, ferr
+			}
+			allOK = allOK && ok
+		}
+		if err == io.EOF {
+			return allOK, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// A flag.Value that sets the configuration from a preset name or a file
+type configFlag struct {
+	o *options
+}
+
+func (f configFlag) String() string {
+	return config.PresetTwitterV3
+}
+
+func (f configFlag) Set(value string) error {
+	if c, err := config.Preset(value); err == nil {
+		f.o.config = c
+		return nil
+	}
+	file, err := os.Open(value)
+	if err != nil {
+		return fmt.Errorf("not a preset (%s) or a readable file", strings.Join(config.PresetNames(), ", "))
+	}
+	defer file.Close()
+	c, err := config.LoadConfig(file)
+	if err != nil {
+		return err
+	}
+	f.o.config = c
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		stdin    string
+		expected string
+		status   int
+	}{
+		{[]string{"parse", "Hello", "@world"}, "", `{"weightedLength":12,"permillage":42,"valid":true}` + "\n", 0},
+		{[]string{"parse", "-format", "text", "Hello @world"}, "", "12 42 true\n", 0},
+		{[]string{"parse", "-config", "twitter-v1", "日本"}, "", `{"weightedLength":2,"permillage":14,"valid":true}` + "\n", 0},
+		{[]string{"extract", "Hello @world"}, "", `[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]` + "\n", 0},
+		{[]string{"extract"}, "@a/b #c\r\n$D http://e.com\n\nnothing", `[{"type":"mention","text":"@a/b","indices":[0,4],"screenName":"a","listSlug":"/b"},{"type":"hashtag","text":"#c","indices":[5,7],"hashtag":"c"}]` + "\n" +
+			`[{"type":"cashtag","text":"$D","indices":[0,2],"cashtag":"D"},{"type":"url","text":"http://e.com","indices":[3,15],"url":"http://e.com"}]` + "\n[]\n[]\n", 0},
+		{[]string{"extract", "-format", "text", "-type", "hashtags", "#a @b #c"}, "", "#a\n#c\n", 0},
+		{[]string{"extract", "-whole", "-format", "text"}, "#a\n#b\n", "#a\n#b\n", 0},
+		{[]string{"validate"}, "valid\n\n" + strings.Repeat("x", 281), `{"valid":true}` + "\n" + `{"valid":false,"error":"Tweets may not be empty"}` + "\n" + `{"valid":false,"error":"Length 281 exceeds the maximum tweet length"}` + "\n", 1},
+		{[]string{"validate", "-format", "text", "valid"}, "", "valid\n", 0},
+		{[]string{"autolink", "#hashtag"}, "", `"<a href=\"https://twitter.com/search?q=%23hashtag\" title=\"#hashtag\" class=\"tweet-url hashtag\" rel=\"nofollow\">#hashtag</a>"` + "\n", 0},
+		{[]string{"autolink", "-format", "text", "-render", "markdown", "@user"}, "", "[@user](https://twitter.com/user)\n", 0},
+		{nil, "", "", 2},
+		{[]string{"unknown"}, "", "", 2},
+		{[]string{"parse", "-format", "xml", "text"}, "", "", 2},
+		{[]string{"parse", "-config", "no-such-preset", "text"}, "", "", 2},
+		{[]string{"extract", "-type", "unknown", "text"}, "", "", 1},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+		if status != test.status || stdout.String() != test.expected {
+			t.Errorf("run returned incorrect value for args %q and input [%s]. Expected:%d [%s] Got:%d [%s] %s", test.args, test.stdin, test.status, test.expected, status, stdout.String(), stderr.String())
+		}
+	}
+}
+
+func TestRunConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "twtext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(name, []byte(`{"version":1,"maxWeightedTweetLength":10,"scale":1,"defaultWeight":1,"transformedURLLength":23}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"parse", "-config", name, "Hello world"}, strings.NewReader(""), &stdout, &stderr)
+	expected := `{"weightedLength":11,"permillage":1100,"valid":false}` + "\n"
+	if status != 0 || stdout.String() != expected {
+		t.Errorf("run returned incorrect value for config file. Expected:0 [%s] Got:%d [%s] %s", expected, status, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/internal/cmdutil"
+	"github.com/kylemcc/twitter-text-go/textjson"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// The body of a request to any endpoint
+type request struct {
+	Text   string `json:"text"`
+	Config string `json:"config"` // The preset to use in place of the default
+	Type   string `json:"type"`   // The entities /extract returns
+	Render string `json:"render"` // How /autolink renders entities
+}
+
+// The body of a response to /autolink
+type autolinkResponse struct {
+	HTML string `json:"html"`
+}
+
+// The body of a response to an invalid request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Returns a handler serving the endpoints and their metrics, which weighs
+// texts under c unless a request names another preset, and rejects
+// request bodies larger than maxBodySize bytes. c must not be modified
+// while the handler is in use
+func newHandler(c *config.Config, maxBodySize int64) http.Handler {
+	s := &server{config: c, maxBodySize: maxBodySize, metrics: newMetrics()}
+	mux := http.NewServeMux()
+	mux.Handle("/parse", s.metrics.instrument("parse", s.endpoint(s.parse)))
+	mux.Handle("/validate", s.metrics.instrument("validate", s.endpoint(s.validate)))
+	mux.Handle("/extract", s.metrics.instrument("extract", s.endpoint(s.extract)))
+	mux.Handle("/autolink", s.metrics.instrument("autolink", s.endpoint(s.autolink)))
+	mux.Handle("/metrics", s.metrics.handler())
+	return mux
+}
+
+type server struct {
+	config      *config.Config
+	maxBodySize int64
+	metrics     *metrics
+}
+
+// Returns a handler that decodes the request body, calls f with it and the
+// configuration it names, and writes the value f returns as JSON. Errors
+// returned by f are errors in the request
+func (s *server) endpoint(f func(req *request, c *config.Config) (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+			return
+		}
+
+		var req request
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodySize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			status := http.StatusBadRequest
+			// http.MaxBytesError is not available before Go 1.19
+			if err.Error() == "http: request body too large" {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeJSON(w, status, errorResponse{fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+
+		c := s.config
+		if req.Config != "" {
+			preset, err := config.Preset(req.Config)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+				return
+			}
+			c = preset
+		}
+
+		result, err := f(&req, c)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+func (s *server) parse(req *request, c *config.Config) (interface{}, error) {
+	return textjson.NewParseResults(validate.ParseTweetWithConfig(req.Text, c)), nil
+}
+
+func (s *server) validate(req *request, c *config.Config) (interface{}, error) {
+	return textjson.NewValidation(validate.ValidateTweetWithConfig(req.Text, c)), nil
+}
+
+func (s *server) extract(req *request, c *config.Config) (interface{}, error) {
+	kind := req.Type
+	if kind == "" {
+		kind = "all"
+	}
+	extractor, ok := cmdutil.Extractors[kind]
+	if !ok {
+		return nil, fmt.Errorf("invalid type %q", kind)
+	}
+	entities := extractor(req.Text)
+	s.metrics.entities.WithLabelValues(kind).Observe(float64(len(entities)))
+	return textjson.NewEntities(entities), nil
+}
+
+func (s *server) autolink(req *request, c *config.Config) (interface{}, error) {
+	render := req.Render
+	if render == "" {
+		render = "html"
+	}
+	renderer, ok := cmdutil.Renderers[render]
+	if !ok {
+		return nil, fmt.Errorf("invalid rendering %q", render)
+	}
+	return autolinkResponse{renderer(req.Text)}, nil
+}
+
+// Writes v as the JSON body of a response with the given status. HTML is
+// not escaped, since autolink results are HTML
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		status   int
+		expected string
+	}{
+		{"POST", "/parse", `{"text":"Hello @world"}`, 200, `{"weightedLength":12,"permillage":42,"valid":true}`},
+		{"POST", "/parse", `{"text":"日本","config":"twitter-v1"}`, 200, `{"weightedLength":2,"permillage":14,"valid":true}`},
+		{"POST", "/validate", `{"text":"Hello"}`, 200, `{"valid":true}`},
+		{"POST", "/validate", `{"text":""}`, 200, `{"valid":false,"error":"Tweets may not be empty"}`},
+		{"POST", "/extract", `{"text":"Hello @world"}`, 200, `[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]`},
+		{"POST", "/extract", `{"text":"#a @b #c","type":"hashtags"}`, 200, `[{"type":"hashtag","text":"#a","indices":[0,2],"hashtag":"a"},{"type":"hashtag","text":"#c","indices":[6,8],"hashtag":"c"}]`},
+		{"POST", "/extract", `{"text":"nothing"}`, 200, `[]`},
+		{"POST", "/autolink", `{"text":"@user","render":"markdown"}`, 200, `{"html":"[@user](https://twitter.com/user)"}`},
+		{"POST", "/autolink", `{"text":"#hashtag"}`, 200, `{"html":"<a href=\"https://twitter.com/search?q=%23hashtag\" title=\"#hashtag\" class=\"tweet-url hashtag\" rel=\"nofollow\">#hashtag</a>"}`},
+		{"GET", "/parse", ``, 405, `{"error":"method not allowed"}`},
+		{"POST", "/parse", `{"text":`, 400, `{"error":"invalid request body: unexpected EOF"}`},
+		{"POST", "/parse", `{"txt":"Hello"}`, 400, `{"error":"invalid request body: json: unknown field \"txt\""}`},
+		{"POST", "/parse", `{"text":"Hello","config":"unknown"}`, 400, `{"error":"config: unknown preset \"unknown\""}`},
+		{"POST", "/extract", `{"text":"Hello","type":"unknown"}`, 400, `{"error":"invalid type \"unknown\""}`},
+		{"POST", "/autolink", `{"text":"Hello","render":"unknown"}`, 400, `{"error":"invalid rendering \"unknown\""}`},
+		{"POST", "/parse", `{"text":"` + strings.Repeat("x", 100) + `"}`, 413, `{"error":"invalid request body: http: request body too large"}`},
+		{"POST", "/unknown", `{"text":"Hello"}`, 404, "404 page not found"},
+	}
+
+	handler := newHandler(config.V3(), 64)
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(test.method, test.path, strings.NewReader(test.body)))
+		if actual := strings.TrimSuffix(w.Body.String(), "\n"); w.Code != test.status || actual != test.expected {
+			t.Errorf("%s %s returned incorrect value for body [%s]. Expected:%d %s Got:%d %s", test.method, test.path, test.body, test.status, test.expected, w.Code, actual)
+		}
+	}
+}
+
+func TestHandlerContentType(t *testing.T) {
+	w := httptest.NewRecorder()
+	newHandler(config.V3(), 1<<20).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/parse", strings.NewReader(`{"text":"Hello"}`)))
+	if actual := w.Header().Get("Content-Type"); actual != "application/json; charset=utf-8" {
+		t.Errorf("Content-Type was incorrect. Expected:application/json; charset=utf-8 Got:%s", actual)
+	}
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	handler := newHandler(config.V3(), 1<<20)
+	for _, body := range []string{`{"text":"@a #b"}`, `{"text":"#a #b #c","type":"hashtags"}`, `{"text":"none"}`, `{"text":"x","type":"unknown"}`} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/extract", strings.NewReader(body)))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/parse", nil))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics returned incorrect status. Expected:200 Got:%d", w.Code)
+	}
+
+	metrics := w.Body.String()
+	for _, expected := range []string{
+		`twtextd_requests_total{code="200",endpoint="extract",method="post"} 3`,
+		`twtextd_requests_total{code="400",endpoint="extract",method="post"} 1`,
+		`twtextd_requests_total{code="405",endpoint="parse",method="get"} 1`,
+		`twtextd_request_duration_seconds_count{endpoint="extract"} 4`,
+		`twtextd_extracted_entities_sum{type="all"} 2`,
+		`twtextd_extracted_entities_count{type="all"} 2`,
+		`twtextd_extracted_entities_bucket{type="all",le="0"} 1`,
+		`twtextd_extracted_entities_sum{type="hashtags"} 3`,
+		"go_goroutines ",
+	} {
+		if !strings.Contains(metrics, expected) {
+			t.Errorf("GET /metrics did not include [%s]. Got:%s", expected, metrics)
+		}
+	}
+	if strings.Contains(metrics, `type="unknown"`) {
+		t.Errorf("GET /metrics included the entities of an invalid request")
+	}
+}
@@ -0,0 +1,90 @@
+// Command twtextd serves the twitter-text functions over HTTP, so that
+// services written in other languages can extract entities from,
+// validate, parse, and auto-link tweets the same way Go services do.
+//
+// Usage:
+//
+//	twtextd [-addr :8080] [-config twitter-v3]
+//
+// Each endpoint accepts a POST request with a JSON body holding the text:
+//
+//	{"text": "Hello @world", "config": "twitter-v3"}
+//
+// config optionally names the preset to weigh the text under, in place of
+// the one given by the -config flag. The endpoints respond with JSON in the
+// shapes used by the twitter-text libraries:
+//
+//	POST /parse     {"weightedLength":12,"permillage":42,"valid":true}
+//	POST /validate  {"valid":false,"error":"Tweets may not be empty"}
+//	POST /extract   [{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]
+//	POST /autolink  {"html":"Hello @<a ...>world</a>"}
+//
+// /extract also accepts "type", one of all (the default), mentions,
+// hashtags, cashtags, urls, or emoji, and /autolink accepts "render", one
+// of html (the default), ansi, markdown, or slack. Invalid requests are
+// answered with status 400 and a body such as {"error":"..."}.
+//
+// GET /metrics serves metrics in the Prometheus format: the number of
+// requests to each endpoint by status code (twtextd_requests_total), the
+// time taken to serve them (twtextd_request_duration_seconds), and the
+// number of entities returned by /extract (twtextd_extracted_entities),
+// along with the Go runtime and process metrics.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kylemcc/twitter-text-go/config"
+)
+
+var (
+	addr        = flag.String("addr", ":8080", "the `address` to listen on")
+	configName  = flag.String("config", config.PresetTwitterV3, "the `preset` or JSON configuration file to weigh texts under by default")
+	maxBodySize = flag.Int64("max-body-size", 1<<20, "the maximum size of a request body, in `bytes`")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: twtextd [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c, err := loadConfig(*configName)
+	if err != nil {
+		log.Fatalf("twtextd: %v", err)
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newHandler(c, *maxBodySize),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	log.Printf("twtextd: listening on %s", *addr)
+	log.Fatal(server.ListenAndServe())
+}
+
+// Returns the named preset, or the configuration in the named file
+func loadConfig(name string) (*config.Config, error) {
+	if c, err := config.Preset(name); err == nil {
+		return c, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a preset or a readable file", name)
+	}
+	defer f.Close()
+	return config.LoadConfig(f)
+}
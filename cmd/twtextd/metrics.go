@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The Prometheus metrics of the service, served at /metrics
+type metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	entities *prometheus.HistogramVec
+}
+
+// Returns the metrics of the service, registered with a new registry
+// along with the Go runtime and process metrics
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "twtextd_requests_total",
+			Help: "The number of requests to each endpoint, by status code and method.",
+		}, []string{"endpoint", "code", "method"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "twtextd_request_duration_seconds",
+			Help:    "The time taken to serve requests to each endpoint.",
+			Buckets: []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"endpoint"}),
+		entities: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "twtextd_extracted_entities",
+			Help:    "The number of entities returned by /extract, by the type requested.",
+			Buckets: []float64{0, 1, 2, 3, 5, 8, 13, 21, 34, 55},
+		}, []string{"type"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.entities,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Returns h, counting and timing its requests as the named endpoint
+func (m *metrics) instrument(endpoint string, h http.Handler) http.Handler {
+	labels := prometheus.Labels{"endpoint": endpoint}
+	h = promhttp.InstrumentHandlerCounter(m.requests.MustCurryWith(labels), h)
+	return promhttp.InstrumentHandlerDuration(m.duration.MustCurryWith(labels), h)
+}
+
+// Returns a handler serving the metrics in the Prometheus exposition
+// format
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,68 @@
+// Command twtextgrpc serves the TwitterText gRPC service defined in
+// rpc/twtextpb/twtext.proto, for services that use gRPC rather than the
+// JSON endpoints of twtextd.
+//
+// Usage:
+//
+//	twtextgrpc [-addr :9090] [-config twitter-v3]
+//
+// Texts are weighed under the configuration given by -config, a preset or
+// a JSON configuration file, unless a request names another preset.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/rpc"
+	"github.com/kylemcc/twitter-text-go/rpc/twtextpb"
+	"google.golang.org/grpc"
+)
+
+var (
+	addr       = flag.String("addr", ":9090", "the `address` to listen on")
+	configName = flag.String("config", config.PresetTwitterV3, "the `preset` or JSON configuration file to weigh texts under by default")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: twtextgrpc [flags]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c, err := loadConfig(*configName)
+	if err != nil {
+		log.Fatalf("twtextgrpc: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("twtextgrpc: %v", err)
+	}
+	s := grpc.NewServer()
+	twtextpb.RegisterTwitterTextServer(s, rpc.NewServer(c))
+	log.Printf("twtextgrpc: listening on %s", lis.Addr())
+	log.Fatal(s.Serve(lis))
+}
+
+// Returns the named preset, or the configuration in the named file
+func loadConfig(name string) (*config.Config, error) {
+	if c, err := config.Preset(name); err == nil {
+		return c, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a preset or a readable file", name)
+	}
+	defer f.Close()
+	return config.LoadConfig(f)
+}
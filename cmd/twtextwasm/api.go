@@ -0,0 +1,143 @@
+// Command twtextwasm exposes the twitter-text functions to JavaScript when
+// built for WebAssembly, so that web front-ends count, extract, and
+// auto-link tweets exactly as Go services do rather than with the separate
+// twitter-text-js. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o twtext.wasm github.com/kylemcc/twitter-text-go/cmd/twtextwasm
+//
+// and load it with the wasm_exec.js support file that comes with Go:
+//
+//	const go = new Go();
+//	const { instance } = await WebAssembly.instantiateStreaming(fetch("twtext.wasm"), go.importObject);
+//	go.run(instance);
+//	twitterText.parseTweet("Hello @world"); // {weightedLength: 12, permillage: 42, valid: true}
+//
+// Running the program defines a global twitterText object with these
+// functions, named and shaped as in twitter-text-js:
+//
+//	parseTweet(text, config)
+//	extractEntitiesWithIndices(text)
+//	extractUrlsWithIndices(text)
+//	extractHashtagsWithIndices(text)
+//	extractMentionsOrListsWithIndices(text)
+//	extractCashtagsWithIndices(text)
+//	autoLink(text)
+//
+// config is optional, and is either the name of a preset, such as
+// "twitter-v3", the default, or a configuration object such as
+// twitter-text-js takes. As in twitter-text-js, indices count UTF-16 code
+// units, so they can be used to slice JavaScript strings. The functions
+// return an Error, rather than throwing it, for an invalid configuration.
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// The functions defined on the twitterText object, by name. Each takes its
+// arguments as strings, with a configuration object given as JSON, and
+// returns a value that js.ValueOf accepts
+var functions = map[string]func(args []string) (interface{}, error){
+	"parseTweet": func(args []string) (interface{}, error) {
+		c, err := configFor(arg(args, 1))
+		if err != nil {
+			return nil, err
+		}
+		results := validate.ParseTweetWithConfig(arg(args, 0), c)
+		return map[string]interface{}{
+			"weightedLength": results.WeightedLength,
+			"permillage":     results.Permillage,
+			"valid":          results.IsValid,
+		}, nil
+	},
+	"extractEntitiesWithIndices":        extractor(extract.ExtractEntities),
+	"extractUrlsWithIndices":            extractor(extract.ExtractUrls),
+	"extractHashtagsWithIndices":        extractor(extract.ExtractHashtags),
+	"extractMentionsOrListsWithIndices": extractor(extract.ExtractMentionsOrLists),
+	"extractCashtagsWithIndices":        extractor(extract.ExtractCashtags),
+	"autoLink": func(args []string) (interface{}, error) {
+		return autolink.AutoLink(arg(args, 0)), nil
+	},
+}
+
+// Returns the ith argument, or "" if there are fewer arguments
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// Returns the configuration given by a preset name or a JSON configuration
+// object, or the default configuration if spec is empty
+func configFor(spec string) (*config.Config, error) {
+	switch {
+	case spec == "":
+		return config.V3(), nil
+	case strings.HasPrefix(spec, "{"):
+		return config.LoadConfig(strings.NewReader(spec))
+	}
+	return config.Preset(spec)
+}
+
+// Returns a function that returns the entities f extracts from its first
+// argument, in the shape twitter-text-js uses
+func extractor(f func(string) []*extract.TwitterEntity) func([]string) (interface{}, error) {
+	return func(args []string) (interface{}, error) {
+		text := arg(args, 0)
+		entities := f(text)
+		offsets := utf16Offsets{text: text}
+		result := make([]interface{}, 0, len(entities))
+		for _, e := range entities {
+			result = append(result, entityValue(e, offsets.at(e.ByteRange.Start), offsets.at(e.ByteRange.Stop)))
+		}
+		return result, nil
+	}
+}
+
+// Returns e as twitter-text-js represents it, with the given indices
+func entityValue(e *extract.TwitterEntity, start, stop int) map[string]interface{} {
+	v := map[string]interface{}{"indices": []interface{}{start, stop}}
+	switch e.Type {
+	case extract.URL:
+		v["url"] = e.Text
+	case extract.HASH_TAG:
+		v["hashtag"], _ = e.Hashtag()
+	case extract.MENTION:
+		v["screenName"], _ = e.ScreenName()
+		v["listSlug"], _ = e.ListSlug()
+	case extract.CASH_TAG:
+		v["cashtag"], _ = e.Cashtag()
+	}
+	return v
+}
+
+// Converts byte offsets in text to UTF-16 offsets. Converting offsets in
+// increasing order takes time proportional to the length of the text
+type utf16Offsets struct {
+	text  string
+	pos   int // A byte offset in text
+	units int // The number of UTF-16 code units before pos
+}
+
+// Returns the UTF-16 offset of the byte offset i
+func (o *utf16Offsets) at(i int) int {
+	if i < o.pos {
+		o.pos, o.units = 0, 0
+	}
+	for o.pos < i {
+		r, size := utf8.DecodeRuneInString(o.text[o.pos:])
+		o.pos += size
+		o.units++
+		if r >= 0x10000 {
+			o.units++
+		}
+	}
+	return o.units
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTweet(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected map[string]interface{}
+	}{
+		{[]string{"Hello @world"}, map[string]interface{}{"weightedLength": 12, "permillage": 42, "valid": true}},
+		{[]string{"日本", "twitter-v1"}, map[string]interface{}{"weightedLength": 2, "permillage": 14, "valid": true}},
+		{[]string{"Hello world", `{"version":1,"maxWeightedTweetLength":10,"scale":1,"defaultWeight":1,"transformedURLLength":23}`}, map[string]interface{}{"weightedLength": 11, "permillage": 1100, "valid": false}},
+	}
+
+	for _, test := range tests {
+		actual, err := functions["parseTweet"](test.args)
+		if err != nil || !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("parseTweet returned incorrect value for args %q. Expected:%v Got:%v %v", test.args, test.expected, actual, err)
+		}
+	}
+
+	if _, err := functions["parseTweet"]([]string{"Hello", "unknown"}); err == nil {
+		t.Errorf("parseTweet did not return an error for an unknown preset")
+	}
+}
+
+func TestExtractWithIndices(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []interface{}
+	}{
+		{"extractEntitiesWithIndices", "", []interface{}{}},
+		{"extractEntitiesWithIndices", "@a/b #c $D http://e.com", []interface{}{
+			map[string]interface{}{"screenName": "a", "listSlug": "/b", "indices": []interface{}{0, 4}},
+			map[string]interface{}{"hashtag": "c", "indices": []interface{}{5, 7}},
+			map[string]interface{}{"cashtag": "D", "indices": []interface{}{8, 10}},
+			map[string]interface{}{"url": "http://e.com", "indices": []interface{}{11, 23}},
+		}},
+		// Characters outside the BMP are two UTF-16 code units
+		{"extractHashtagsWithIndices", "\U0001F600 #a \U0001F600\U0001F600 #b", []interface{}{
+			map[string]interface{}{"hashtag": "a", "indices": []interface{}{3, 5}},
+			map[string]interface{}{"hashtag": "b", "indices": []interface{}{11, 13}},
+		}},
+		{"extractMentionsOrListsWithIndices", "日本 @a", []interface{}{
+			map[string]interface{}{"screenName": "a", "listSlug": "", "indices": []interface{}{3, 5}},
+		}},
+		{"extractUrlsWithIndices", "@a example.com", []interface{}{
+			map[string]interface{}{"url": "example.com", "indices": []interface{}{3, 14}},
+		}},
+		{"extractCashtagsWithIndices", "#a $B", []interface{}{
+			map[string]interface{}{"cashtag": "B", "indices": []interface{}{3, 5}},
+		}},
+	}
+
+	for _, test := range tests {
+		actual, err := functions[test.name]([]string{test.text})
+		if err != nil || !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s returned incorrect value for text [%s]. Expected:%v Got:%v %v", test.name, test.text, test.expected, actual, err)
+		}
+	}
+}
+
+func TestUtf16Offsets(t *testing.T) {
+	offsets := utf16Offsets{text: "aé\U0001F600b"}
+	for _, test := range []struct{ byteOffset, expected int }{{0, 0}, {1, 1}, {3, 2}, {7, 4}, {8, 5}, {3, 2}} {
+		if actual := offsets.at(test.byteOffset); actual != test.expected {
+			t.Errorf("at returned incorrect value for offset %d. Expected:%d Got:%d", test.byteOffset, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLink(t *testing.T) {
+	expected := `@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`
+	if actual, err := functions["autoLink"]([]string{"@user"}); err != nil || actual != expected {
+		t.Errorf("autoLink returned incorrect value for text [@user]. Expected:%s Got:%v %v", expected, actual, err)
+	}
+}
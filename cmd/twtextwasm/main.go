@@ -0,0 +1,42 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"syscall/js"
+)
+
+func main() {
+	api := js.Global().Get("Object").New()
+	for name, f := range functions {
+		api.Set(name, js.FuncOf(wrap(f)))
+	}
+	js.Global().Set("twitterText", api)
+
+	// The functions are called from JavaScript for as long as the page
+	// runs, so the program must not exit
+	select {}
+}
+
+// Adapts f to be called from JavaScript. Objects passed as arguments are
+// converted to JSON, and errors are returned as JavaScript Errors
+func wrap(f func(args []string) (interface{}, error)) func(js.Value, []js.Value) interface{} {
+	return func(this js.Value, args []js.Value) interface{} {
+		strs := make([]string, len(args))
+		for i, a := range args {
+			switch a.Type() {
+			case js.TypeUndefined, js.TypeNull:
+			case js.TypeObject:
+				strs[i] = js.Global().Get("JSON").Call("stringify", a).String()
+			default:
+				strs[i] = a.String()
+			}
+		}
+		result, err := f(strs)
+		if err != nil {
+			return js.Global().Get("Error").New(err.Error())
+		}
+		return result
+	}
+}
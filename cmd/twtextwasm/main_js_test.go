@@ -0,0 +1,37 @@
+//go:build js && wasm
+// +build js,wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	parseTweet := js.FuncOf(wrap(functions["parseTweet"]))
+	defer parseTweet.Release()
+
+	result := parseTweet.Invoke("Hello world")
+	if actual := result.Get("weightedLength").Int(); actual != 11 {
+		t.Errorf("parseTweet returned incorrect value for text [Hello world]. Expected:11 Got:%d", actual)
+	}
+
+	c := js.Global().Get("JSON").Call("parse", `{"version":1,"maxWeightedTweetLength":10,"scale":1,"defaultWeight":1,"transformedURLLength":23}`)
+	result = parseTweet.Invoke("Hello world", c)
+	if actual := result.Get("valid").Bool(); actual {
+		t.Errorf("parseTweet returned incorrect value for text [Hello world] and a configuration object. Expected:false Got:%v", actual)
+	}
+
+	result = parseTweet.Invoke("Hello world", "unknown")
+	if !result.InstanceOf(js.Global().Get("Error")) {
+		t.Errorf("parseTweet did not return an Error for an unknown preset. Got:%v", result)
+	}
+
+	extract := js.FuncOf(wrap(functions["extractEntitiesWithIndices"]))
+	defer extract.Release()
+	result = extract.Invoke("\U0001F600 @a")
+	if actual := result.Index(0).Get("indices").Index(0).Int(); actual != 3 {
+		t.Errorf("extractEntitiesWithIndices returned incorrect value for text [\U0001F600 @a]. Expected:3 Got:%d", actual)
+	}
+}
@@ -0,0 +1,301 @@
+// Package config models the configuration used by the twitter-text
+// libraries to compute the weighted length of a tweet
+//
+// Each version of the configuration corresponds to a change in the way
+// Twitter counts characters. Version 1 counts every character as one,
+// with a limit of 140. Version 2 raised the limit to 280 and gives
+// characters outside of a set of ranges (primarily Latin scripts and
+// punctuation) a weight of two. Version 3 additionally counts each emoji
+// as two characters, regardless of the number of code points it contains.
+// Configurations for other products, such as the presets for other
+// networks, have VersionCustom.
+//
+// The configurations returned by V1, V2, and V3 are parsed from copies of
+// the JSON configuration files published in the twitter-text repository
+// (https://github.com/twitter/twitter-text/tree/master/config), which are
+// embedded in this package.
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+var (
+	//go:embed v1.json
+	v1JSON []byte
+
+	//go:embed v2.json
+	v2JSON []byte
+
+	//go:embed v3.json
+	v3JSON []byte
+
+	v1, v2, v3 = mustParse(v1JSON), mustParse(v2JSON), mustParse(v3JSON)
+)
+
+// The version of configurations that are not one of Twitter's, such as
+// the presets for other networks and configurations for other products.
+// Validate accepts it along with versions 1 to 3
+const VersionCustom = 0
+
+// The weight of the characters from Start to End inclusive
+type Range struct {
+	Start  int `json:"start"`
+	End    int `json:"end"`
+	Weight int `json:"weight"`
+}
+
+// A Config specifies how the weighted length of a tweet is computed and
+// the maximum weighted length of a valid tweet. Weights are scaled by
+// Scale, so that fractional weights can be expressed as integers: with a
+// Scale of 100, a character with a weight of 200 counts as two characters.
+type Config struct {
+	Version                int     `json:"version"`                // The version of the configuration: 1 to 3, or VersionCustom
+	MaxWeightedTweetLength int     `json:"maxWeightedTweetLength"` // The maximum weighted length of a valid tweet
+	Scale                  int     `json:"scale"`                  // The value weights are divided by
+	DefaultWeight          int     `json:"defaultWeight"`          // The weight of characters that are not in any of Ranges
+	Ranges                 []Range `json:"ranges"`                 // The weights of ranges of characters
+	TransformedURLLength   int     `json:"transformedURLLength"`   // The length of a URL after it has been shortened by t.co
+	EmojiParsingEnabled    bool    `json:"emojiParsingEnabled"`    // Whether each emoji is weighted as a single character
+
+	// The following settings are extensions to the twitter-text format,
+	// for products with different rules. They are only written by
+	// MarshalJSON if they are set
+
+	// Whether each grapheme cluster (a user-perceived character, such as
+	// a letter followed by combining accents) counts as a single
+	// character, with the weight of its first code point
+	CountGraphemeClusters bool `json:"countGraphemeClusters"`
+
+	// Whether URLs are counted as ordinary text, instead of as
+	// TransformedURLLength
+	CountURLText bool `json:"countURLText"`
+}
+
+// Returns the version 1 configuration: 140 characters, each counted as one
+func V1() *Config {
+	return v1.Clone()
+}
+
+// Returns the version 2 configuration: 280 characters, with characters
+// outside of the Latin, general punctuation, and similar ranges counted
+// as two
+func V2() *Config {
+	return v2.Clone()
+}
+
+// Returns the version 3 configuration: the version 2 configuration, with
+// emoji parsing enabled
+func V3() *Config {
+	return v3.Clone()
+}
+
+// Returns the weight of the given character: the weight of the first of
+// the configuration's ranges that contains it, or the default weight if
+// none do. Any number of ranges may be configured. To weigh many
+// characters, use a Weigher, which finds the range in logarithmic time.
+func (c *Config) Weight(r rune) int {
+	return weightOf(r, c.Ranges, c.DefaultWeight)
+}
+
+// Returns the weight of the first of ranges that contains r, or
+// defaultWeight if none do
+func weightOf(r rune, ranges []Range, defaultWeight int) int {
+	for _, rng := range ranges {
+		if int(r) >= rng.Start && int(r) <= rng.End {
+			return rng.Weight
+		}
+	}
+	return defaultWeight
+}
+
+// A Weigher returns the weights of characters under a configuration, like
+// Config.Weight. When the configuration's ranges are sorted and do not
+// overlap, as Validate requires, the range containing a character is found
+// with a binary search rather than a linear scan.
+//
+// A Weigher refers to the configuration's ranges, which must not be
+// modified while it is in use. The zero Weigher weighs every character as
+// zero.
+type Weigher struct {
+	ranges        []Range
+	defaultWeight int
+	sorted        bool
+}
+
+// Returns a Weigher for the configuration. Creating a Weigher takes time
+// proportional to the number of ranges, so create one for each text, or
+// batch of texts, rather than for each character
+func (c *Config) Weigher() Weigher {
+	sorted := true
+	for i, rng := range c.Ranges {
+		if rng.Start > rng.End || (i > 0 && rng.Start <= c.Ranges[i-1].End) {
+			sorted = false
+			break
+		}
+	}
+	return Weigher{ranges: c.Ranges, defaultWeight: c.DefaultWeight, sorted: sorted}
+}
+
+// Returns the weight of the given character
+func (w Weigher) Weight(r rune) int {
+	if !w.sorted {
+		return weightOf(r, w.ranges, w.defaultWeight)
+	}
+
+	// Find the first range that ends at or after r
+	lo, hi := 0, len(w.ranges)
+	for lo < hi {
+		m := int(uint(lo+hi) >> 1)
+		if w.ranges[m].End < int(r) {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	if lo < len(w.ranges) && w.ranges[lo].Start <= int(r) {
+		return w.ranges[lo].Weight
+	}
+	return w.defaultWeight
+}
+
+// Reads a configuration in the JSON format of the twitter-text
+// configuration files from r. This allows configuration changes, such as
+// new limits or weight ranges, to be deployed without recompiling.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var c Config
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Returns an error describing the first problem found with the
+// configuration, or nil if it is valid. A valid configuration has a known
+// version, a positive scale and positive weights, a positive maximum
+// length and a non-negative URL length, and ranges that are within the
+// Unicode code space, sorted, and non-overlapping. Configurations read
+// with LoadConfig are always valid; configurations built in code, or
+// supplied by operators, should be validated before they are used.
+func (c *Config) Validate() error {
+	if err := c.checkWeighting(); err != nil {
+		return err
+	}
+	if c.Version != VersionCustom && (c.Version < 1 || c.Version > 3) {
+		return fmt.Errorf("config: unknown version %d", c.Version)
+	}
+	if c.MaxWeightedTweetLength <= 0 {
+		return fmt.Errorf("config: maximum weighted tweet length must be positive, got %d", c.MaxWeightedTweetLength)
+	}
+	if c.TransformedURLLength < 0 {
+		return fmt.Errorf("config: transformed URL length must not be negative, got %d", c.TransformedURLLength)
+	}
+	for i, r := range c.Ranges {
+		if r.Start < 0 || r.End > unicode.MaxRune || r.Start > r.End {
+			return fmt.Errorf("config: range %d-%d is not a valid range of code points", r.Start, r.End)
+		}
+		if i > 0 {
+			prev := c.Ranges[i-1]
+			if r.Start <= prev.End {
+				if r.Start < prev.Start {
+					return fmt.Errorf("config: range %d-%d is not sorted; it must follow range %d-%d", prev.Start, prev.End, r.Start, r.End)
+				}
+				return fmt.Errorf("config: range %d-%d overlaps range %d-%d", r.Start, r.End, prev.Start, prev.End)
+			}
+		}
+	}
+	return nil
+}
+
+// Encodes the configuration in the format of the twitter-text
+// configuration files, so that it can be shared with the twitter-text
+// libraries for other languages. The fields are written in the same order
+// as in those files, and emojiParsingEnabled is omitted unless it is true.
+func (c Config) MarshalJSON() ([]byte, error) {
+	ranges := c.Ranges
+	if ranges == nil {
+		ranges = []Range{}
+	}
+	return json.Marshal(struct {
+		Version                int     `json:"version"`
+		MaxWeightedTweetLength int     `json:"maxWeightedTweetLength"`
+		Scale                  int     `json:"scale"`
+		DefaultWeight          int     `json:"defaultWeight"`
+		EmojiParsingEnabled    bool    `json:"emojiParsingEnabled,omitempty"`
+		TransformedURLLength   int     `json:"transformedURLLength"`
+		Ranges                 []Range `json:"ranges"`
+		CountGraphemeClusters  bool    `json:"countGraphemeClusters,omitempty"`
+		CountURLText           bool    `json:"countURLText,omitempty"`
+	}{
+		c.Version,
+		c.MaxWeightedTweetLength,
+		c.Scale,
+		c.DefaultWeight,
+		c.EmojiParsingEnabled,
+		c.TransformedURLLength,
+		ranges,
+		c.CountGraphemeClusters,
+		c.CountURLText,
+	})
+}
+
+// Sets the scale and default weight of the configuration, returning an
+// error unless both are positive. The configuration is not modified if an
+// error is returned.
+//
+// For example, to count characters outside of the configured ranges as
+// one and a half characters:
+//
+//	c.SetWeighting(100, 150)
+func (c *Config) SetWeighting(scale, defaultWeight int) error {
+	copied := *c
+	copied.Scale, copied.DefaultWeight = scale, defaultWeight
+	if err := copied.checkWeighting(); err != nil {
+		return err
+	}
+	c.Scale, c.DefaultWeight = scale, defaultWeight
+	return nil
+}
+
+// Returns an error if the scale or any of the weights of the configuration
+// are not positive
+func (c *Config) checkWeighting() error {
+	if c.Scale <= 0 {
+		return fmt.Errorf("config: scale must be positive, got %d", c.Scale)
+	}
+	if c.DefaultWeight <= 0 {
+		return fmt.Errorf("config: default weight must be positive, got %d", c.DefaultWeight)
+	}
+	for _, r := range c.Ranges {
+		if r.Weight <= 0 {
+			return fmt.Errorf("config: weight of range %d-%d must be positive, got %d", r.Start, r.End, r.Weight)
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the configuration that shares no memory with it
+func (c *Config) Clone() *Config {
+	copied := *c
+	if c.Ranges != nil {
+		copied.Ranges = make([]Range, len(c.Ranges))
+		copy(copied.Ranges, c.Ranges)
+	}
+	return &copied
+}
+
+// Parses one of the embedded configuration files
+func mustParse(data []byte) *Config {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		panic("config: invalid embedded configuration: " + err.Error())
+	}
+	return &c
+}
@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+var benchmarkRunes = []rune("Latin text, 日本語のテキスト, and 한국어 텍스트")
+
+func BenchmarkWeight(b *testing.B) {
+	c := manyRangesConfig()
+	for i := 0; i < b.N; i++ {
+		for _, r := range benchmarkRunes {
+			c.Weight(r)
+		}
+	}
+}
+
+func BenchmarkWeigher(b *testing.B) {
+	w := manyRangesConfig().Weigher()
+	for i := 0; i < b.N; i++ {
+		for _, r := range benchmarkRunes {
+			w.Weight(r)
+		}
+	}
+}
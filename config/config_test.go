@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	tests := []struct {
+		c             *Config
+		version       int
+		maxLength     int
+		scale         int
+		defaultWeight int
+		ranges        int
+		emoji         bool
+	}{
+		{V1(), 1, 140, 1, 1, 0, false},
+		{V2(), 2, 280, 100, 200, 4, false},
+		{V3(), 3, 280, 100, 200, 4, true},
+	}
+
+	for _, test := range tests {
+		c := test.c
+		if c.Version != test.version || c.MaxWeightedTweetLength != test.maxLength || c.Scale != test.scale ||
+			c.DefaultWeight != test.defaultWeight || len(c.Ranges) != test.ranges || c.EmojiParsingEnabled != test.emoji ||
+			c.TransformedURLLength != 23 {
+			t.Errorf("Incorrect configuration for version %d: %+v", test.version, c)
+		}
+	}
+}
+
+func TestConstructorsReturnCopies(t *testing.T) {
+	c := V2()
+	c.Ranges[0].Weight = 1
+	if V2().Ranges[0].Weight != 100 || V3().Ranges[0].Weight != 100 {
+		t.Errorf("Modifying a Config modified the version 2 defaults")
+	}
+}
+
+func TestEmbeddedRanges(t *testing.T) {
+	expected := []Range{
+		{Start: 0, End: 4351, Weight: 100},
+		{Start: 8192, End: 8205, Weight: 100},
+		{Start: 8208, End: 8223, Weight: 100},
+		{Start: 8242, End: 8247, Weight: 100},
+	}
+	for _, c := range []*Config{V2(), V3()} {
+		if !reflect.DeepEqual(c.Ranges, expected) {
+			t.Errorf("Incorrect ranges for version %d. Expected:%v Got:%v", c.Version, expected, c.Ranges)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	c, err := LoadConfig(bytes.NewReader(v3JSON))
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(c, V3()) {
+		t.Errorf("LoadConfig returned incorrect value. Expected:%+v Got:%+v", V3(), c)
+	}
+
+	for _, data := range []string{`{"version": 2`, `{"version": 2, "scale": 0}`} {
+		if _, err := LoadConfig(strings.NewReader(data)); err == nil {
+			t.Errorf("LoadConfig did not return an error for [%s]", data)
+		}
+	}
+}
+
+func TestWeight(t *testing.T) {
+	c := &Config{
+		Scale:         100,
+		DefaultWeight: 200,
+		Ranges: []Range{
+			{Start: 0, End: 127, Weight: 100},
+			{Start: 0x3040, End: 0x309F, Weight: 150},
+			{Start: 0x1F600, End: 0x1F64F, Weight: 300},
+			{Start: 0, End: 0x10FFFF, Weight: 50},
+		},
+	}
+
+	tests := []struct {
+		r        rune
+		expected int
+	}{
+		{'a', 100},
+		{'の', 150},
+		{'😀', 300},
+		{'日', 50},
+	}
+
+	for _, test := range tests {
+		if actual := c.Weight(test.r); actual != test.expected {
+			t.Errorf("Weight returned incorrect value for [%c]. Expected:%d Got:%d", test.r, test.expected, actual)
+		}
+	}
+
+	if actual := V2().Weight('日'); actual != 200 {
+		t.Errorf("Weight returned incorrect value for [日]. Expected:200 Got:%d", actual)
+	}
+}
+
+// Returns a configuration with many small sorted ranges, as a
+// configuration for a product with finely grained weights might have
+func manyRangesConfig() *Config {
+	c := &Config{Version: 2, MaxWeightedTweetLength: 280, Scale: 100, DefaultWeight: 200}
+	for start := 0; start < 0x20000; start += 64 {
+		c.Ranges = append(c.Ranges, Range{Start: start, End: start + 31, Weight: 100 + start%3})
+	}
+	return c
+}
+
+func TestWeigher(t *testing.T) {
+	unsorted := &Config{
+		DefaultWeight: 200,
+		Ranges: []Range{
+			{Start: 0x3040, End: 0x309F, Weight: 150},
+			{Start: 0, End: 127, Weight: 100},
+			{Start: 0, End: 0x10FFFF, Weight: 50},
+		},
+	}
+	configs := []*Config{V1(), V2(), V3(), Mastodon(), manyRangesConfig(), unsorted, {DefaultWeight: 7}}
+
+	for _, c := range configs {
+		w := c.Weigher()
+		for r := rune(0); r <= 0x20100; r++ {
+			if expected, actual := c.Weight(r), w.Weight(r); actual != expected {
+				t.Errorf("Weigher returned incorrect value for [%U] and version %d. Expected:%d Got:%d", r, c.Version, expected, actual)
+				break
+			}
+		}
+	}
+
+	if actual := (Weigher{}).Weight('a'); actual != 0 {
+		t.Errorf("Zero Weigher returned incorrect value for [a]. Expected:0 Got:%d", actual)
+	}
+}
+
+func TestSetWeighting(t *testing.T) {
+	c := V2()
+	if err := c.SetWeighting(10, 15); err != nil {
+		t.Errorf("SetWeighting returned an error for valid values: %v", err)
+	}
+	if c.Scale != 10 || c.DefaultWeight != 15 {
+		t.Errorf("SetWeighting did not set the scale and default weight: %+v", c)
+	}
+
+	tests := []struct {
+		scale         int
+		defaultWeight int
+		expected      string
+	}{
+		{0, 200, "config: scale must be positive, got 0"},
+		{-100, 200, "config: scale must be positive, got -100"},
+		{100, 0, "config: default weight must be positive, got 0"},
+	}
+	for _, test := range tests {
+		c := V2()
+		err := c.SetWeighting(test.scale, test.defaultWeight)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("SetWeighting(%d, %d) returned incorrect error. Expected:[%s] Got:[%v]", test.scale, test.defaultWeight, test.expected, err)
+		}
+		if c.Scale != 100 || c.DefaultWeight != 200 {
+			t.Errorf("SetWeighting(%d, %d) modified the configuration: %+v", test.scale, test.defaultWeight, c)
+		}
+	}
+}
+
+func TestLoadConfigWeighting(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"scale": 0, "defaultWeight": 1}`, "config: scale must be positive, got 0"},
+		{`{"scale": 1, "defaultWeight": -1}`, "config: default weight must be positive, got -1"},
+		{`{"scale": 1, "defaultWeight": 1, "ranges": [{"start": 0, "end": 127, "weight": 0}]}`, "config: weight of range 0-127 must be positive, got 0"},
+	}
+	for _, test := range tests {
+		_, err := LoadConfig(strings.NewReader(test.data))
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("LoadConfig returned incorrect error for [%s]. Expected:[%s] Got:[%v]", test.data, test.expected, err)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		c        *Config
+		expected []byte
+	}{
+		{V1(), v1JSON},
+		{V2(), v2JSON},
+		{V3(), v3JSON},
+	}
+
+	for _, test := range tests {
+		var expected bytes.Buffer
+		if err := json.Compact(&expected, test.expected); err != nil {
+			t.Fatalf("Error compacting configuration: %v", err)
+		}
+		actual, err := json.Marshal(test.c)
+		if err != nil {
+			t.Errorf("MarshalJSON returned an error for version %d: %v", test.c.Version, err)
+			continue
+		}
+		if !bytes.Equal(actual, expected.Bytes()) {
+			t.Errorf("MarshalJSON returned incorrect value for version %d. Expected:%s Got:%s", test.c.Version, expected.Bytes(), actual)
+		}
+
+		loaded, err := LoadConfig(bytes.NewReader(actual))
+		if err != nil || !reflect.DeepEqual(loaded, test.c) {
+			t.Errorf("LoadConfig did not restore the marshaled configuration for version %d. Expected:%+v Got:%+v (%v)", test.c.Version, test.c, loaded, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range []*Config{V1(), V2(), V3(), Mastodon(), Bluesky(), Generic()} {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate returned an error for a built-in configuration %+v: %v", c, err)
+		}
+	}
+
+	tests := []struct {
+		modify   func(c *Config)
+		expected string
+	}{
+		{func(c *Config) { c.Scale = 0 }, "config: scale must be positive, got 0"},
+		{func(c *Config) { c.Version = 4 }, "config: unknown version 4"},
+		{func(c *Config) { c.Version = -1 }, "config: unknown version -1"},
+		{func(c *Config) { c.MaxWeightedTweetLength = -1 }, "config: maximum weighted tweet length must be positive, got -1"},
+		{func(c *Config) { c.TransformedURLLength = -23 }, "config: transformed URL length must not be negative, got -23"},
+		{func(c *Config) { c.Ranges[1].End = 8000 }, "config: range 8192-8000 is not a valid range of code points"},
+		{func(c *Config) { c.Ranges[3].End = 0x110000 }, "config: range 8242-1114112 is not a valid range of code points"},
+		{func(c *Config) { c.Ranges[1].Start = 4000 }, "config: range 4000-8205 overlaps range 0-4351"},
+		{func(c *Config) { c.Ranges[0], c.Ranges[1] = c.Ranges[1], c.Ranges[0] }, "config: range 8192-8205 is not sorted; it must follow range 0-4351"},
+	}
+
+	for _, test := range tests {
+		c := V2()
+		test.modify(c)
+		err := c.Validate()
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Validate returned incorrect error for %+v. Expected:[%s] Got:[%v]", c, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Names of the configurations returned by Preset
+const (
+	PresetTwitterV1 = "twitter-v1"
+	PresetTwitterV2 = "twitter-v2"
+	PresetTwitterV3 = "twitter-v3"
+	PresetMastodon  = "mastodon"
+	PresetBluesky   = "bluesky"
+	PresetGeneric   = "generic"
+)
+
+var presets = map[string]func() *Config{
+	PresetTwitterV1: V1,
+	PresetTwitterV2: V2,
+	PresetTwitterV3: V3,
+	PresetMastodon:  Mastodon,
+	PresetBluesky:   Bluesky,
+	PresetGeneric:   Generic,
+}
+
+// Returns the configuration for Mastodon's default rules: 500 characters,
+// each counted as one, with every URL counted as 23 characters
+func Mastodon() *Config {
+	return &Config{
+		Version:                VersionCustom,
+		MaxWeightedTweetLength: 500,
+		Scale:                  1,
+		DefaultWeight:          1,
+		Ranges:                 []Range{},
+		TransformedURLLength:   23,
+	}
+}
+
+// Returns the configuration for Bluesky's rules: 300 grapheme clusters,
+// with URLs counted as text
+func Bluesky() *Config {
+	return &Config{
+		Version:                VersionCustom,
+		MaxWeightedTweetLength: 300,
+		Scale:                  1,
+		DefaultWeight:          1,
+		Ranges:                 []Range{},
+		CountGraphemeClusters:  true,
+		CountURLText:           true,
+	}
+}
+
+// Returns a generic configuration: 280 characters, each counted as one,
+// with every URL counted as 23 characters
+func Generic() *Config {
+	return &Config{
+		Version:                VersionCustom,
+		MaxWeightedTweetLength: 280,
+		Scale:                  1,
+		DefaultWeight:          1,
+		Ranges:                 []Range{},
+		TransformedURLLength:   23,
+	}
+}
+
+// Returns the configuration with the given name, e.g. PresetMastodon, so
+// that tools that post to several networks can select the rules for each
+// of them by name
+func Preset(name string) (*Config, error) {
+	if preset, ok := presets[name]; ok {
+		return preset(), nil
+	}
+	return nil, fmt.Errorf("config: unknown preset %q", name)
+}
+
+// Returns the names of all presets in sorted order
+func PresetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
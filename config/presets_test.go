@@ -0,0 +1,49 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLength int
+		version   int
+	}{
+		{PresetTwitterV1, 140, 1},
+		{PresetTwitterV2, 280, 2},
+		{PresetTwitterV3, 280, 3},
+		{PresetMastodon, 500, VersionCustom},
+		{PresetBluesky, 300, VersionCustom},
+		{PresetGeneric, 280, VersionCustom},
+	}
+
+	for _, test := range tests {
+		c, err := Preset(test.name)
+		if err != nil {
+			t.Errorf("Preset returned an error for [%s]: %v", test.name, err)
+			continue
+		}
+		if c.MaxWeightedTweetLength != test.maxLength {
+			t.Errorf("Preset returned incorrect maximum length for [%s]. Expected:%d Got:%d", test.name, test.maxLength, c.MaxWeightedTweetLength)
+		}
+		if c.Version != test.version {
+			t.Errorf("Preset returned incorrect version for [%s]. Expected:%d Got:%d", test.name, test.version, c.Version)
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("Preset returned an invalid configuration for [%s]: %v", test.name, err)
+		}
+	}
+
+	if _, err := Preset("myspace"); err == nil {
+		t.Errorf("Preset did not return an error for an unknown name")
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	expected := []string{"bluesky", "generic", "mastodon", "twitter-v1", "twitter-v2", "twitter-v3"}
+	if actual := PresetNames(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("PresetNames returned incorrect value. Expected:%v Got:%v", expected, actual)
+	}
+}
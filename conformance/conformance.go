@@ -0,0 +1,34 @@
+// Package conformance embeds the twitter-text conformance suites, the YAML
+// files in this directory that the tests of the other packages are run
+// against, so that they can be loaded by programs outside this repository.
+//
+// Suites are vendored unchanged from the conformance directory of
+// https://github.com/twitter/twitter-text at a pinned commit by fetch.go,
+// and REVISION records the source of each suite: the commit it was
+// fetched from, or "local" for a fixture maintained in this repository.
+// Tests that this implementation deliberately does not pass are skipped
+// by the harness rather than removed from the files.
+//
+// It also runs the suites, so that forks and alternative implementations
+// can be held to exactly the same tests. Each Run function takes an
+// implementation of the functions tested by a suite, converted to the
+// types of this package, and runs each section as a subtest:
+//
+//	func TestHitHighlight(t *testing.T) {
+//		conformance.RunHitHighlight(t, hithighlight.NewHighlighter())
+//	}
+//
+// Sections may be named to run only those, e.g. to skip those that an
+// implementation does not support.
+//
+// Programs that use the tests some other way can decode a suite with Load,
+// which converts the expected value of each test to a typed form.
+package conformance
+
+import "embed"
+
+// The vendored conformance suites, of autolink.yml, emoji.yml,
+// extract.yml, hit_highlighting.yml, tlds.yml, and validate.yml
+//
+//go:embed *.yml
+var Files embed.FS
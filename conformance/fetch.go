@@ -0,0 +1,139 @@
+//go:build ignore
+// +build ignore
+
+// Vendors conformance suites from the conformance directory of the
+// twitter-text repository (https://github.com/twitter/twitter-text) at a
+// pinned commit
+//
+// Usage:
+//
+//	go run fetch.go -commit <sha> suite.yml...
+//
+// Only the named suites are fetched, and the commit must be given in full,
+// so that a suite is never replaced with whatever the default branch holds
+// at the time. Every named suite is downloaded before any is written, and
+// the commit each suite was fetched from is recorded in REVISION. The
+// files are written as they are; tests that this implementation
+// deliberately does not pass are listed in skippedTests in harness.go
+// rather than removed.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const repository = "twitter/twitter-text"
+
+var (
+	commit = flag.String("commit", "", "full SHA-1 of the commit of "+repository+" to fetch from")
+
+	validCommit = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+func main() {
+	flag.Parse()
+	if !validCommit.MatchString(*commit) {
+		log.Fatalf("-commit must be a full commit SHA-1, got %q", *commit)
+	}
+	suites := flag.Args()
+	if len(suites) == 0 {
+		log.Fatal("no suites named; e.g. go run fetch.go -commit <sha> autolink.yml")
+	}
+
+	contents := map[string][]byte{}
+	for _, name := range suites {
+		if !strings.HasSuffix(name, ".yml") || strings.ContainsAny(name, `/\`) {
+			log.Fatalf("invalid suite name %q", name)
+		}
+		url := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/conformance/%s", repository, *commit, name)
+		var err error
+		if contents[name], err = get(url); err != nil {
+			log.Fatalf("error fetching %s: %v", name, err)
+		}
+	}
+
+	sources, err := readRevision("REVISION")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range suites {
+		if err := os.WriteFile(name, contents[name], 0644); err != nil {
+			log.Fatal(err)
+		}
+		sources[name] = fmt.Sprintf("https://github.com/%s/blob/%s/conformance/%s", repository, *commit, name)
+	}
+	if err := writeRevision("REVISION", sources); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Fetched %d suites from commit %s\n", len(suites), *commit)
+}
+
+// Returns the source of each suite recorded in the named file
+func readRevision(name string) (map[string]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sources := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid line in %s: %q", name, line)
+		}
+		sources[fields[0]] = strings.TrimSpace(fields[1])
+	}
+	return sources, scanner.Err()
+}
+
+// Rewrites the named file with the given sources, keeping its comments
+func writeRevision(name string, sources map[string]string) error {
+	contents, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			buf.WriteString(line + "\n")
+			continue
+		}
+		suite := strings.SplitN(line, " ", 2)[0]
+		if source, ok := sources[suite]; ok {
+			buf.WriteString(suite + " " + source + "\n")
+			delete(sources, suite)
+		}
+	}
+	for suite, source := range sources {
+		buf.WriteString(suite + " " + source + "\n")
+	}
+	return os.WriteFile(name, buf.Bytes(), 0644)
+}
+
+// Returns the body of a successful response to a GET request for url
+func get(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
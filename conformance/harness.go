@@ -0,0 +1,354 @@
+package conformance
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// The tests of the upstream suites that this implementation deliberately
+// does not pass, by suite and description, with the reason. They are
+// skipped rather than removed from the vendored files, so that the suites
+// stay exactly as they are upstream
+var skippedTests = map[string]map[string]string{}
+
+// Returns the tests in the named sections of the named suite, or all of
+// its sections if none are named, failing t if the suite or any of the
+// sections is missing
+func sections(t *testing.T, name string, names []string) map[string][]Case {
+	suite, err := Load(name)
+	if err != nil {
+		t.Fatalf("Error loading %s: %v", name, err)
+	}
+	if len(names) == 0 {
+		names = make([]string, 0, len(suite.Tests))
+		for section := range suite.Tests {
+			names = append(names, section)
+		}
+	}
+	result := map[string][]Case{}
+	for _, section := range names {
+		tests, ok := suite.Tests[section]
+		if !ok {
+			t.Fatalf("Conformance file %s did not contain '%s' key", name, section)
+		}
+		var kept []Case
+		for _, test := range tests {
+			if reason, ok := skippedTests[name][test.Description]; ok {
+				t.Logf("Skipping test [%s] of %s: %s", test.Description, name, reason)
+				continue
+			}
+			kept = append(kept, test)
+		}
+		result[section] = kept
+	}
+	return result
+}
+
+// Runs f as a subtest for each of the named sections of the named suite
+func runSections(t *testing.T, name string, names []string, f func(t *testing.T, section string, tests []Case)) {
+	tests := sections(t, name, names)
+	sorted := make([]string, 0, len(tests))
+	for section := range tests {
+		sorted = append(sorted, section)
+	}
+	sort.Strings(sorted)
+	for _, section := range sorted {
+		section := section
+		t.Run(section, func(t *testing.T) {
+			f(t, section, tests[section])
+		})
+	}
+}
+
+// An entity found by an implementation under test. Only the fields that
+// apply to the type of the entity are compared
+type Entity struct {
+	ScreenName string
+	ListSlug   string // Including the leading '/'
+	Hashtag    string // Without the '#'
+	Cashtag    string // Without the '$'
+	Url        string
+
+	// The start and end of the entity, counting characters (code points)
+	Indices [2]int
+}
+
+// The extraction functions tested by extract.yml and tlds.yml. Each
+// returns the entities in the order they appear in the text
+type Extractor interface {
+	ExtractMentionedScreenNames(text string) []Entity
+	ExtractMentionsOrLists(text string) []Entity
+
+	// Returns the screen name that text is a reply to, or "" if it is not
+	// a reply
+	ExtractReplyScreenname(text string) string
+
+	ExtractUrls(text string) []Entity
+	ExtractHashtags(text string) []Entity
+	ExtractCashtags(text string) []Entity
+}
+
+// How each section of extract.yml is tested: the function under test, and
+// the field of the entities that is compared
+var extractSections = map[string]struct {
+	function string
+	extract  func(Extractor, string) []Entity
+	field    func(Entity) string
+}{
+	"mentions":                       {"ExtractMentionedScreenNames", Extractor.ExtractMentionedScreenNames, screenName},
+	"mentions_with_indices":          {"ExtractMentionedScreenNames", Extractor.ExtractMentionedScreenNames, screenName},
+	"mentions_or_lists_with_indices": {"ExtractMentionsOrLists", Extractor.ExtractMentionsOrLists, screenName},
+	"urls":                           {"ExtractUrls", Extractor.ExtractUrls, url},
+	"urls_with_indices":              {"ExtractUrls", Extractor.ExtractUrls, url},
+	"hashtags":                       {"ExtractHashtags", Extractor.ExtractHashtags, hashtag},
+	"hashtags_with_indices":          {"ExtractHashtags", Extractor.ExtractHashtags, hashtag},
+	"cashtags":                       {"ExtractCashtags", Extractor.ExtractCashtags, cashtag},
+	"cashtags_with_indices":          {"ExtractCashtags", Extractor.ExtractCashtags, cashtag},
+}
+
+func screenName(e Entity) string { return e.ScreenName }
+func url(e Entity) string        { return e.Url }
+func hashtag(e Entity) string    { return e.Hashtag }
+func cashtag(e Entity) string    { return e.Cashtag }
+
+// Runs the tests in the named sections of extract.yml against x, or all
+// of them if none are named, each section as a subtest
+func RunExtract(t *testing.T, x Extractor, sectionNames ...string) {
+	runSections(t, "extract.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		if section == "replies" {
+			for _, test := range tests {
+				if actual := x.ExtractReplyScreenname(test.Text); actual != test.Expected.Text {
+					t.Errorf("ExtractReplyScreenname returned incorrect value for test [%s]. Expected:[%s] Got:[%s]", test.Description, test.Expected.Text, actual)
+				}
+			}
+			return
+		}
+
+		s, ok := extractSections[section]
+		if !ok {
+			t.Fatalf("Unknown section '%s' of extract.yml", section)
+		}
+		for _, test := range tests {
+			checkEntities(t, s.function, test, s.extract(x, test.Text), s.field)
+		}
+	})
+}
+
+// Runs the tests in the named sections of tlds.yml, or all of them if
+// none are named, against the URLs extracted by x
+func RunTLDs(t *testing.T, x Extractor, sectionNames ...string) {
+	runSections(t, "tlds.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		for _, test := range tests {
+			checkEntities(t, "ExtractUrls", test, x.ExtractUrls(test.Text), url)
+		}
+	})
+}
+
+// Compares the entities returned by the named function with the expected
+// value of test: a list of the values of the compared field, or a list of
+// entities, whose indices and list slugs are also compared
+func checkEntities(t *testing.T, function string, test Case, actual []Entity, field func(Entity) string) {
+	expected := test.Expected.Entities
+	if expected == nil {
+		for _, value := range test.Expected.Values {
+			var e Entity
+			e.ScreenName, e.Url, e.Hashtag, e.Cashtag = value, value, value, value
+			expected = append(expected, e)
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("%s returned incorrect number of entities for test [%s]. Expected:%+v Got:%+v", function, test.Description, expected, actual)
+		return
+	}
+
+	for i, e := range expected {
+		if field(actual[i]) != field(e) {
+			t.Errorf("%s returned incorrect value for test [%s]. Expected:[%s] Got:[%s]", function, test.Description, field(e), field(actual[i]))
+		}
+		if test.Expected.Entities == nil {
+			continue
+		}
+		if actual[i].ListSlug != e.ListSlug {
+			t.Errorf("%s returned incorrect list slug for test [%s]. Expected:[%s] Got:[%s]", function, test.Description, e.ListSlug, actual[i].ListSlug)
+		}
+		if actual[i].Indices != e.Indices {
+			t.Errorf("%s returned incorrect indices for test [%s]. Expected:%v Got:%v", function, test.Description, e.Indices, actual[i].Indices)
+		}
+	}
+}
+
+// The results of parsing a tweet. The ranges are of offsets in UTF-16
+// code units, and include both ends
+type ParseResults struct {
+	WeightedLength int
+	Permillage     int
+	Valid          bool
+
+	DisplayRangeStart int
+	DisplayRangeEnd   int
+	ValidRangeStart   int
+	ValidRangeEnd     int
+}
+
+// The validation functions tested by validate.yml and by the weighted
+// lengths in emoji.yml
+type Validator interface {
+	// Report whether text is a valid tweet, and return its length, under
+	// version 1 of the twitter-text configuration, in which the tweets and
+	// lengths sections are written
+	TweetIsValid(text string) bool
+	TweetLength(text string) int
+
+	UsernameIsValid(username string) bool
+	ListIsValid(list string) bool
+	HashtagIsValid(hashtag string) bool
+
+	// Reports whether url is valid, allowing internationalized domain
+	// names
+	UrlIsValid(url string, requireProtocol bool) bool
+
+	// Returns the results of parsing text under the given version of the
+	// twitter-text configuration: 2 or 3
+	ParseTweet(text string, version int) ParseResults
+}
+
+// The version of the configuration that each section of weighted length
+// tests is written for
+var weightedSections = map[string]int{
+	"WeightedTweetsCounterTest":                    2,
+	"WeightedTweetsWithDiscountedEmojiCounterTest": 3,
+	"UnicodeDirectionalMarkerCounterTest":          3,
+}
+
+// Runs the tests in the named sections of validate.yml against v, or all
+// of them if none are named, each section as a subtest
+func RunValidate(t *testing.T, v Validator, sectionNames ...string) {
+	runSections(t, "validate.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		validity := map[string]struct {
+			function string
+			isValid  func(string) bool
+		}{
+			"tweets":                {"TweetIsValid", v.TweetIsValid},
+			"usernames":             {"UsernameIsValid", v.UsernameIsValid},
+			"lists":                 {"ListIsValid", v.ListIsValid},
+			"hashtags":              {"HashtagIsValid", v.HashtagIsValid},
+			"urls":                  {"UrlIsValid", func(url string) bool { return v.UrlIsValid(url, true) }},
+			"urls_without_protocol": {"UrlIsValid", func(url string) bool { return v.UrlIsValid(url, false) }},
+		}
+
+		for _, test := range tests {
+			if check, ok := validity[section]; ok {
+				if actual := check.isValid(test.Text); actual != test.Expected.Valid {
+					t.Errorf("%s returned incorrect value for test [%s]. Expected:%v Got:%v", check.function, test.Description, test.Expected.Valid, actual)
+				}
+			} else if section == "lengths" {
+				if actual := v.TweetLength(test.Text); actual != test.Expected.Length {
+					t.Errorf("TweetLength returned incorrect value for test [%s]. Expected:%d Got:%d", test.Description, test.Expected.Length, actual)
+				}
+			} else if version, ok := weightedSections[section]; ok {
+				if actual := v.ParseTweet(test.Text, version); actual != test.Expected.Results {
+					t.Errorf("ParseTweet returned incorrect value for test [%s]. Expected:%+v Got:%+v", test.Description, test.Expected.Results, actual)
+				}
+			} else {
+				t.Fatalf("Unknown section '%s' of validate.yml", section)
+			}
+		}
+	})
+}
+
+// Runs the tests in the named sections of emoji.yml, or all of them if
+// none are named, checking the weighted length of each text under version
+// 3 of the configuration
+func RunEmojiLengths(t *testing.T, v Validator, sectionNames ...string) {
+	runSections(t, "emoji.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		for _, test := range tests {
+			if actual := v.ParseTweet(test.Text, 3).WeightedLength; actual != test.WeightedLength {
+				t.Errorf("ParseTweet returned incorrect weighted length for test [%s]. Expected:%d Got:%d", test.Description, test.WeightedLength, actual)
+			}
+		}
+	})
+}
+
+// Finds emoji, tested by emoji.yml
+type EmojiMatcher interface {
+	// Returns the emoji in text, in order
+	MatchEmoji(text string) []string
+}
+
+// Runs the tests in the named sections of emoji.yml against m, or all of
+// them if none are named, each section as a subtest
+func RunEmoji(t *testing.T, m EmojiMatcher, sectionNames ...string) {
+	runSections(t, "emoji.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		for _, test := range tests {
+			expected := test.Expected.Values
+			if expected == nil {
+				expected = []string{}
+			}
+			actual := m.MatchEmoji(test.Text)
+			if actual == nil {
+				actual = []string{}
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("MatchEmoji returned incorrect value for test [%s]. Expected:%q Got:%q", test.Description, expected, actual)
+			}
+		}
+	})
+}
+
+// The auto-linking functions tested by autolink.yml. Like the reference
+// implementations, they should not add rel="nofollow"
+type Autolinker interface {
+	AutoLinkUsernamesAndLists(text string) string
+	AutoLinkHashtags(text string) string
+	AutoLinkCashtags(text string) string
+	AutoLinkUrls(text string) string
+	AutoLink(text string) string
+}
+
+// The function tested by each section of autolink.yml
+var autolinkSections = map[string]struct {
+	function string
+	autoLink func(Autolinker, string) string
+}{
+	"usernames": {"AutoLinkUsernamesAndLists", Autolinker.AutoLinkUsernamesAndLists},
+	"lists":     {"AutoLinkUsernamesAndLists", Autolinker.AutoLinkUsernamesAndLists},
+	"hashtags":  {"AutoLinkHashtags", Autolinker.AutoLinkHashtags},
+	"cashtags":  {"AutoLinkCashtags", Autolinker.AutoLinkCashtags},
+	"urls":      {"AutoLinkUrls", Autolinker.AutoLinkUrls},
+	"all":       {"AutoLink", Autolinker.AutoLink},
+}
+
+// Runs the tests in the named sections of autolink.yml against a, or all
+// of them if none are named, each section as a subtest
+func RunAutolink(t *testing.T, a Autolinker, sectionNames ...string) {
+	runSections(t, "autolink.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		s, ok := autolinkSections[section]
+		if !ok {
+			t.Fatalf("Unknown section '%s' of autolink.yml", section)
+		}
+		for _, test := range tests {
+			if actual := s.autoLink(a, test.Text); actual != test.Expected.Text {
+				t.Errorf("%s returned incorrect value for test [%s]. Expected:[%s] Got:[%s]", s.function, test.Description, test.Expected.Text, actual)
+			}
+		}
+	})
+}
+
+// Highlights hits, tested by hit_highlighting.yml
+type HitHighlighter interface {
+	// Wraps each range of characters in hits in <em> tags
+	HitHighlight(text string, hits [][2]int) string
+}
+
+// Runs the tests in the named sections of hit_highlighting.yml against h,
+// or all of them if none are named, each section as a subtest
+func RunHitHighlight(t *testing.T, h HitHighlighter, sectionNames ...string) {
+	runSections(t, "hit_highlighting.yml", sectionNames, func(t *testing.T, section string, tests []Case) {
+		for _, test := range tests {
+			if actual := h.HitHighlight(test.Text, test.Hits); actual != test.Expected.Text {
+				t.Errorf("HitHighlight returned incorrect value for test [%s]. Expected:[%s] Got:[%s]", test.Description, test.Expected.Text, actual)
+			}
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package conformance
+
+import (
+	"fmt"
+	"io/fs"
+
+	goyaml "gopkg.in/yaml.v2"
+)
+
+// A conformance suite: its tests, by section
+type Suite struct {
+	Tests map[string][]Case
+}
+
+// A test in a conformance suite
+type Case struct {
+	Description string
+	Text        string
+	Expected    Expected
+
+	// The ranges to highlight, in hit_highlighting.yml
+	Hits [][2]int
+
+	// The weighted length of the text under version 3 of the
+	// configuration, in emoji.yml
+	WeightedLength int
+}
+
+// The expected result of a test. Which field is set depends on the form of
+// the value in the suite, which depends on the section
+type Expected struct {
+	// Lists of strings: the screen names, URLs, hashtags, or cashtags in
+	// extract.yml and tlds.yml, and the emoji in emoji.yml
+	Values []string
+
+	// Lists of entities with their indices, in the *_with_indices sections
+	// of extract.yml. Only the fields that apply to the type of the
+	// entities are set
+	Entities []Entity
+
+	// Strings: the screen name of a reply in extract.yml, which is empty
+	// for texts that are not replies, and the HTML in autolink.yml and
+	// hit_highlighting.yml
+	Text string
+
+	// Booleans: whether a text is valid, in validate.yml
+	Valid bool
+
+	// Integers: the length of a text, in the lengths section of
+	// validate.yml
+	Length int
+
+	// The results of parsing a text, in the weighted sections of
+	// validate.yml
+	Results ParseResults
+}
+
+// The form in which the tests are decoded, before their expected values
+// are converted to the typed forms above
+type rawSuite struct {
+	Tests map[string][]struct {
+		Description    string
+		Text           string
+		Expected       interface{}
+		Hits           [][]int
+		WeightedLength int `yaml:"weighted_length"`
+	}
+}
+
+// Returns the named suite, e.g. "extract.yml"
+func Load(name string) (*Suite, error) {
+	contents, err := fs.ReadFile(Files, name)
+	if err != nil {
+		return nil, err
+	}
+	var raw rawSuite
+	if err := goyaml.Unmarshal(contents, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", name, err)
+	}
+
+	suite := &Suite{Tests: map[string][]Case{}}
+	for section, tests := range raw.Tests {
+		cases := make([]Case, 0, len(tests))
+		for _, test := range tests {
+			c := Case{Description: test.Description, Text: test.Text, WeightedLength: test.WeightedLength}
+			if c.Expected, err = newExpected(test.Expected); err != nil {
+				return nil, fmt.Errorf("%s: test [%s] in %s: %v", name, test.Description, section, err)
+			}
+			for _, hit := range test.Hits {
+				if len(hit) != 2 {
+					return nil, fmt.Errorf("%s: test [%s] in %s: invalid hit %v", name, test.Description, section, hit)
+				}
+				c.Hits = append(c.Hits, [2]int{hit[0], hit[1]})
+			}
+			cases = append(cases, c)
+		}
+		suite.Tests[section] = cases
+	}
+	return suite, nil
+}
+
+// Returns the typed form of a decoded expected value
+func newExpected(value interface{}) (Expected, error) {
+	var e Expected
+	switch v := value.(type) {
+	case nil:
+	case string:
+		e.Text = v
+	case bool:
+		e.Valid = v
+	case int:
+		e.Length = v
+	case []interface{}:
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				e.Values = append(e.Values, item)
+			case map[interface{}]interface{}:
+				entity, err := newEntity(item)
+				if err != nil {
+					return e, err
+				}
+				e.Entities = append(e.Entities, entity)
+			default:
+				return e, fmt.Errorf("invalid list item %v", item)
+			}
+		}
+	case map[interface{}]interface{}:
+		var ok [7]bool
+		e.Results.WeightedLength, ok[0] = v["weightedLength"].(int)
+		e.Results.Permillage, ok[1] = v["permillage"].(int)
+		e.Results.Valid, ok[2] = v["valid"].(bool)
+		e.Results.DisplayRangeStart, ok[3] = v["displayRangeStart"].(int)
+		e.Results.DisplayRangeEnd, ok[4] = v["displayRangeEnd"].(int)
+		e.Results.ValidRangeStart, ok[5] = v["validRangeStart"].(int)
+		e.Results.ValidRangeEnd, ok[6] = v["validRangeEnd"].(int)
+		for _, ok := range ok {
+			if !ok {
+				return e, fmt.Errorf("invalid parse results %v", v)
+			}
+		}
+	default:
+		return e, fmt.Errorf("invalid expected value %v", value)
+	}
+	return e, nil
+}
+
+// The fields of an expected entity, by key
+var entityFields = map[string]func(*Entity) *string{
+	"screen_name": func(e *Entity) *string { return &e.ScreenName },
+	"list_slug":   func(e *Entity) *string { return &e.ListSlug },
+	"hashtag":     func(e *Entity) *string { return &e.Hashtag },
+	"cashtag":     func(e *Entity) *string { return &e.Cashtag },
+	"url":         func(e *Entity) *string { return &e.Url },
+}
+
+// Returns the entity described by a decoded map, which must hold indices
+func newEntity(values map[interface{}]interface{}) (Entity, error) {
+	var e Entity
+	indices, _ := values["indices"].([]interface{})
+	if len(indices) != 2 {
+		return e, fmt.Errorf("invalid indices %v", values["indices"])
+	}
+	for i, index := range indices {
+		n, ok := index.(int)
+		if !ok {
+			return e, fmt.Errorf("invalid indices %v", indices)
+		}
+		e.Indices[i] = n
+	}
+
+	for key, value := range values {
+		name, _ := key.(string)
+		field, ok := entityFields[name]
+		if !ok {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return e, fmt.Errorf("invalid %s %v", name, value)
+		}
+		*field(&e) = s
+	}
+	return e, nil
+}
@@ -0,0 +1,140 @@
+package conformance
+
+import (
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	names, err := fs.Glob(Files, "*.yml")
+	if err != nil || len(names) == 0 {
+		t.Fatalf("Glob returned incorrect value. Expected suites Got:%v %v", names, err)
+	}
+	for _, name := range names {
+		suite, err := Load(name)
+		if err != nil {
+			t.Errorf("Load returned an error for %s: %v", name, err)
+			continue
+		}
+		if len(suite.Tests) == 0 {
+			t.Errorf("Load returned no tests for %s", name)
+		}
+	}
+
+	if _, err := Load("missing.yml"); err == nil {
+		t.Errorf("Load returned no error for a missing suite")
+	}
+}
+
+// Every suite has a recorded source, and every recorded suite exists
+func TestRevision(t *testing.T) {
+	contents, err := os.ReadFile("REVISION")
+	if err != nil {
+		t.Fatalf("Error reading REVISION: %v", err)
+	}
+	recorded := map[string]bool{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			t.Errorf("REVISION contains an invalid line [%s]", line)
+			continue
+		}
+		if _, err := fs.Stat(Files, fields[0]); err != nil {
+			t.Errorf("REVISION records a source for %s, which is not a suite: %v", fields[0], err)
+		}
+		recorded[fields[0]] = true
+	}
+
+	names, _ := fs.Glob(Files, "*.yml")
+	for _, name := range names {
+		if !recorded[name] {
+			t.Errorf("REVISION does not record a source for %s", name)
+		}
+	}
+}
+
+func TestLoadExpected(t *testing.T) {
+	tests := []struct {
+		suite    string
+		section  string
+		expected Case
+	}{
+		{"extract.yml", "mentions", Case{
+			Description: "Extract mention at the begining of a tweet",
+			Text:        "@username reply",
+			Expected:    Expected{Values: []string{"username"}},
+		}},
+		{"extract.yml", "mentions_or_lists_with_indices", Case{
+			Description: "Extract a mention",
+			Text:        "@username yo!",
+			Expected:    Expected{Entities: []Entity{{ScreenName: "username", Indices: [2]int{0, 9}}}},
+		}},
+		{"extract.yml", "replies", Case{
+			Description: "Extract reply at the begining of a tweet",
+			Text:        "@username reply",
+			Expected:    Expected{Text: "username"},
+		}},
+		{"validate.yml", "tweets", Case{
+			Description: "Valid Tweet: < 20 characters",
+			Text:        "I am a Tweet",
+			Expected:    Expected{Valid: true},
+		}},
+		{"validate.yml", "lengths", Case{
+			Description: "Count the number of characters",
+			Text:        "This is a test.",
+			Expected:    Expected{Length: 15},
+		}},
+		{"validate.yml", "WeightedTweetsCounterTest", Case{
+			Description: "Regular Tweet",
+			Text:        "This is a test.",
+			Expected: Expected{Results: ParseResults{
+				WeightedLength: 15, Permillage: 53, Valid: true,
+				DisplayRangeStart: 0, DisplayRangeEnd: 14, ValidRangeStart: 0, ValidRangeEnd: 14,
+			}},
+		}},
+		{"hit_highlighting.yml", "plain_text", Case{
+			Description: "Highlight the beginning of a string",
+			Text:        "this is a test",
+			Expected:    Expected{Text: "<em>this</em> is a test"},
+			Hits:        [][2]int{{0, 4}},
+		}},
+		{"tlds.yml", "country", Case{
+			Description: "ac is a valid country tld",
+			Text:        "https://twitter.ac",
+			Expected:    Expected{Values: []string{"https://twitter.ac"}},
+		}},
+	}
+
+	for _, test := range tests {
+		suite, err := Load(test.suite)
+		if err != nil {
+			t.Fatalf("Error loading %s: %v", test.suite, err)
+		}
+		if cases := suite.Tests[test.section]; len(cases) == 0 || !reflect.DeepEqual(cases[0], test.expected) {
+			t.Errorf("Load returned incorrect first test in section %s of %s. Expected:%+v Got:%+v", test.section, test.suite, test.expected, cases)
+		}
+	}
+}
+
+func TestNewExpectedErrors(t *testing.T) {
+	values := []interface{}{
+		1.5,
+		[]interface{}{1},
+		[]interface{}{map[interface{}]interface{}{"screen_name": "a"}},
+		[]interface{}{map[interface{}]interface{}{"screen_name": 1, "indices": []interface{}{0, 2}}},
+		[]interface{}{map[interface{}]interface{}{"url": "a", "indices": []interface{}{0, "2"}}},
+		map[interface{}]interface{}{"weightedLength": 1},
+		map[interface{}]interface{}{"weightedLength": 1, "permillage": 3, "valid": true},
+	}
+	for _, value := range values {
+		if _, err := newExpected(value); err == nil {
+			t.Errorf("newExpected returned no error for value %v", value)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package emoji
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Runs emoji.yml against Match
+type conformanceMatcher struct{}
+
+func (conformanceMatcher) MatchEmoji(text string) []string {
+	var result []string
+	for i := 0; i < len(text); {
+		if n := Match(text[i:]); n > 0 {
+			result = append(result, text[i:i+n])
+			i += n
+			continue
+		}
+		i++
+	}
+	return result
+}
+
+func TestConformance(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced tables do not list each emoji")
+	}
+	conformance.RunEmoji(t, conformanceMatcher{})
+}
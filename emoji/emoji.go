@@ -0,0 +1,188 @@
+// Package emoji provides routines for recognizing emoji in text
+//
+// An emoji may be a single character, such as U+1F600 GRINNING FACE, or a
+// sequence of characters that is displayed as a single image: a character
+// followed by a skin tone modifier or by VARIATION SELECTOR-16, a keycap
+// sequence, a flag made of two regional indicators, a subdivision flag
+// made of tag characters, or several emoji joined by ZERO WIDTH JOINERs.
+// Sequences are always recognized as a whole, which is what twitter-text
+// version 3 requires to count each emoji as a single character.
+//
+// The tables of emoji characters are generated from the Unicode
+// emoji-test.txt data file by gen.go, and can be queried with
+// IsEmojiPresentation and IsTextPresentation so that other code can share
+// the same definition of emoji. Run go generate to regenerate them.
+package emoji
+
+//go:generate go run gen.go
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner    = '\u200D'
+	variationSelector  = '\uFE0F' // VS-16, requests emoji presentation
+	textSelector       = '\uFE0E' // VS-15, requests text presentation
+	combiningKeycap    = '\u20E3'
+	blackFlag          = '\U0001F3F4'
+	cancelTag          = '\U000E007F'
+	regionalIndicatorA = '\U0001F1E6'
+	regionalIndicatorZ = '\U0001F1FF'
+	skinToneLight      = '\U0001F3FB'
+	skinToneDark       = '\U0001F3FF'
+)
+
+// Reports whether r is displayed as an emoji by default. This includes
+// the skin tone and hair style components
+func IsEmojiPresentation(r rune) bool {
+	return unicode.Is(emojiPresentation, r)
+}
+
+// Reports whether r is displayed as text by default and as an emoji when
+// followed by VARIATION SELECTOR-16
+func IsTextPresentation(r rune) bool {
+	return unicode.Is(textPresentation, r)
+}
+
+// Returns the length in bytes of the emoji at the start of s, or 0 if s
+// does not start with an emoji
+func Match(s string) int {
+	n := emojiElementAt(s, false)
+	if n == 0 {
+		return 0
+	}
+	for {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if r != zeroWidthJoiner {
+			return n
+		}
+		next := emojiElementAt(s[n+size:], true)
+		if next == 0 {
+			return n
+		}
+		n += size + next
+	}
+}
+
+// Reports whether an emoji starts at the given byte offset within text.
+// Offsets in the middle of an emoji sequence, such as the second member
+// of a ZWJ sequence or the second regional indicator of a flag, are not
+// the start of an emoji
+func IsEmojiAt(text string, offset int) bool {
+	if offset < 0 || offset >= len(text) {
+		return false
+	}
+	found := false
+	scan(text, func(start, end int) bool {
+		found = start == offset
+		return start < offset
+	})
+	return found
+}
+
+// Reports whether text contains at least one emoji
+func HasEmoji(text string) bool {
+	found := false
+	scan(text, func(start, end int) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// Returns the number of emoji in text. Each emoji sequence counts once,
+// regardless of the number of characters it is made of
+func CountEmoji(text string) int {
+	count := 0
+	scan(text, func(start, end int) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Calls f with the byte offsets of each emoji in text, in order, until f
+// returns false
+func scan(text string, f func(start, end int) bool) {
+	for i := 0; i < len(text); {
+		n := Match(text[i:])
+		if n == 0 {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		if !f(i, i+n) {
+			return
+		}
+		i += n
+	}
+}
+
+// Returns the length in bytes of the single emoji (which may be followed
+// by modifiers) at the start of s, or 0. Within a ZWJ sequence, characters
+// that default to text presentation need not be followed by VS-16, so
+// inSequence is true for elements that follow a zero width joiner
+func emojiElementAt(s string, inSequence bool) int {
+	r, n := utf8.DecodeRuneInString(s)
+	next, size := utf8.DecodeRuneInString(s[n:])
+
+	switch {
+	case r == '#' || r == '*' || (r >= '0' && r <= '9'):
+		// Keycaps, with or without VS-16
+		if next == variationSelector {
+			n += size
+			next, size = utf8.DecodeRuneInString(s[n:])
+		}
+		if next == combiningKeycap {
+			return n + size
+		}
+		return 0
+	case r >= regionalIndicatorA && r <= regionalIndicatorZ:
+		// Flags are pairs of regional indicators. Runs are paired from
+		// their start, so the last indicator of an odd-length run is
+		// left unpaired and counts as an emoji on its own
+		if next >= regionalIndicatorA && next <= regionalIndicatorZ {
+			return n + size
+		}
+		return n
+	case r == blackFlag && next >= '\U000E0020' && next < cancelTag:
+		// Subdivision flags are tag sequences
+		for next >= '\U000E0020' && next < cancelTag {
+			n += size
+			next, size = utf8.DecodeRuneInString(s[n:])
+		}
+		if next == cancelTag {
+			n += size
+		}
+		return n
+	case IsEmojiPresentation(r) && next != textSelector:
+	case IsTextPresentation(r) && (next == variationSelector || isSkinTone(next)):
+	case IsTextPresentation(r) && (inSequence || startsSequence(s[n:])):
+	default:
+		return 0
+	}
+
+	if next == variationSelector {
+		n += size
+		next, size = utf8.DecodeRuneInString(s[n:])
+	}
+	if isSkinTone(next) {
+		n += size
+	}
+	return n
+}
+
+// Reports whether s starts with a zero width joiner followed by an emoji,
+// i.e. whether the preceding character is the first element of a ZWJ
+// sequence
+func startsSequence(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return r == zeroWidthJoiner && emojiElementAt(s[size:], true) > 0
+}
+
+// Reports whether r is one of the Fitzpatrick skin tone modifiers
+func isSkinTone(r rune) bool {
+	return r >= skinToneLight && r <= skinToneDark
+}
@@ -0,0 +1,147 @@
+package emoji
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestMatch(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced tables do not list each emoji")
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"\U0001f600 smile", "\U0001f600"},
+		{"☕ coffee", "☕"},
+		{"❤️ love", "❤️"},
+		{"❤ love", ""},
+		{"\U0001f44d\U0001f3fd!", "\U0001f44d\U0001f3fd"},
+		{"\U0001f468‍\U0001f469‍\U0001f467‍\U0001f466", "\U0001f468‍\U0001f469‍\U0001f467‍\U0001f466"},
+		{"\U0001f1ef\U0001f1f5\U0001f1fa\U0001f1f8", "\U0001f1ef\U0001f1f5"},
+		{"1️⃣2", "1️⃣"},
+		{"\U0001f3f4\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f", "\U0001f3f4\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f"},
+		{"a\U0001f600", ""},
+		{"1", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		actual := test.text[:Match(test.text)]
+		if actual != test.expected {
+			t.Errorf("Match returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.text, test.expected, actual)
+		}
+	}
+}
+
+// Every character in the exported tables must be recognized by Match, so
+// that code using the tables agrees with the validator
+func TestTables(t *testing.T) {
+	if UnicodeVersion == "" {
+		t.Errorf("UnicodeVersion is empty")
+	}
+
+	forEach := func(table *unicode.RangeTable, f func(r rune)) {
+		for _, r16 := range table.R16 {
+			for r := rune(r16.Lo); r <= rune(r16.Hi); r += rune(r16.Stride) {
+				f(r)
+			}
+		}
+		for _, r32 := range table.R32 {
+			for r := rune(r32.Lo); r <= rune(r32.Hi); r += rune(r32.Stride) {
+				f(r)
+			}
+		}
+	}
+
+	forEach(emojiPresentation, func(r rune) {
+		if text := string(r); Match(text) != len(text) {
+			t.Errorf("Match did not recognize %U from emojiPresentation", r)
+		}
+	})
+	forEach(textPresentation, func(r rune) {
+		if text := string(r); Match(text) != 0 {
+			t.Errorf("Match recognized %U from textPresentation without VS-16", r)
+		}
+		if text := string(r) + "\uFE0F"; Match(text) != len(text) {
+			t.Errorf("Match did not recognize %U from textPresentation with VS-16", r)
+		}
+	})
+
+	if !minimalTables {
+		for _, r := range []rune{'\U0001F600', '\u231A', '\U0001F3FD'} {
+			if !IsEmojiPresentation(r) {
+				t.Errorf("IsEmojiPresentation returned false for %U", r)
+			}
+		}
+		for _, r := range []rune{'\u00A9', '\u2764', '\U0001F3F3'} {
+			if !IsTextPresentation(r) {
+				t.Errorf("IsTextPresentation returned false for %U", r)
+			}
+		}
+	}
+	for _, r := range []rune{'a', '1', '#', '\U0001F1EF'} {
+		if IsEmojiPresentation(r) || IsTextPresentation(r) {
+			t.Errorf("Tables contain %U", r)
+		}
+	}
+}
+
+func TestIsEmojiAt(t *testing.T) {
+	family := "\U0001f468‍\U0001f469‍\U0001f467"
+	tests := []struct {
+		text     string
+		offset   int
+		expected bool
+	}{
+		{"a\U0001f600", 0, false},
+		{"a\U0001f600", 1, true},
+		{"a\U0001f600", 2, false},
+		{"a\U0001f600", 5, false},
+		{"a\U0001f600", -1, false},
+		{"x" + family, 1, true},
+		{"x" + family, 8, false},
+		{"\U0001f1ef\U0001f1f5\U0001f1fa", 0, true},
+		{"\U0001f1ef\U0001f1f5\U0001f1fa", 4, false},
+		{"\U0001f1ef\U0001f1f5\U0001f1fa", 8, true},
+		{"1️⃣", 0, true},
+		{"1", 0, false},
+	}
+
+	for _, test := range tests {
+		actual := IsEmojiAt(test.text, test.offset)
+		if actual != test.expected {
+			t.Errorf("IsEmojiAt returned incorrect value for text [%s] offset %d. Expected:[%t] Got:[%t]", test.text, test.offset, test.expected, actual)
+		}
+	}
+}
+
+func TestHasEmojiAndCountEmoji(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced tables do not list each emoji")
+	}
+
+	tests := []struct {
+		text  string
+		count int
+	}{
+		{"", 0},
+		{"no emoji here #1 ©", 0},
+		{"hi \U0001f600", 1},
+		{"\U0001f44d\U0001f3fd\U0001f44d\U0001f3fd", 2},
+		{"\U0001f468‍\U0001f469‍\U0001f467 and \U0001f1ef\U0001f1f5", 2},
+		{"\U0001f1ef\U0001f1f5\U0001f1fa", 2},
+		{"©️ 1️⃣ ☕", 3},
+	}
+
+	for _, test := range tests {
+		if actual := CountEmoji(test.text); actual != test.count {
+			t.Errorf("CountEmoji returned incorrect value for text [%s]. Expected:[%d] Got:[%d]", test.text, test.count, actual)
+		}
+		if actual := HasEmoji(test.text); actual != (test.count > 0) {
+			t.Errorf("HasEmoji returned incorrect value for text [%s]. Expected:[%t] Got:[%t]", test.text, test.count > 0, actual)
+		}
+	}
+}
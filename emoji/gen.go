@@ -0,0 +1,200 @@
+//go:build ignore
+// +build ignore
+
+// Generates tables.go from the Unicode emoji-test.txt data file
+//
+// Usage:
+//
+//	go run gen.go [-version 15.1] [-file emoji-test.txt] [-output tables.go]
+//
+// By default the data file for the pinned Unicode version is downloaded
+// from unicode.org. Use -file to generate the tables from a local copy.
+// To update emoji support for a new Unicode release, bump emojiVersion
+// below (or pass -version) and run go generate.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The Unicode emoji version the tables are generated from
+const emojiVersion = "15.1"
+
+const variationSelector = 0xFE0F
+
+var (
+	version = flag.String("version", emojiVersion, "Unicode emoji version")
+	file    = flag.String("file", "", "read emoji-test.txt from this file instead of unicode.org")
+	output  = flag.String("output", "tables.go", "output file")
+)
+
+func main() {
+	flag.Parse()
+
+	var r io.Reader
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	} else {
+		url := fmt.Sprintf("https://unicode.org/Public/emoji/%s/emoji-test.txt", *version)
+		resp, err := http.Get(url)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("Error fetching %s: %s", url, resp.Status)
+		}
+		r = resp.Body
+	}
+
+	emoji, text, fileVersion, err := parse(r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if fileVersion != *version {
+		log.Fatalf("emoji-test.txt is version %s, expected %s", fileVersion, *version)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen.go from emoji-test.txt version %s; DO NOT EDIT.\n\n", fileVersion)
+	fmt.Fprintf(&buf, "//go:build !minimaltables\n// +build !minimaltables\n\n")
+	fmt.Fprintf(&buf, "package emoji\n\n")
+	fmt.Fprintf(&buf, "import \"unicode\"\n\n")
+	fmt.Fprintf(&buf, "// The version of the Unicode emoji data the tables were generated from\n")
+	fmt.Fprintf(&buf, "const UnicodeVersion = %q\n\n", fileVersion)
+	writeTable(&buf, "emojiPresentation", "Characters that are displayed as emoji by default, including the\n// skin tone and hair style components. See IsEmojiPresentation", emoji)
+	writeTable(&buf, "textPresentation", "Characters that are displayed as text by default and as emoji when\n// followed by VS-16. See IsTextPresentation", text)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Parses emoji-test.txt, returning the characters that are emoji on their
+// own, the characters that are emoji when followed by VS-16, and the
+// version of the file.
+//
+// A character that is listed by itself as a fully-qualified emoji or as a
+// component (skin tones, hair styles) defaults to emoji presentation. A
+// character that is only fully-qualified when followed by VS-16 defaults
+// to text presentation. Regional indicators, keycap bases, and tags only
+// appear within sequences and are recognized by the matcher directly.
+func parse(r io.Reader) (emoji, text []rune, version string, err error) {
+	var emojiSet, textSet = map[rune]bool{}, map[rune]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# Version:") {
+			version = strings.TrimSpace(strings.TrimPrefix(line, "# Version:"))
+			continue
+		}
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) != 2 {
+			continue
+		}
+
+		var cps []rune
+		for _, s := range strings.Fields(fields[0]) {
+			cp, err := strconv.ParseUint(s, 16, 32)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("invalid code point %q: %v", s, err)
+			}
+			cps = append(cps, rune(cp))
+		}
+
+		switch status := strings.TrimSpace(fields[1]); {
+		case status != "fully-qualified" && status != "component":
+		case len(cps) == 1:
+			emojiSet[cps[0]] = true
+		case len(cps) == 2 && cps[1] == variationSelector:
+			textSet[cps[0]] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, "", err
+	}
+	if version == "" {
+		return nil, nil, "", fmt.Errorf("emoji-test.txt does not contain a version")
+	}
+
+	return sorted(emojiSet), sorted(textSet), version, nil
+}
+
+func sorted(set map[rune]bool) []rune {
+	result := make([]rune, 0, len(set))
+	for r := range set {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Writes a unicode.RangeTable containing the given sorted characters
+func writeTable(w io.Writer, name, doc string, runes []rune) {
+	var r16, r32 [][2]rune
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 {
+			j++
+		}
+		if runes[j] <= 0xFFFF {
+			r16 = append(r16, [2]rune{runes[i], runes[j]})
+		} else {
+			r32 = append(r32, [2]rune{runes[i], runes[j]})
+		}
+		i = j + 1
+	}
+
+	latinOffset := 0
+	for _, r := range r16 {
+		if r[1] <= unicode.MaxLatin1 {
+			latinOffset++
+		}
+	}
+
+	fmt.Fprintf(w, "// %s\nvar %s = &unicode.RangeTable{\n", doc, name)
+	if len(r16) > 0 {
+		fmt.Fprintf(w, "R16: []unicode.Range16{\n")
+		for _, r := range r16 {
+			fmt.Fprintf(w, "{0x%04X, 0x%04X, 1},\n", r[0], r[1])
+		}
+		fmt.Fprintf(w, "},\n")
+	}
+	if len(r32) > 0 {
+		fmt.Fprintf(w, "R32: []unicode.Range32{\n")
+		for _, r := range r32 {
+			fmt.Fprintf(w, "{0x%X, 0x%X, 1},\n", r[0], r[1])
+		}
+		fmt.Fprintf(w, "},\n")
+	}
+	if latinOffset > 0 {
+		fmt.Fprintf(w, "LatinOffset: %d,\n", latinOffset)
+	}
+	fmt.Fprintf(w, "}\n\n")
+}
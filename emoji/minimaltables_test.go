@@ -0,0 +1,8 @@
+//go:build minimaltables
+// +build minimaltables
+
+package emoji
+
+// Builds with the minimaltables tag approximate the Unicode tables, so
+// tests that depend on them are skipped
+const minimalTables = true
@@ -0,0 +1,39 @@
+//go:build minimaltables
+// +build minimaltables
+
+package emoji
+
+import "unicode"
+
+// Reduced tables for builds with the minimaltables tag, which cover whole
+// blocks of emoji rather than listing each character. Most emoji are
+// recognized, but some symbols in these blocks that are not emoji are
+// too, and emoji outside them are not.
+
+// The version of the Unicode emoji data that the full tables are generated
+// from
+const UnicodeVersion = "15.1"
+
+// Characters that are displayed as emoji by default: the Miscellaneous
+// Symbols and Pictographs, Emoticons, Transport and Map Symbols,
+// Supplemental Symbols and Pictographs, and Symbols and Pictographs
+// Extended-A blocks
+var emojiPresentation = &unicode.RangeTable{
+	R32: []unicode.Range32{
+		{0x1F300, 0x1F64F, 1},
+		{0x1F680, 0x1F6FF, 1},
+		{0x1F900, 0x1F9FF, 1},
+		{0x1FA70, 0x1FAFF, 1},
+	},
+}
+
+// Characters that are displayed as text by default and as emoji when
+// followed by VS-16: the copyright and registered signs, and the
+// Miscellaneous Symbols and Dingbats blocks
+var textPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00A9, 0x00AE, 5},
+		{0x2600, 0x27BF, 1},
+	},
+	LatinOffset: 1,
+}
@@ -0,0 +1,114 @@
+package extract
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits bound the work done by a single call to one of the *WithLimits
+// functions of this package. The zero value of each field means no limit.
+//
+// Only those functions are bounded: the other packages, such as validate,
+// autolink, and tweet, extract entities without limits. A service that
+// must bound the time spent on untrusted text can extract the entities
+// with limits and pass them on instead, e.g. to
+// autolink.AutoLinkWithEntities, or, for the URLs of the text's NFC form,
+// to validate.ParseNormalizedTweet:
+//
+//	normalized := validate.Normalize(text)
+//	urls, err := extract.ExtractUrlsWithLimits(normalized, limits)
+//	if err != nil {
+//		return err
+//	}
+//	results, err := validate.ParseNormalizedTweet(text, normalized, urls, c)
+type Limits struct {
+	// The maximum number of bytes of text that may be examined. Text is
+	// examined once by each extractor, and parts of it more than once
+	// when matches are retried, so this is a multiple of the length of
+	// the text rather than the length itself
+	MaxBytes int
+
+	// The maximum number of attempts to match an entity
+	MaxMatchAttempts int
+
+	// The time by which extraction must finish. The deadline is checked
+	// before each match attempt, so it is exceeded by at most the time
+	// taken by a single attempt
+	Deadline time.Time
+}
+
+// Error returned by the *WithLimits functions when extraction exceeds one
+// of the given Limits. Limit is the name of the limit that was exceeded,
+// one of "MaxBytes", "MaxMatchAttempts", or "Deadline", and Offset is the
+// byte offset within the text at which extraction stopped
+type BudgetExceededError struct {
+	Limit  string
+	Offset int
+}
+
+func (e BudgetExceededError) Error() string {
+	return fmt.Sprintf("Extraction limit %s exceeded at byte offset %d", e.Limit, e.Offset)
+}
+
+// The work remaining for a call that is subject to Limits. Extractors
+// charge each match attempt to the budget and stop as soon as it is
+// exceeded; the error is then held in err. A nil budget is unlimited
+type budget struct {
+	limits   Limits
+	bytes    int
+	attempts int
+	err      error
+}
+
+func newBudget(limits Limits) *budget {
+	return &budget{limits: limits}
+}
+
+// Charges an attempt to match an entity that may examine the n bytes
+// following byte offset offset. Reports whether the budget is exceeded
+func (b *budget) exceeded(offset, n int) bool {
+	if b == nil {
+		return false
+	}
+	if b.err != nil {
+		return true
+	}
+
+	b.bytes += n
+	b.attempts++
+	switch {
+	case b.limits.MaxBytes > 0 && b.bytes > b.limits.MaxBytes:
+		b.err = BudgetExceededError{Limit: "MaxBytes", Offset: offset}
+	case b.limits.MaxMatchAttempts > 0 && b.attempts > b.limits.MaxMatchAttempts:
+		b.err = BudgetExceededError{Limit: "MaxMatchAttempts", Offset: offset}
+	case !b.limits.Deadline.IsZero() && !time.Now().Before(b.limits.Deadline):
+		b.err = BudgetExceededError{Limit: "Deadline", Offset: offset}
+	}
+	return b.err != nil
+}
+
+// A regular expression that can be searched for one match at a time
+type submatchFinder interface {
+	FindStringSubmatchIndex(s string) []int
+}
+
+// Returns the first match of re in text at or after byte offset offset,
+// as the next match FindAllStringSubmatchIndex would return after a match
+// ending there, charging the attempt to b. afterStart must match as re
+// does anywhere but at the start of the text. Returns nil if there is no
+// match or b is exceeded
+func findSubmatchIndexFrom(re, afterStart submatchFinder, text string, offset int, b *budget) []int {
+	if b.exceeded(offset, len(text)-offset) {
+		return nil
+	}
+	if offset > 0 {
+		re = afterStart
+	}
+	m := re.FindStringSubmatchIndex(text[offset:])
+	for i := range m {
+		if m[i] >= 0 {
+			m[i] += offset
+		}
+	}
+	return m
+}
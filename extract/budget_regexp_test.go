@@ -0,0 +1,36 @@
+//go:build regexpextract
+// +build regexpextract
+
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegexpWithLimitsExceeded(t *testing.T) {
+	// The regexps are searched for one match at a time, so extraction
+	// stops near the start of the text rather than after all of it has
+	// been searched
+	tests := []struct {
+		name    string
+		extract func(string, Limits) ([]*TwitterEntity, error)
+		text    string
+	}{
+		{"ExtractMentionsOrLists", ExtractMentionsOrListsWithLimits, strings.Repeat("@user ", 100000)},
+		{"ExtractHashtags", ExtractHashtagsWithLimits, strings.Repeat("#tag ", 100000)},
+	}
+
+	for _, test := range tests {
+		for _, limits := range []Limits{{MaxMatchAttempts: 10}, {MaxBytes: 10 * len(test.text)}} {
+			result, err := test.extract(test.text, limits)
+			if result != nil {
+				t.Errorf("%sWithLimits returned entities for text [%.20s...] with limits %+v", test.name, test.text, limits)
+			}
+			actual, ok := err.(BudgetExceededError)
+			if !ok || actual.Offset <= 0 || actual.Offset > 100 {
+				t.Errorf("%sWithLimits returned incorrect error for text [%.20s...] with limits %+v. Expected:[limit exceeded within 100 bytes] Got:[%v]", test.name, test.text, limits, err)
+			}
+		}
+	}
+}
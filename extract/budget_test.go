@@ -0,0 +1,80 @@
+package extract
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var limitedExtractors = []struct {
+	name      string
+	extract   func(string) []*TwitterEntity
+	withLimit func(string, Limits) ([]*TwitterEntity, error)
+}{
+	{"ExtractEntities", ExtractEntities, ExtractEntitiesWithLimits},
+	{"ExtractUrls", ExtractUrls, ExtractUrlsWithLimits},
+	{"ExtractMentionsOrLists", ExtractMentionsOrLists, ExtractMentionsOrListsWithLimits},
+	{"ExtractHashtags", ExtractHashtags, ExtractHashtagsWithLimits},
+	{"ExtractCashtags", ExtractCashtags, ExtractCashtagsWithLimits},
+}
+
+func TestWithLimitsMatchesUnlimited(t *testing.T) {
+	limits := Limits{MaxBytes: 1 << 30, MaxMatchAttempts: 1 << 30, Deadline: time.Now().Add(time.Hour)}
+	for _, text := range conformanceTexts(t) {
+		for _, e := range limitedExtractors {
+			for _, l := range []Limits{{}, limits} {
+				actual, err := e.withLimit(text, l)
+				if err != nil {
+					t.Errorf("%sWithLimits returned an error for text [%s]: %v", e.name, text, err)
+				}
+				if expected := e.extract(text); !reflect.DeepEqual(actual, expected) {
+					t.Errorf("%sWithLimits returned incorrect value for text [%s]. Expected:%v Got:%v", e.name, text, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestWithLimitsExceeded(t *testing.T) {
+	// Each URL is found by a separate match attempt, each of which may
+	// examine the rest of the text
+	urls := strings.Repeat("a.com ", 1000)
+
+	tests := []struct {
+		text   string
+		limits Limits
+		limit  string
+	}{
+		{urls, Limits{MaxMatchAttempts: 10}, "MaxMatchAttempts"},
+		{urls, Limits{MaxBytes: 10 * len(urls)}, "MaxBytes"},
+		{urls, Limits{Deadline: time.Now()}, "Deadline"},
+		{strings.Repeat("#tag ", 100), Limits{MaxMatchAttempts: 50}, "MaxMatchAttempts"},
+		{strings.Repeat("@user ", 100), Limits{MaxMatchAttempts: 50}, "MaxMatchAttempts"},
+		{strings.Repeat("$CASH ", 100), Limits{MaxMatchAttempts: 50}, "MaxMatchAttempts"},
+	}
+
+	for _, test := range tests {
+		result, err := ExtractEntitiesWithLimits(test.text, test.limits)
+		if result != nil {
+			t.Errorf("ExtractEntitiesWithLimits returned entities for text [%.20s...] with limits %+v", test.text, test.limits)
+		}
+		actual, ok := err.(BudgetExceededError)
+		if !ok || actual.Limit != test.limit || actual.Offset < 0 || actual.Offset >= len(test.text) {
+			t.Errorf("ExtractEntitiesWithLimits returned incorrect error for text [%.20s...] with limits %+v. Expected:[%s exceeded] Got:[%v]", test.text, test.limits, test.limit, err)
+		}
+	}
+
+	// The deadline is checked before the first attempt
+	if _, err := ExtractUrlsWithLimits(urls, Limits{Deadline: time.Now()}); err != (BudgetExceededError{Limit: "Deadline", Offset: 0}) {
+		t.Errorf("ExtractUrlsWithLimits returned incorrect error for an expired deadline: %v", err)
+	}
+}
+
+func TestBudgetExceededError(t *testing.T) {
+	err := BudgetExceededError{Limit: "MaxBytes", Offset: 12}
+	expected := "Extraction limit MaxBytes exceeded at byte offset 12"
+	if err.Error() != expected {
+		t.Errorf("Error returned incorrect value. Expected:[%s] Got:[%s]", expected, err.Error())
+	}
+}
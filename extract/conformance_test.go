@@ -0,0 +1,98 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Runs the conformance suites against the package, checking the type of
+// each entity as it is converted
+type conformanceExtractor struct {
+	t *testing.T
+}
+
+// Returns the conformance form of entities, which should all have type
+// expected
+func (x conformanceExtractor) entities(function string, entities []*TwitterEntity, expected EntityType) []conformance.Entity {
+	result := make([]conformance.Entity, 0, len(entities))
+	for _, e := range entities {
+		if e.Type != expected {
+			x.t.Errorf("%s returned entity with wrong type. Expected:%v Got:%v", function, expected, e.Type)
+		}
+		result = append(result, conformance.Entity{
+			ScreenName: e.screenName,
+			ListSlug:   e.listSlug,
+			Hashtag:    e.hashtag,
+			Cashtag:    e.cashtag,
+			Url:        e.Text,
+			Indices:    [2]int{e.Range.Start, e.Range.Stop},
+		})
+	}
+	return result
+}
+
+func (x conformanceExtractor) ExtractMentionedScreenNames(text string) []conformance.Entity {
+	return x.entities("ExtractMentionedScreenNames", ExtractMentionedScreenNames(text), MENTION)
+}
+
+func (x conformanceExtractor) ExtractMentionsOrLists(text string) []conformance.Entity {
+	return x.entities("ExtractMentionsOrLists", ExtractMentionsOrLists(text), MENTION)
+}
+
+func (x conformanceExtractor) ExtractReplyScreenname(text string) string {
+	e := ExtractReplyScreenname(text)
+	if e == nil {
+		return ""
+	}
+	if e.Type != MENTION {
+		x.t.Errorf("ExtractReplyScreenname returned entity with wrong type. Expected:MENTION Got:%v", e.Type)
+	}
+	return e.screenName
+}
+
+func (x conformanceExtractor) ExtractUrls(text string) []conformance.Entity {
+	return x.entities("ExtractUrls", ExtractUrls(text), URL)
+}
+
+func (x conformanceExtractor) ExtractHashtags(text string) []conformance.Entity {
+	return x.entities("ExtractHashtags", ExtractHashtags(text), HASH_TAG)
+}
+
+func (x conformanceExtractor) ExtractCashtags(text string) []conformance.Entity {
+	return x.entities("ExtractCashtags", ExtractCashtags(text), CASH_TAG)
+}
+
+func TestExtractMentions(t *testing.T) {
+	conformance.RunExtract(t, conformanceExtractor{t}, "mentions", "mentions_with_indices", "mentions_or_lists_with_indices")
+}
+
+func TestExtractReplyScreenname(t *testing.T) {
+	conformance.RunExtract(t, conformanceExtractor{t}, "replies")
+}
+
+func TestExtractUrls(t *testing.T) {
+	conformance.RunExtract(t, conformanceExtractor{t}, "urls")
+}
+
+func TestExtractUrlsWithIndices(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced TLD tables omit TLDs that are not ASCII")
+	}
+	conformance.RunExtract(t, conformanceExtractor{t}, "urls_with_indices")
+}
+
+func TestTlds(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced TLD tables omit TLDs that are not ASCII")
+	}
+	conformance.RunTLDs(t, conformanceExtractor{t})
+}
+
+func TestExtractHashtags(t *testing.T) {
+	conformance.RunExtract(t, conformanceExtractor{t}, "hashtags", "hashtags_with_indices")
+}
+
+func TestExtractCashtags(t *testing.T) {
+	conformance.RunExtract(t, conformanceExtractor{t}, "cashtags", "cashtags_with_indices")
+}
@@ -0,0 +1,38 @@
+package extract
+
+import (
+	"github.com/kylemcc/twitter-text-go/emoji"
+)
+
+// Extract emoji from the given text. Returns a slice of TwitterEntity
+// struct pointers with Type=EMOJI, in the order they appear within the
+// input string.
+//
+// Each entity covers a complete emoji sequence, so a flag, a keycap, an
+// emoji with a skin tone modifier, or a sequence joined by zero width
+// joiners is returned as a single entity rather than one entity per
+// character.
+func ExtractEmoji(text string) []*TwitterEntity {
+	return entitiesT(AppendEmoji(nil, text)).pointers()
+}
+
+// Appends the emoji ExtractEmoji would return for text to dst, and returns
+// the extended slice. See AppendEntities
+func AppendEmoji(dst []TwitterEntity, text string) []TwitterEntity {
+	base := len(dst)
+	for i := 0; i < len(text); {
+		n := emoji.Match(text[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		dst = append(dst, TwitterEntity{
+			Text:      text[i : i+n],
+			ByteRange: Range{Start: i, Stop: i + n},
+			Type:      EMOJI,
+		})
+		i += n
+	}
+	entitiesT(dst[base:]).fixIndices(text)
+	return dst
+}
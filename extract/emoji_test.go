@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractEmoji(t *testing.T) {
+	if minimalTables {
+		t.Skip("the reduced emoji tables do not list each emoji")
+	}
+
+	tests := []struct {
+		text     string
+		expected []string
+		ranges   []Range
+	}{
+		{"no emoji here", nil, nil},
+		{"hi \U0001f600!", []string{"\U0001f600"}, []Range{{3, 4}}},
+		{"\U0001f44d\U0001f3fd and ☕", []string{"\U0001f44d\U0001f3fd", "☕"}, []Range{{0, 2}, {7, 8}}},
+		{"\U0001f1ef\U0001f1f5 #tokyo 1️⃣", []string{"\U0001f1ef\U0001f1f5", "1️⃣"}, []Range{{0, 2}, {10, 13}}},
+		{"\U0001f468‍\U0001f469‍\U0001f467", []string{"\U0001f468‍\U0001f469‍\U0001f467"}, []Range{{0, 5}}},
+	}
+
+	for _, test := range tests {
+		var (
+			actual []string
+			ranges []Range
+		)
+		for _, e := range ExtractEmoji(test.text) {
+			if e.Type != EMOJI {
+				t.Errorf("ExtractEmoji returned entity of type %v for text [%s]", e.Type, test.text)
+			}
+			if test.text[e.ByteRange.Start:e.ByteRange.Stop] != e.Text {
+				t.Errorf("ExtractEmoji returned incorrect byte range for text [%s]. Entity:[%s] ByteRange:%v", test.text, e.Text, e.ByteRange)
+			}
+			actual = append(actual, e.Text)
+			ranges = append(ranges, e.Range)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("ExtractEmoji returned incorrect value for text [%s]. Expected:%q Got:%q", test.text, test.expected, actual)
+		}
+		if !reflect.DeepEqual(ranges, test.ranges) {
+			t.Errorf("ExtractEmoji returned incorrect ranges for text [%s]. Expected:%v Got:%v", test.text, test.ranges, ranges)
+		}
+	}
+}
+
+func TestExtractHashtagsKeycap(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"#️⃣", nil},
+		{"#⃣", nil},
+		{"#️⃣tokyo", nil},
+		{"#️⃣ #tokyo", []string{"tokyo"}},
+		{"#️tokyo", nil},
+		{"#tokyo 1️⃣", []string{"tokyo"}},
+	}
+
+	for _, test := range tests {
+		var actual []string
+		for _, e := range ExtractHashtags(test.text) {
+			hashtag, _ := e.Hashtag()
+			actual = append(actual, hashtag)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("ExtractHashtags returned incorrect value for text [%s]. Expected:%q Got:%q", test.text, test.expected, actual)
+		}
+	}
+}
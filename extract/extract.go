@@ -2,16 +2,21 @@
 // "entities" from text
 //
 // This package supports extraction of Twitter usernames, replies, lists,
-// hashtags, cashtags, and URLs. The implementation and API are based the
-// set of similarly named twitter-text-* libraries published by Twitter. This
-// library is tested using the standard Conformance test suite maintained by
-// Twitter (https://github.com/twitter/twitter-text-conformance).
+// hashtags, cashtags, URLs, and emoji. The implementation and API are based
+// the set of similarly named twitter-text-* libraries published by Twitter.
+// This library is tested using the standard Conformance test suite
+// maintained by Twitter (https://github.com/twitter/twitter-text-conformance).
+//
+// Building with the minimaltables tag, e.g. for TinyGo or WebAssembly,
+// replaces the Unicode tables in this package and in the emoji and
+// validate packages with smaller approximations. URLs are only recognized
+// with TLDs written in ASCII, and emoji by the blocks they belong to. ASCII
+// text gives the same results as in the default build.
 package extract
 
 import (
 	"fmt"
 	"sort"
-	"strings"
 	"unicode/utf8"
 )
 
@@ -40,6 +45,8 @@ const (
 	HASH_TAG
 	CASH_TAG
 	URL
+	EMOJI
+	CUSTOM // Found by a Matcher registered with an Extractor
 )
 
 // Implement the Stringer interface
@@ -53,6 +60,10 @@ func (t EntityType) String() string {
 		return "CASH_TAG"
 	case URL:
 		return "URL"
+	case EMOJI:
+		return "EMOJI"
+	case CUSTOM:
+		return "CUSTOM"
 	}
 	return "Unknown"
 }
@@ -67,22 +78,34 @@ type TwitterEntity struct {
 
 	screenName string // Contains the value of username without the leading '@' when Type=MENTION
 	listSlug   string // Contains the value of the list name when Type=MENTION
+	domain     string // Contains the domain of the server of the account of a mention of the form @user@domain when Type=MENTION
 	hashtag    string // Contains the value of the hashtag without the leading # when Type=HASH_TAG
 	cashtag    string // Contains the value of the cashtag without the leading $ when Type=CASH_TAG
-
-	screenNameIsSet bool
-	listSlugIsSet   bool
-	hashtagIsSet    bool
-	cashtagIsSet    bool
+	kind       string // Contains the kind of entity given when its Matcher was registered when Type=CUSTOM
+
+	displayUrl  string // Contains the display URL supplied for a URL entity (e.g. by the Twitter API) when Type=URL
+	expandedUrl string // Contains the expanded URL supplied for a URL entity (e.g. by the Twitter API) when Type=URL
+
+	screenNameIsSet  bool
+	listSlugIsSet    bool
+	domainIsSet      bool
+	hashtagIsSet     bool
+	cashtagIsSet     bool
+	kindIsSet        bool
+	displayUrlIsSet  bool
+	expandedUrlIsSet bool
 }
 
-type entitiesT []*TwitterEntity
+// Entities found by the extractors. Entities are held by value so that
+// they can be appended to a caller's slice without allocating each one
+type entitiesT []TwitterEntity
 
 // The indices generated by the various extract functions are
 // byte offsets. This function calculates charecter/rune offsets
 // based on those offsets
 func (entities entitiesT) fixIndices(text string) {
-	for _, e := range entities {
+	for i := range entities {
+		e := &entities[i]
 		start := utf8.RuneCountInString(text[:e.ByteRange.Start])
 		e.Range.Start = start
 		stop := utf8.RuneCountInString(e.Text)
@@ -90,26 +113,38 @@ func (entities entitiesT) fixIndices(text string) {
 	}
 }
 
-func (e *entitiesT) removeOverlappingEntities() {
-	if e.Len() < 2 {
-		return
+// Removes the entities that overlap an entity before them that is kept,
+// in place, and returns the remaining entities. The entities must be
+// sorted
+func (entities entitiesT) removeOverlappingEntities() entitiesT {
+	if len(entities) < 2 {
+		return entities
 	}
 
-	var (
-		result   []*TwitterEntity
-		prev     *TwitterEntity
-		cur      *TwitterEntity
-		entities = *e
-	)
-	prev = entities[0]
-	result = append(result, prev)
-	for _, cur = range entities[1:] {
-		if !(prev.Range.Stop > cur.Range.Start) {
-			result = append(result, cur)
+	n := 1
+	prevStop := entities[0].Range.Stop
+	for i := 1; i < len(entities); i++ {
+		cur := &entities[i]
+		if !(prevStop > cur.Range.Start) {
+			entities[n] = *cur
+			n++
+			prevStop = cur.Range.Stop
 		}
-		prev = cur
 	}
-	*e = result
+	return entities[:n]
+}
+
+// Returns pointers to the entities, or nil if there are none, for the
+// functions that return []*TwitterEntity
+func (entities entitiesT) pointers() []*TwitterEntity {
+	if len(entities) == 0 {
+		return nil
+	}
+	result := make([]*TwitterEntity, len(entities))
+	for i := range entities {
+		result[i] = &entities[i]
+	}
+	return result
 }
 
 func (e entitiesT) Len() int {
@@ -124,6 +159,22 @@ func (e entitiesT) Swap(i, j int) {
 	e[i], e[j] = e[j], e[i]
 }
 
+// Sorts the entities by their start offsets, keeping entities with the
+// same start offset in order. A tweet has few entities, which are sorted
+// by insertion, as sort.Stable would sort them, without the allocation of
+// converting them to a sort.Interface
+func (e entitiesT) sort() {
+	if len(e) > 12 {
+		sort.Stable(e)
+		return
+	}
+	for i := 1; i < len(e); i++ {
+		for j := i; j > 0 && e.Less(j, j-1); j-- {
+			e.Swap(j, j-1)
+		}
+	}
+}
+
 // Implement the Stringer interface
 func (t *TwitterEntity) String() string {
 	return fmt.Sprintf("TwitterEntity{Text: [%s] Range: %+v Type: %v", t.Text, t.Range, t.Type)
@@ -144,6 +195,14 @@ func (t *TwitterEntity) ListSlug() (string, bool) {
 	return t.listSlug, t.listSlugIsSet
 }
 
+// Returns the domain of the server of a mention of the form @user@domain,
+// as extracted by an Extractor with FederatedMentions set, and a boolean
+// indicating whether the value is set. The return value will be ("",
+// false) when Type != MENTION OR when the mention does not name a server
+func (t *TwitterEntity) Domain() (string, bool) {
+	return t.domain, t.domainIsSet
+}
+
 // Returns the value of the extracted hashtag (when Type=HASH_TAG) and
 // a boolean indicating whether the value is set. The return value will be
 // ("", false) when Type != HASH_TAG
@@ -158,28 +217,199 @@ func (t *TwitterEntity) Cashtag() (string, bool) {
 	return t.cashtag, t.cashtagIsSet
 }
 
+// Returns the kind of a custom entity (when Type=CUSTOM), as given when
+// its Matcher was registered, and a boolean indicating whether the value
+// is set. The return value will be ("", false) when Type != CUSTOM
+func (t *TwitterEntity) Kind() (string, bool) {
+	return t.kind, t.kindIsSet
+}
+
+// Returns the display URL of a URL entity and a boolean indicating whether
+// the value is set. Entities returned by the extract functions never have
+// a display URL; it is only set for entities created with NewUrlEntity
+func (t *TwitterEntity) DisplayUrl() (string, bool) {
+	return t.displayUrl, t.displayUrlIsSet
+}
+
+// Returns the expanded URL of a URL entity and a boolean indicating whether
+// the value is set. Entities returned by the extract functions never have
+// an expanded URL; it is only set for entities created with NewUrlEntity
+func (t *TwitterEntity) ExpandedUrl() (string, bool) {
+	return t.expandedUrl, t.expandedUrlIsSet
+}
+
+// Creates a MENTION entity located at byte offsets [start, stop) within
+// text. listSlug should be empty unless the entity refers to a list, in
+// which case it includes the leading '/'
+//
+// This, and the other New*Entity functions, allow entities obtained from
+// elsewhere (e.g. a Twitter API payload) to be used with packages that
+// consume extracted entities.
+func NewMentionEntity(text string, start, stop int, screenName, listSlug string) *TwitterEntity {
+	e := newEntity(text, start, stop, MENTION)
+	e.screenName = screenName
+	e.screenNameIsSet = true
+	e.listSlug = listSlug
+	e.listSlugIsSet = listSlug != ""
+	return e
+}
+
+// Creates a MENTION entity for a mention of the form @user@domain located
+// at byte offsets [start, stop) within text
+func NewFederatedMentionEntity(text string, start, stop int, screenName, domain string) *TwitterEntity {
+	e := newEntity(text, start, stop, MENTION)
+	e.screenName = screenName
+	e.screenNameIsSet = true
+	e.domain = domain
+	e.domainIsSet = domain != ""
+	return e
+}
+
+// Creates a HASH_TAG entity located at byte offsets [start, stop) within text
+func NewHashtagEntity(text string, start, stop int, hashtag string) *TwitterEntity {
+	e := newEntity(text, start, stop, HASH_TAG)
+	e.hashtag = hashtag
+	e.hashtagIsSet = true
+	return e
+}
+
+// Creates a CASH_TAG entity located at byte offsets [start, stop) within text
+func NewCashtagEntity(text string, start, stop int, cashtag string) *TwitterEntity {
+	e := newEntity(text, start, stop, CASH_TAG)
+	e.cashtag = cashtag
+	e.cashtagIsSet = true
+	return e
+}
+
+// Creates a URL entity located at byte offsets [start, stop) within text.
+// displayUrl and expandedUrl may be empty if they are not known
+func NewUrlEntity(text string, start, stop int, displayUrl, expandedUrl string) *TwitterEntity {
+	e := newEntity(text, start, stop, URL)
+	e.displayUrl = displayUrl
+	e.displayUrlIsSet = displayUrl != ""
+	e.expandedUrl = expandedUrl
+	e.expandedUrlIsSet = expandedUrl != ""
+	return e
+}
+
+// Creates a CUSTOM entity of the given kind located at byte offsets
+// [start, stop) within text
+func NewCustomEntity(text string, start, stop int, kind string) *TwitterEntity {
+	e := newEntity(text, start, stop, CUSTOM)
+	e.kind = kind
+	e.kindIsSet = true
+	return e
+}
+
+func newEntity(text string, start, stop int, t EntityType) *TwitterEntity {
+	e := &TwitterEntity{
+		Text:      text[start:stop],
+		ByteRange: Range{Start: start, Stop: stop},
+		Type:      t,
+	}
+	e.Range.Start = utf8.RuneCountInString(text[:start])
+	e.Range.Stop = e.Range.Start + utf8.RuneCountInString(e.Text)
+	return e
+}
+
 // Extract all usernames, lists, hashtags, and URLs from the
 // given text - returned in the order they appear within the
 // input string
 func ExtractEntities(text string) []*TwitterEntity {
-	var result entitiesT
-	result = ExtractUrls(text)
-	result = append(result, ExtractHashtags(text)...)
-	result = append(result, ExtractMentionsOrLists(text)...)
-	result = append(result, ExtractCashtags(text)...)
-
-	sort.Sort(result)
-	result.removeOverlappingEntities()
-	return result
+	return extractEntities(nil, text, nil, nil).pointers()
+}
+
+// Appends the entities ExtractEntities would return for text to dst, and
+// returns the extended slice. Like the other Append* functions, this
+// appends entities by value rather than allocating each one, so a caller
+// that extracts entities from many texts can reuse the same slice for
+// each of them, e.g. by passing dst[:0], and avoid allocating at all once
+// it is large enough
+func AppendEntities(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractEntities(dst, text, nil, nil)
+}
+
+// Extracts entities as ExtractEntities does, subject to the given limits.
+// Returns nil and a BudgetExceededError if a limit is exceeded
+func ExtractEntitiesWithLimits(text string, limits Limits) ([]*TwitterEntity, error) {
+	b := newBudget(limits)
+	return withBudget(extractEntities(nil, text, nil, b), b)
+}
+
+// Returns the result of an extraction subject to b, or nil and the error
+// that stopped it
+func withBudget(result entitiesT, b *budget) ([]*TwitterEntity, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return result.pointers(), nil
+}
+
+// Appends the built-in entities and the additional entities found by x,
+// which may be nil, to dst, removing those that overlap
+func extractEntities(dst entitiesT, text string, x *Extractor, b *budget) entitiesT {
+	var matchers []kindMatcher
+	federated := false
+	if x != nil {
+		matchers = x.matchers
+		federated = x.FederatedMentions
+	}
+
+	// Optimization
+	t := findTriggers(text)
+	if !t.any() && len(matchers) == 0 {
+		return dst
+	}
+
+	base := len(dst)
+	if federated {
+		// First, so that they are kept over the mentions of the first 20
+		// characters of long usernames
+		dst = extractFederatedMentions(dst, text, b)
+	}
+	dst = extractUrls(dst, text, t.url, b)
+	dst = extractHashtags(dst, text, t.hashtag, true, b)
+	dst = extractMentionsOrLists(dst, text, t.mention, b)
+	dst = extractCashtags(dst, text, t.cashtag, b)
+	dst = appendCustomEntities(dst, text, matchers, b)
+
+	result := dst[base:]
+	result.sort()
+	result = result.removeOverlappingEntities()
+	return dst[:base+len(result)]
 }
 
 // Extract urls from the given text. Returns a slice of
 // TwitterEntity struct pointers.
 func ExtractUrls(text string) []*TwitterEntity {
+	return extractUrls(nil, text, urlSearchStart(text), nil).pointers()
+}
+
+// Appends the urls ExtractUrls would return for text to dst, and returns
+// the extended slice. See AppendEntities
+func AppendUrls(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractUrls(dst, text, urlSearchStart(text), nil)
+}
+
+// Extracts urls as ExtractUrls does, subject to the given limits. Returns
+// nil and a BudgetExceededError if a limit is exceeded
+func ExtractUrlsWithLimits(text string, limits Limits) ([]*TwitterEntity, error) {
+	b := newBudget(limits)
+	return withBudget(extractUrls(nil, text, urlSearchStart(text), b), b)
+}
+
+// Appends the urls in text to dst, starting at byte offset start, as
+// returned by urlSearchStart
+func extractUrls(dst entitiesT, text string, start int, b *budget) entitiesT {
+	// Optimization
+	if start < 0 {
+		return dst
+	}
+
 	// This giant pile of barf is copied from the various
 	// twitter-text implementations. There must be a better
 	// way!
-	var result entitiesT
+	base := len(dst)
 	var (
 		matchStart     int
 		matchEnd       int
@@ -196,11 +426,15 @@ func ExtractUrls(text string) []*TwitterEntity {
 	// to walk the string because the regexp package
 	// lacks support for lookahead assertions
 	offset := 0
-	nextOffset := 0
+	nextOffset := start
 	for {
 		offset = nextOffset
 		substr := text[offset:]
-		match := validUrl.FindStringSubmatchIndex(substr)
+		if b.exceeded(offset, len(substr)) {
+			break
+		}
+		var matchBuf [urlMatchLength]int
+		match := matchUrl(substr, matchBuf[:])
 
 		// If no matches are found in this portion of the string,
 		// we're done
@@ -217,7 +451,7 @@ func ExtractUrls(text string) []*TwitterEntity {
 
 		// If protocol is missing, only extract ascii domains
 		if match[validUrlGroupProtocol*2] < 0 {
-			var lastEntity *TwitterEntity
+			lastStart, lastStop := -1, -1
 			lastInvalid := false
 			precedingStart = match[validUrlGroupBefore*2]
 			precedingEnd = match[validUrlGroupBefore*2+1]
@@ -233,63 +467,96 @@ func ExtractUrls(text string) []*TwitterEntity {
 
 			// Make sure the protocol-less domain is ascii only
 			// e.g., in the case of "한국twitter.com", only extract twitter.com
-			if m := validAsciiDomain.FindStringSubmatchIndex(substr[domainStart:domainEnd]); m != nil {
-				lastEntity = &TwitterEntity{
-					Text: substr[matchStart+m[0] : matchStart+m[1]],
-					ByteRange: Range{
-						Start: matchStart + offset + m[0],
-						Stop:  matchStart + offset + m[1]},
-					Type: URL}
+			if start, end, ok := matchAsciiDomain(substr[domainStart:domainEnd]); ok {
+				lastStart, lastStop = matchStart+offset+start, matchStart+offset+end
 
 				// Set the next offset to the end of this match
-				nextOffset = matchStart + m[1] + offset - 1
+				nextOffset = matchStart + end + offset - 1
 
 				// If the url has a Generic TLD (not CC TLD), it's valid
-				if lastInvalid = invalidShortDomain.MatchString(lastEntity.Text); !lastInvalid {
-					result = append(result, lastEntity)
+				if lastInvalid = isInvalidShortDomain(text[lastStart:lastStop]); !lastInvalid {
+					dst = appendUrl(dst, text, lastStart, lastStop)
 				}
 			}
 
-			if lastEntity == nil {
+			if lastStart < 0 {
 				continue
 			}
 
 			// If the match contains a path immediately following the domain,
 			// append it to the match
-			if pathStart > 0 && pathStart == lastEntity.ByteRange.Stop-offset {
+			if pathStart > 0 && pathStart == lastStop-offset {
 				// If the last result was invalid b/c it did not contain a GTLD,
 				// append it
 				if lastInvalid {
-					result = append(result, lastEntity)
+					dst = appendUrl(dst, text, lastStart, lastStop)
 				}
 
 				// Update the text and offsets
-				lastEntity.Text += substr[pathStart:pathEnd]
+				lastEntity := &dst[len(dst)-1]
 				lastEntity.ByteRange.Stop = pathEnd + offset
+				lastEntity.Text = text[lastEntity.ByteRange.Start:lastEntity.ByteRange.Stop]
 				nextOffset = lastEntity.ByteRange.Stop - 1
-			} else if validSpecialShortDomain.MatchString(lastEntity.Text) {
-				result = append(result, lastEntity)
+			} else if isValidSpecialShortDomain(text[lastStart:lastStop]) {
+				dst = appendUrl(dst, text, lastStart, lastStop)
 			}
 		} else {
 			// Else, the url contains a protocol
-			url := substr[matchStart:matchEnd]
 			// If it's a t.co url, restrict to certain path characters
-			if tcoLoc := validTcoUrl.FindStringIndex(url); tcoLoc != nil {
-				url = url[tcoLoc[0]:tcoLoc[1]]
-				matchEnd = matchStart + len(url)
+			if n := tcoUrlLength(substr[matchStart:matchEnd]); n > 0 {
+				matchEnd = matchStart + n
 			}
-			result = append(result,
-				&TwitterEntity{Text: url,
-					ByteRange: Range{
-						Start: matchStart + offset,
-						Stop:  matchEnd + offset},
-					Type: URL})
+			dst = appendUrl(dst, text, matchStart+offset, matchEnd+offset)
 		}
 	}
 
 	// Add character/rune offsets in addition to byte offsets
-	result.fixIndices(text)
-	return result
+	dst[base:].fixIndices(text)
+	return dst
+}
+
+// The most URL entities that room is made for at once
+const maxUrlBlock = 8
+
+// Appends a URL entity located at byte offsets [start, stop) within text
+// to dst. When dst is full, room is made for it and for the URLs that are
+// likely to follow it together, rather than for one at a time
+func appendUrl(dst entitiesT, text string, start, stop int) entitiesT {
+	if len(dst) == cap(dst) {
+		n := estimateUrls(text[start:])
+		if n < len(dst) {
+			n = len(dst)
+		}
+		grown := make(entitiesT, len(dst), len(dst)+n)
+		copy(grown, dst)
+		dst = grown
+	}
+	return append(dst, TwitterEntity{
+		Text:      text[start:stop],
+		ByteRange: Range{Start: start, Stop: stop},
+		Type:      URL,
+	})
+}
+
+// Returns an estimate of the number of URLs in text, which starts with a
+// URL: the number of space-separated words that contain a '.' followed by
+// a letter, a digit, or a non-ASCII character, between 1 and maxUrlBlock
+func estimateUrls(text string) int {
+	n := 1
+	counted := true // the first word is the URL
+	for i := 0; i+1 < len(text) && n < maxUrlBlock; i++ {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			counted = false
+		case c == '.' && !counted:
+			switch next := text[i+1]; {
+			case next >= utf8.RuneSelf, 'a' <= next && next <= 'z', 'A' <= next && next <= 'Z', '0' <= next && next <= '9':
+				counted = true
+				n++
+			}
+		}
+	}
+	return n
 }
 
 // Extracts @username mentions from the supplied text. Returns a slice
@@ -298,14 +565,22 @@ func ExtractUrls(text string) []*TwitterEntity {
 // The ScreenName field in the returned structs will contain the value
 // of the referenced username without the leading @ sign
 func ExtractMentionedScreenNames(text string) []*TwitterEntity {
-	mentionsOrLists := ExtractMentionsOrLists(text)
-	var result []*TwitterEntity
-	for _, e := range mentionsOrLists {
-		if !e.listSlugIsSet {
-			result = append(result, e)
+	return entitiesT(AppendMentionedScreenNames(nil, text)).pointers()
+}
+
+// Appends the mentions ExtractMentionedScreenNames would return for text
+// to dst, and returns the extended slice. See AppendEntities
+func AppendMentionedScreenNames(dst []TwitterEntity, text string) []TwitterEntity {
+	base := len(dst)
+	dst = extractMentionsOrLists(dst, text, indexSign(text, '@', "＠"), nil)
+	n := base
+	for i := base; i < len(dst); i++ {
+		if !dst[i].listSlugIsSet {
+			dst[n] = dst[i]
+			n++
 		}
 	}
-	return result
+	return dst[:n]
 }
 
 // Extracts @username mentions or list names from the supplied text. Returns
@@ -317,14 +592,51 @@ func ExtractMentionedScreenNames(text string) []*TwitterEntity {
 // The ListSlug field in the returned structs will contain the name of the
 // list (if present), without the leading / or preceding username
 func ExtractMentionsOrLists(text string) []*TwitterEntity {
+	return extractMentionsOrLists(nil, text, indexSign(text, '@', "＠"), nil).pointers()
+}
+
+// Appends the mentions and lists ExtractMentionsOrLists would return for
+// text to dst, and returns the extended slice. See AppendEntities
+func AppendMentionsOrLists(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractMentionsOrLists(dst, text, indexSign(text, '@', "＠"), nil)
+}
+
+// Extracts mentions and lists as ExtractMentionsOrLists does, subject to
+// the given limits. Returns nil and a BudgetExceededError if a limit is
+// exceeded
+func ExtractMentionsOrListsWithLimits(text string, limits Limits) ([]*TwitterEntity, error) {
+	b := newBudget(limits)
+	return withBudget(extractMentionsOrLists(nil, text, indexSign(text, '@', "＠"), b), b)
+}
+
+// Appends the mentions and lists in text to dst, starting at byte offset
+// start, the first @ sign
+func extractMentionsOrLists(dst entitiesT, text string, start int, b *budget) entitiesT {
 	// Optimization
-	if !strings.ContainsAny(text, "@＠") {
-		return nil
+	if start < 0 {
+		return dst
 	}
 
-	var result entitiesT
-	matches := validMentionOrList.FindAllStringSubmatchIndex(text, -1)
-	for _, m := range matches {
+	base := len(dst)
+	if useRegexp {
+		dst = regexpMentionsOrLists(dst, text, b)
+	} else {
+		dst = scanMentionsOrLists(dst, text, start, b)
+	}
+	dst[base:].fixIndices(text)
+	return dst
+}
+
+// Finds mentions and lists using the validMentionOrList regexp. This is
+// the reference implementation for scanMentionsOrLists, and is used
+// instead of it when built with the regexpextract tag
+func regexpMentionsOrLists(dst entitiesT, text string, b *budget) entitiesT {
+	for offset := 0; offset < len(text); {
+		m := findSubmatchIndexFrom(validMentionOrList, validMentionOrListAfterStart, text, offset, b)
+		if m == nil {
+			break
+		}
+		offset = m[1]
 		matchEnd := text[m[1]:]
 		if invalidMentionMatchEnd.MatchString(matchEnd) {
 			continue
@@ -336,28 +648,33 @@ func ExtractMentionsOrLists(text string) []*TwitterEntity {
 		listNameStart := m[validMentionOrListGroupList*2]
 		listNameEnd := m[validMentionOrListGroupList*2+1]
 
-		var slug string
-		start := atSignStart
-		stop := screennameEnd
-		if listNameStart > 0 {
-			slug = text[listNameStart:listNameEnd]
-			stop = listNameEnd
-		}
+		dst = append(dst, newMention(text, atSignStart, screennameStart, screennameEnd, listNameStart, listNameEnd))
+	}
+	return dst
+}
 
-		result = append(result, &TwitterEntity{
-			Text:            text[start:stop],
-			screenName:      text[screennameStart:screennameEnd],
-			screenNameIsSet: true,
-			listSlug:        slug,
-			listSlugIsSet:   slug != "",
-			ByteRange: Range{
-				Start: start,
-				Stop:  stop},
-			Type: MENTION})
+// Returns a MENTION entity for the mention located at the given byte
+// offsets. listNameStart and listNameEnd are negative if the mention does
+// not refer to a list
+func newMention(text string, atSignStart, screennameStart, screennameEnd, listNameStart, listNameEnd int) TwitterEntity {
+	var slug string
+	start := atSignStart
+	stop := screennameEnd
+	if listNameStart > 0 {
+		slug = text[listNameStart:listNameEnd]
+		stop = listNameEnd
 	}
 
-	result.fixIndices(text)
-	return result
+	return TwitterEntity{
+		Text:            text[start:stop],
+		screenName:      text[screennameStart:screennameEnd],
+		screenNameIsSet: true,
+		listSlug:        slug,
+		listSlugIsSet:   slug != "",
+		ByteRange: Range{
+			Start: start,
+			Stop:  stop},
+		Type: MENTION}
 }
 
 // Extracts an @username mention from the beginning of the supplied text. A reply
@@ -394,63 +711,96 @@ func ExtractReplyScreenname(text string) *TwitterEntity {
 // The Hashtag field of the returned entities will contain the value
 // of the extracted hashtag without the leading # character
 func ExtractHashtags(text string) []*TwitterEntity {
-	return extractHashtags(text, true)
+	return extractHashtags(nil, text, indexSign(text, '#', "＃"), true, nil).pointers()
+}
+
+// Appends the hashtags ExtractHashtags would return for text to dst, and
+// returns the extended slice. See AppendEntities
+func AppendHashtags(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractHashtags(dst, text, indexSign(text, '#', "＃"), true, nil)
+}
+
+// Extracts hashtags as ExtractHashtags does, subject to the given limits.
+// Returns nil and a BudgetExceededError if a limit is exceeded
+func ExtractHashtagsWithLimits(text string, limits Limits) ([]*TwitterEntity, error) {
+	b := newBudget(limits)
+	return withBudget(extractHashtags(nil, text, indexSign(text, '#', "＃"), true, b), b)
 }
 
-func extractHashtags(text string, checkUrlOverlap bool) []*TwitterEntity {
+// Appends the hashtags in text to dst, starting at byte offset start, the
+// first # sign. If checkUrlOverlap is true, hashtags that overlap a URL
+// are omitted
+func extractHashtags(dst entitiesT, text string, start int, checkUrlOverlap bool, b *budget) entitiesT {
 	// Optimization
-	if !strings.ContainsAny(text, "#＃") {
-		return nil
+	if start < 0 {
+		return dst
 	}
-	var result entitiesT
-	var hashStart int
-	var hashtagStart int
-	var hashtagEnd int
-	for _, match := range validHashtag.FindAllStringSubmatchIndex(text, -1) {
-		if invalidHashtagMatchEnd.MatchString(text[match[1]:]) {
-			continue
-		}
-		hashStart = match[validHashtagGroupHash*2]
-		hashtagStart = match[validHashtagGroupTag*2]
-		hashtagEnd = match[validHashtagGroupTag*2+1]
-		result = append(result, &TwitterEntity{
-			Text:         text[hashStart:hashtagEnd],
-			hashtag:      text[hashtagStart:hashtagEnd],
-			hashtagIsSet: true,
-			ByteRange: Range{
-				Start: hashStart,
-				Stop:  hashtagEnd,
-			},
-			Type: HASH_TAG})
+	base := len(dst)
+	if useRegexp {
+		dst = regexpHashtags(dst, text, b)
+	} else {
+		dst = scanHashtags(dst, text, start, b)
 	}
+	dst[base:].fixIndices(text)
 
-	result.fixIndices(text)
-
-	if checkUrlOverlap {
-		urls := ExtractUrls(text)
-		result = append(result, urls...)
-		sort.Sort(result)
-		result.removeOverlappingEntities()
+	if checkUrlOverlap && len(dst) > base {
+		// The URLs are appended after the hashtags, and removed again
+		dst = extractUrls(dst, text, urlSearchStart(text), b)
+		result := dst[base:]
+		result.sort()
+		result = result.removeOverlappingEntities()
 
 		numHashtags := 0
-		for _, e := range result {
-			if e.Type == HASH_TAG {
-				result[numHashtags] = e
+		for i := range result {
+			if result[i].Type == HASH_TAG {
+				result[numHashtags] = result[i]
 				numHashtags++
 			}
 		}
-		result = result[:numHashtags]
+		dst = dst[:base+numHashtags]
+	}
 
-		var tmpResult []*TwitterEntity
-		for _, e := range result {
-			if e.Type == HASH_TAG {
-				tmpResult = append(tmpResult, e)
-			}
+	return dst
+}
+
+// Finds hashtags using the validHashtag regexp. This is the reference
+// implementation for scanHashtags, and is used instead of it when built
+// with the regexpextract tag
+func regexpHashtags(dst entitiesT, text string, b *budget) entitiesT {
+	for offset := 0; offset < len(text); {
+		match := findSubmatchIndexFrom(validHashtag, validHashtagAfterStart, text, offset, b)
+		if match == nil {
+			break
 		}
-		result = tmpResult
+		offset = match[1]
+		if invalidHashtagMatchEnd.MatchString(text[match[1]:]) {
+			continue
+		}
+		hashStart := match[validHashtagGroupHash*2]
+		hashtagStart := match[validHashtagGroupTag*2]
+		hashtagEnd := match[validHashtagGroupTag*2+1]
+		// A # followed by VS-16 or a combining keycap is the keycap
+		// emoji #️⃣, not the start of a hashtag
+		if keycapHashtagStart.MatchString(text[hashtagStart:]) {
+			continue
+		}
+		dst = append(dst, newHashtag(text, hashStart, hashtagStart, hashtagEnd))
 	}
+	return dst
+}
 
-	return result
+// Returns a HASH_TAG entity for the hashtag located at the given byte
+// offsets
+func newHashtag(text string, hashStart, hashtagStart, hashtagEnd int) TwitterEntity {
+	return TwitterEntity{
+		Text:         text[hashStart:hashtagEnd],
+		hashtag:      text[hashtagStart:hashtagEnd],
+		hashtagIsSet: true,
+		ByteRange: Range{
+			Start: hashStart,
+			Stop:  hashtagEnd,
+		},
+		Type: HASH_TAG}
 }
 
 // Extracts $cashtag occurrences from the supplied text. Returns a slice
@@ -459,16 +809,47 @@ func extractHashtags(text string, checkUrlOverlap bool) []*TwitterEntity {
 // The Cashtag field of the returned entities will contain the value
 // of the extracted cashtag without the leading $ character
 func ExtractCashtags(text string) []*TwitterEntity {
-	if !strings.Contains(text, "$") {
-		return nil
+	return extractCashtags(nil, text, cashtagSearchStart(text), nil).pointers()
+}
+
+// Appends the cashtags ExtractCashtags would return for text to dst, and
+// returns the extended slice. See AppendEntities
+func AppendCashtags(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractCashtags(dst, text, cashtagSearchStart(text), nil)
+}
+
+// Extracts cashtags as ExtractCashtags does, subject to the given limits.
+// Returns nil and a BudgetExceededError if a limit is exceeded
+func ExtractCashtagsWithLimits(text string, limits Limits) ([]*TwitterEntity, error) {
+	b := newBudget(limits)
+	return withBudget(extractCashtags(nil, text, cashtagSearchStart(text), b), b)
+}
+
+// Returns the offsets of the cashtag, without its $ sign, in the leftmost
+// match of validCashtag in text, and whether there is one
+func findCashtag(text string) (start, end int, ok bool) {
+	if useRegexp {
+		if m := validCashtag.FindStringSubmatchIndex(text); m != nil {
+			return m[validCashtagGroupCashtag*2], m[validCashtagGroupCashtag*2+1], true
+		}
+		return 0, 0, false
+	}
+	return scanCashtag(text)
+}
+
+// Appends the cashtags in text to dst, starting at byte offset start, as
+// returned by cashtagSearchStart
+func extractCashtags(dst entitiesT, text string, start int, b *budget) entitiesT {
+	if start < 0 {
+		return dst
 	}
 
+	base := len(dst)
 	var (
-		result       entitiesT
 		cashtagStart int
 		cashtagEnd   int
 		offset       int
-		nextOffset   int
+		nextOffset   = start
 	)
 
 	// Start at the beginning of the input string,
@@ -478,22 +859,23 @@ func ExtractCashtags(text string) []*TwitterEntity {
 	for {
 		offset = nextOffset
 		substr := text[offset:]
-		match := validCashtag.FindStringSubmatchIndex(substr)
+		if b.exceeded(offset, len(substr)) {
+			break
+		}
+		var ok bool
+		cashtagStart, cashtagEnd, ok = findCashtag(substr)
 
 		// If no matches are found in this portion of the string,
 		// we're done
-		if match == nil {
+		if !ok {
 			break
 		}
 
-		cashtagStart = match[validCashtagGroupCashtag*2]
-		cashtagEnd = match[validCashtagGroupCashtag*2+1]
-
 		// Next time around, start at the end of the current match,
 		// minus 1 because indices are not inclusive
 		nextOffset = cashtagEnd + offset - 1
 
-		result = append(result, &TwitterEntity{
+		dst = append(dst, TwitterEntity{
 			Text:         substr[cashtagStart-1 : cashtagEnd],
 			cashtag:      substr[cashtagStart:cashtagEnd],
 			cashtagIsSet: true,
@@ -504,6 +886,6 @@ func ExtractCashtags(text string) []*TwitterEntity {
 			Type: CASH_TAG,
 		})
 	}
-	result.fixIndices(text)
-	return result
+	dst[base:].fixIndices(text)
+	return dst
 }
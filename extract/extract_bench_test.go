@@ -0,0 +1,40 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	benchmarkPlainText  = strings.Repeat("just a plain tweet without any entities in it at all ", 5)
+	benchmarkEntityText = strings.Repeat("tweet mentioning @username with a url http://t.co/abcde, $TWTR and a #hashtag ", 3)
+)
+
+func BenchmarkExtractEntitiesPlainText(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractEntities(benchmarkPlainText)
+	}
+}
+
+func BenchmarkExtractEntities(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractEntities(benchmarkEntityText)
+	}
+}
+
+func BenchmarkExtractUrlsWithoutUrls(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractUrls(benchmarkPlainText)
+	}
+}
+
+func BenchmarkAppendEntities(b *testing.B) {
+	b.ReportAllocs()
+	var dst []TwitterEntity
+	for i := 0; i < b.N; i++ {
+		dst = AppendEntities(dst[:0], benchmarkEntityText)
+	}
+}
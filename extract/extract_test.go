@@ -2,25 +2,11 @@ package extract
 
 import (
 	"fmt"
-	"os"
-	"path"
+	"reflect"
+	"strings"
+	"testing"
 )
 
-type Conformance struct {
-	Tests map[string][]*Test
-}
-
-type Test struct {
-	Description string
-	Text        string
-	Expected    interface{}
-}
-
-var cwd, _ = os.Getwd()
-var parentDir = path.Dir(cwd)
-var extractYmlPath = path.Join(parentDir, "conformance", "extract.yml")
-var tldYmlPath = path.Join(parentDir, "conformance", "tlds.yml")
-
 func ExampleExtractEntities() {
 	text := "tweet mentioning @username with a url http://t.co/abcde and a #hashtag"
 	entities := ExtractEntities(text)
@@ -47,3 +33,187 @@ func ExampleExtractMentionedScreenNames() {
 	// Match[1]:@user2 Screenname:user2 Range:(15, 21)
 	// Match[2]:@user3 Screenname:user3 Range:(26, 32)
 }
+
+func TestFindTriggers(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected triggers
+	}{
+		{"", triggers{-1, -1, -1, -1}},
+		{"just some words", triggers{-1, -1, -1, -1}},
+		{"a question? an exclamation! a colon:", triggers{-1, -1, -1, -1}},
+		{"日本語", triggers{-1, -1, -1, -1}},
+		{"@user", triggers{0, -1, -1, -1}},
+		{"#hashtag", triggers{-1, 0, -1, -1}},
+		{"$TWTR", triggers{-1, -1, 0, -1}},
+		{"buy $TWTR", triggers{-1, -1, 3, -1}},
+		{"日本語 $TWTR", triggers{-1, -1, 9, -1}},
+		{"example.com", triggers{-1, -1, -1, 0}},
+		{"＠user and @user", triggers{0, -1, -1, -1}},
+		{"@user and ＠user", triggers{0, -1, -1, -1}},
+		{"日本語 ＃タグ #tag", triggers{-1, 10, -1, -1}},
+		{"ｘ ＋ ＃", triggers{-1, 8, -1, -1}},
+	}
+
+	for _, test := range tests {
+		actual := findTriggers(test.text)
+		if actual != test.expected {
+			t.Errorf("findTriggers returned incorrect value for text [%s]. Expected:%+v Got:%+v", test.text, test.expected, actual)
+		}
+		if !actual.any() && ExtractEntities(test.text) != nil {
+			t.Errorf("ExtractEntities found entities in text [%s]", test.text)
+		}
+	}
+}
+
+func TestUrlSearchStart(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", -1},
+		{"no dots here", -1},
+		{"A sentence. Another one... The end.", -1},
+		{"trailing dot.", -1},
+		{"example.com", 0},
+		{"Sentence.Without a space", 0},
+		{"version 1.2", 7},
+		{"example.рф", 0},
+		{"A sentence. And\nhttp://example.com", 15},
+		{"no URL before this one: example.com and twitter.com", 23},
+	}
+
+	for _, test := range tests {
+		if actual := urlSearchStart(test.text); actual != test.expected {
+			t.Errorf("urlSearchStart returned incorrect value for text [%s]. Expected:[%d] Got:[%d]", test.text, test.expected, actual)
+		}
+		if test.expected < 0 && ExtractUrls(test.text) != nil {
+			t.Errorf("ExtractUrls found URLs in text [%s]", test.text)
+		}
+	}
+}
+
+func TestSearchStartsMatchUnseeded(t *testing.T) {
+	texts := append(crossCheckTexts(t),
+		"a.b example.com", "x.com\ty.com", "-.example.com", "sentence. Next.example.com", "日本 example.com",
+		" $A", "$A $B", "x$A $B", "日本\u3000$TWTR", "\u3000$A",
+	)
+	for _, text := range texts {
+		if expected, actual := extractUrls(nil, text, 0, nil), extractUrls(nil, text, urlSearchStart(text), nil); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("extractUrls returned incorrect value for text [%+q] from urlSearchStart. Expected:%v Got:%v", text, expected, actual)
+		}
+		if start := cashtagSearchStart(text); start >= 0 {
+			if expected, actual := extractCashtags(nil, text, 0, nil), extractCashtags(nil, text, start, nil); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("extractCashtags returned incorrect value for text [%+q] from cashtagSearchStart. Expected:%v Got:%v", text, expected, actual)
+			}
+		}
+	}
+}
+
+func TestEstimateUrls(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"example.com", 1},
+		{"example.com and twitter.com", 2},
+		{"www.example.com/a.b twitter.com. The end.", 2},
+		{"example.com e.g. v1.2 t.co/x", 4},
+		{strings.Repeat("a.co ", 20), maxUrlBlock},
+	}
+
+	for _, test := range tests {
+		if actual := estimateUrls(test.text); actual != test.expected {
+			t.Errorf("estimateUrls returned incorrect value for text [%s]. Expected:[%d] Got:[%d]", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestExtractUrlsAllocations(t *testing.T) {
+	if useRegexp {
+		t.Skip("the regexps allocate for every match")
+	}
+	// One allocation per urlTail match, plus the entities and the slice
+	// that holds them
+	text := "http://example.com/path?q=1, twitter.com and https://t.co/abc"
+	if allocs := testing.AllocsPerRun(100, func() { ExtractUrls(text) }); allocs > 5 {
+		t.Errorf("ExtractUrls made %v allocations for text [%s]. Expected at most 5", allocs, text)
+	}
+}
+
+var appendExtractors = []struct {
+	name    string
+	extract func(string) []*TwitterEntity
+	append  func([]TwitterEntity, string) []TwitterEntity
+}{
+	{"Entities", ExtractEntities, AppendEntities},
+	{"Urls", ExtractUrls, AppendUrls},
+	{"MentionedScreenNames", ExtractMentionedScreenNames, AppendMentionedScreenNames},
+	{"MentionsOrLists", ExtractMentionsOrLists, AppendMentionsOrLists},
+	{"Hashtags", ExtractHashtags, AppendHashtags},
+	{"Cashtags", ExtractCashtags, AppendCashtags},
+	{"Emoji", ExtractEmoji, AppendEmoji},
+}
+
+func TestAppendMatchesExtract(t *testing.T) {
+	prefix := TwitterEntity{Text: "prefix", Type: URL}
+	for _, text := range conformanceTexts(t) {
+		for _, e := range appendExtractors {
+			var expected []TwitterEntity
+			for _, entity := range e.extract(text) {
+				expected = append(expected, *entity)
+			}
+
+			if actual := e.append(nil, text); len(actual) != len(expected) || len(actual) > 0 && !reflect.DeepEqual(actual, expected) {
+				t.Errorf("Append%s returned incorrect value for text [%s]. Expected:%v Got:%v", e.name, text, expected, actual)
+			}
+			actual := e.append([]TwitterEntity{prefix}, text)
+			if len(actual) != 1+len(expected) || actual[0] != prefix || len(expected) > 0 && !reflect.DeepEqual(actual[1:], expected) {
+				t.Errorf("Append%s returned incorrect value for text [%s] appended to an entity. Expected:%v Got:%v", e.name, text, expected, actual)
+			}
+		}
+	}
+}
+
+func TestExtractEntitiesRemovesOverlapping(t *testing.T) {
+	// Each mention overlaps the URL, not just the mention before it
+	text := "see http://x.com/@a/@b/@c ok"
+	entities := ExtractEntities(text)
+	if len(entities) != 1 || entities[0].Type != URL || entities[0].Text != "http://x.com/@a/@b/@c" {
+		t.Errorf("ExtractEntities returned incorrect value for text [%s]. Expected:[http://x.com/@a/@b/@c] Got:%v", text, entities)
+	}
+}
+
+func TestExtractEntitiesKeepsEntityAfterRemovedOverlap(t *testing.T) {
+	// The URL t.co/=#é overlaps the first hashtag and is removed, so the
+	// second hashtag, which only overlaps the URL, is kept
+	text := "#comみんなt.co/=#é"
+	expected := []string{"#comみんなt", "#é"}
+	entities := ExtractEntities(text)
+	if len(entities) != len(expected) {
+		t.Fatalf("ExtractEntities returned incorrect value for text [%s]. Expected:%v Got:%v", text, expected, entities)
+	}
+	for i, e := range entities {
+		if e.Type != HASH_TAG || e.Text != expected[i] {
+			t.Errorf("ExtractEntities returned incorrect value for text [%s]. Expected:%v Got:%v", text, expected, entities)
+			break
+		}
+	}
+}
+
+func TestAppendAllocations(t *testing.T) {
+	text := "tweet mentioning @username with a url http://t.co/abcde, $TWTR and a #hashtag \U0001F600"
+	for _, e := range appendExtractors {
+		extractAllocs := testing.AllocsPerRun(100, func() {
+			e.extract(text)
+		})
+		dst := e.append(nil, text)
+		appendAllocs := testing.AllocsPerRun(100, func() {
+			dst = e.append(dst[:0], text)
+		})
+		// Extract allocates the entities and the slice of pointers to them
+		if appendAllocs > extractAllocs-2 {
+			t.Errorf("Append%s allocated %v times with a reused buffer, expected at most %v", e.name, appendAllocs, extractAllocs-2)
+		}
+	}
+}
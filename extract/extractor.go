@@ -0,0 +1,133 @@
+package extract
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// A Matcher finds custom entities in text, such as issue references like
+// #123 in a developer tool or internal short codes, for an Extractor
+type Matcher interface {
+	// Returns the byte offsets of the entities in text, in any order
+	Match(text string) []Range
+}
+
+// Adapts a function to the Matcher interface
+type MatcherFunc func(text string) []Range
+
+func (f MatcherFunc) Match(text string) []Range {
+	return f(text)
+}
+
+// Returns a Matcher that finds the matches of re. If re has a
+// subexpression, each entity covers the text it matches rather than the
+// whole match, so that re can require context around the entity, e.g.
+// `(?:^|\s)(#\d+)\b`
+func RegexpMatcher(re *regexp.Regexp) Matcher {
+	return MatcherFunc(func(text string) []Range {
+		var result []Range
+		for _, match := range re.FindAllStringSubmatchIndex(text, -1) {
+			if len(match) > 2 {
+				match = match[2:4]
+			}
+			if match[0] >= 0 {
+				result = append(result, Range{Start: match[0], Stop: match[1]})
+			}
+		}
+		return result
+	})
+}
+
+// An Extractor extracts the entities ExtractEntities does along with
+// custom entities found by registered Matchers. Custom entities have
+// Type=CUSTOM and the kind they were registered with, and take part in
+// the removal of overlapping entities: an entity that overlaps one that
+// starts before it is removed, and of entities that start at the same
+// offset, built-in entities are kept over custom entities, and custom
+// entities are kept in the order their Matchers were registered. The
+// results can be passed to the autolink package like any other entities.
+//
+// The zero value extracts only the built-in entities. Matchers must not
+// be registered while the Extractor is in use by other goroutines
+type Extractor struct {
+	// Whether to extract mentions of accounts on other servers of
+	// federated services such as Mastodon, of the form @user@domain, as
+	// MENTION entities with the domain set. They are not extracted by
+	// default, as the reference implementations do not extract a mention
+	// followed by a second @ sign
+	FederatedMentions bool
+
+	matchers []kindMatcher
+}
+
+type kindMatcher struct {
+	kind    string
+	matcher Matcher
+}
+
+// Registers m to find custom entities of the given kind, e.g. "issue"
+func (x *Extractor) Register(kind string, m Matcher) {
+	x.matchers = append(x.matchers, kindMatcher{kind, m})
+}
+
+// Extract all usernames, lists, hashtags, URLs, and custom entities from
+// the given text - returned in the order they appear within the input
+// string
+func (x *Extractor) ExtractEntities(text string) []*TwitterEntity {
+	return entitiesT(x.AppendEntities(nil, text)).pointers()
+}
+
+// Appends the entities ExtractEntities would return for text to dst, and
+// returns the extended slice. See AppendEntities
+func (x *Extractor) AppendEntities(dst []TwitterEntity, text string) []TwitterEntity {
+	return extractEntities(dst, text, x, nil)
+}
+
+// Extract @username mentions and lists as the ExtractMentionsOrLists
+// function does, along with mentions of the form @user@domain if
+// FederatedMentions is set. Custom entities are not extracted
+func (x *Extractor) ExtractMentionsOrLists(text string) []*TwitterEntity {
+	start := indexSign(text, '@', "＠")
+	if !x.FederatedMentions {
+		return extractMentionsOrLists(nil, text, start, nil).pointers()
+	}
+	dst := extractFederatedMentions(nil, text, nil)
+	dst = extractMentionsOrLists(dst, text, start, nil)
+	dst.sort()
+	return dst.removeOverlappingEntities().pointers()
+}
+
+// Appends the custom entities found by matchers to dst, in the order the
+// matchers are given. Each matcher is charged to b as an attempt that may
+// examine all of text before it is run
+func appendCustomEntities(dst entitiesT, text string, matchers []kindMatcher, b *budget) entitiesT {
+	base := len(dst)
+	for _, m := range matchers {
+		if b.exceeded(0, len(text)) {
+			break
+		}
+		for _, r := range m.matcher.Match(text) {
+			if !validRange(text, r) {
+				continue
+			}
+			dst = append(dst, TwitterEntity{
+				Text:      text[r.Start:r.Stop],
+				ByteRange: r,
+				Type:      CUSTOM,
+				kind:      m.kind,
+				kindIsSet: true,
+			})
+		}
+	}
+	dst[base:].fixIndices(text)
+	return dst
+}
+
+// Reports whether r is a non-empty range of text whose offsets fall on
+// character boundaries. Matchers may return any ranges; others are ignored
+func validRange(text string, r Range) bool {
+	if r.Start < 0 || r.Start >= r.Stop || r.Stop > len(text) {
+		return false
+	}
+	return utf8.RuneStart(text[r.Start]) && (r.Stop == len(text) || utf8.RuneStart(text[r.Stop]))
+}
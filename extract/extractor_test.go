@@ -0,0 +1,194 @@
+package extract
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func ExampleExtractor() {
+	var x Extractor
+	x.Register("issue", RegexpMatcher(regexp.MustCompile(`(?:^|\s)(#\d+)\b`)))
+
+	for _, e := range x.ExtractEntities("fixes #123 for @user #golang") {
+		kind, _ := e.Kind()
+		fmt.Printf("Entity:%s Type:%v Kind:%s\n", e.Text, e.Type, kind)
+	}
+	// Output:
+	// Entity:#123 Type:CUSTOM Kind:issue
+	// Entity:@user Type:MENTION Kind:
+	// Entity:#golang Type:HASH_TAG Kind:
+}
+
+// Returns a Matcher that finds each occurrence of s
+func stringMatcher(s string) Matcher {
+	return RegexpMatcher(regexp.MustCompile(regexp.QuoteMeta(s)))
+}
+
+func TestExtractor(t *testing.T) {
+	type entity struct {
+		Text  string
+		Type  EntityType
+		Kind  string
+		Range Range
+	}
+	tests := []struct {
+		text     string
+		matchers map[string]Matcher
+		expected []entity
+	}{
+		{"no entities", nil, nil},
+		{"@user and #tag", nil, []entity{
+			{"@user", MENTION, "", Range{0, 5}},
+			{"#tag", HASH_TAG, "", Range{10, 14}},
+		}},
+		{"see GO-42 now", map[string]Matcher{"code": stringMatcher("GO-42")}, []entity{
+			{"GO-42", CUSTOM, "code", Range{4, 9}},
+		}},
+		// Custom entities are ordered with the built-in entities, with
+		// character offsets
+		{"日本 GO-42 @user", map[string]Matcher{"code": stringMatcher("GO-42")}, []entity{
+			{"GO-42", CUSTOM, "code", Range{3, 8}},
+			{"@user", MENTION, "", Range{9, 14}},
+		}},
+		// Built-in entities are kept over custom entities at the same
+		// offset, and entities that overlap an earlier one are removed
+		{"#tag", map[string]Matcher{"tag": stringMatcher("#tag")}, []entity{
+			{"#tag", HASH_TAG, "", Range{0, 4}},
+		}},
+		{"@user", map[string]Matcher{"user": stringMatcher("ser")}, []entity{
+			{"@user", MENTION, "", Range{0, 5}},
+		}},
+		{"ab@user", map[string]Matcher{"word": stringMatcher("ab@u")}, []entity{
+			{"ab@u", CUSTOM, "word", Range{0, 4}},
+		}},
+		// Invalid ranges are ignored
+		{"日本", map[string]Matcher{"bad": MatcherFunc(func(string) []Range {
+			return []Range{{-1, 1}, {1, 3}, {0, 1}, {3, 3}, {3, 7}, {3, 6}}
+		})}, []entity{
+			{"本", CUSTOM, "bad", Range{1, 2}},
+		}},
+	}
+
+	for _, test := range tests {
+		var x Extractor
+		for kind, m := range test.matchers {
+			x.Register(kind, m)
+		}
+		var actual []entity
+		for _, e := range x.ExtractEntities(test.text) {
+			kind, isSet := e.Kind()
+			if isSet != (e.Type == CUSTOM) {
+				t.Errorf("Extractor returned entity [%s] with incorrect Kind for text [%s]", e.Text, test.text)
+			}
+			actual = append(actual, entity{e.Text, e.Type, kind, e.Range})
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Extractor returned incorrect value for text [%s]. Expected:%v Got:%v", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestExtractorRegistrationOrder(t *testing.T) {
+	text := "ABC-1"
+	var x Extractor
+	x.Register("first", stringMatcher("ABC"))
+	x.Register("second", stringMatcher("ABC-1"))
+
+	entities := x.ExtractEntities(text)
+	if len(entities) != 1 {
+		t.Fatalf("Extractor returned %d entities for text [%s]. Expected:1", len(entities), text)
+	}
+	if kind, _ := entities[0].Kind(); kind != "first" {
+		t.Errorf("Extractor returned incorrect value for text [%s]. Expected:first Got:%s", text, kind)
+	}
+}
+
+func TestExtractorMatchesExtractEntities(t *testing.T) {
+	var x Extractor
+	x.Register("none", MatcherFunc(func(string) []Range { return nil }))
+	for _, text := range []string{
+		"",
+		"tweet mentioning @username with a url http://t.co/abcde and a #hashtag",
+		"$TWTR @user/list #tag http://example.com/#tag",
+	} {
+		expected := ExtractEntities(text)
+		for _, actual := range [][]*TwitterEntity{new(Extractor).ExtractEntities(text), x.ExtractEntities(text)} {
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("Extractor returned incorrect value for text [%s]. Expected:%v Got:%v", text, expected, actual)
+			}
+		}
+	}
+}
+
+func TestSortIsStable(t *testing.T) {
+	var entities entitiesT
+	for i := 0; i < 20; i++ {
+		entities = append(entities, TwitterEntity{Range: Range{Start: (20 - i) / 2}, Text: fmt.Sprintf("%02d", i)})
+	}
+	entities.sort()
+	for i := 1; i < len(entities); i++ {
+		prev, cur := entities[i-1], entities[i]
+		if prev.Range.Start == cur.Range.Start && prev.Text > cur.Text {
+			t.Errorf("sort reordered entities with the same start offset: [%s] before [%s]", prev.Text, cur.Text)
+		}
+	}
+}
+
+func TestExtractorFederatedMentions(t *testing.T) {
+	type mention struct {
+		Text       string
+		ScreenName string
+		Domain     string
+		Range      Range
+	}
+	tests := []struct {
+		text     string
+		expected []mention
+	}{
+		{"@user@mastodon.social", []mention{{"@user@mastodon.social", "user", "mastodon.social", Range{0, 21}}}},
+		{"hi @Gargron@mastodon.social and @jack.", []mention{
+			{"@Gargron@mastodon.social", "Gargron", "mastodon.social", Range{3, 27}},
+			{"@jack", "jack", "", Range{32, 37}},
+		}},
+		{"日本 ＠user＠example.org.", []mention{{"＠user＠example.org", "user", "example.org", Range{3, 20}}}},
+		{"@a_long_username_of_thirty_ch@sub.example.co.uk, ok", []mention{
+			{"@a_long_username_of_thirty_ch@sub.example.co.uk", "a_long_username_of_thirty_ch", "sub.example.co.uk", Range{0, 47}},
+		}},
+		// Email addresses, incomplete domains, and URLs are not mentions
+		{"mail foo@example.com", nil},
+		{"@user@localhost", nil},
+		{"@user@example.com/path", nil},
+		{"@user@example.com@other.org", nil},
+	}
+
+	x := Extractor{FederatedMentions: true}
+	for _, test := range tests {
+		var actual []mention
+		for _, e := range x.ExtractMentionsOrLists(test.text) {
+			screenName, _ := e.ScreenName()
+			domain, isSet := e.Domain()
+			if isSet != (domain != "") {
+				t.Errorf("ExtractMentionsOrLists returned entity [%s] with incorrect Domain for text [%s]", e.Text, test.text)
+			}
+			actual = append(actual, mention{e.Text, screenName, domain, e.Range})
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("ExtractMentionsOrLists returned incorrect value for text [%s]. Expected:%v Got:%v", test.text, test.expected, actual)
+		}
+	}
+
+	// Federated mentions take the place of the URLs in their domains, and
+	// are not extracted by default
+	text := "follow @user@mastodon.social #fediverse"
+	entities := x.ExtractEntities(text)
+	if len(entities) != 2 || entities[0].Text != "@user@mastodon.social" || entities[0].Type != MENTION || entities[1].Type != HASH_TAG {
+		t.Errorf("ExtractEntities returned incorrect value for text [%s]. Got:%v", text, entities)
+	}
+	for _, e := range append(ExtractEntities(text), new(Extractor).ExtractMentionsOrLists(text)...) {
+		if e.Type == MENTION {
+			t.Errorf("ExtractEntities returned a federated mention for text [%s] by default: %v", text, e)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package extract
+
+import (
+	"regexp"
+)
+
+// Matches a mention of an account on another server, e.g.
+// @user@mastodon.social: a username of up to 30 characters, as allowed by
+// Mastodon, followed by @ and a domain name
+var validFederatedMention = regexp.MustCompile(`(?:^|` + federatedMentionBoundary + `)` + federatedMention)
+
+// Matches as validFederatedMention does anywhere but at the start of the
+// text
+var validFederatedMentionAfterStart = regexp.MustCompile(federatedMentionBoundary + federatedMention)
+
+const (
+	federatedMentionBoundary = `[^a-zA-Z0-9_!#$%&*@＠]`
+	federatedMention         = `([@＠])([a-zA-Z0-9_]{1,30})[@＠]((?:[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]{2,63})`
+)
+
+const (
+	validFederatedMentionGroupAt       = 1
+	validFederatedMentionGroupUsername = 2
+	validFederatedMentionGroupDomain   = 3
+)
+
+// Matches text that may not follow a federated mention: more of a domain
+// name, another @ sign, or a path or protocol that makes it part of a URL
+var invalidFederatedMentionMatchEnd = regexp.MustCompile(`^(?:[a-zA-Z0-9_@＠-]|\.[a-zA-Z0-9]|/)`)
+
+// Appends the mentions of the form @user@domain in text to dst
+func extractFederatedMentions(dst entitiesT, text string, b *budget) entitiesT {
+	// Optimization
+	if indexSign(text, '@', "＠") < 0 {
+		return dst
+	}
+
+	base := len(dst)
+	for offset := 0; offset < len(text); {
+		m := findSubmatchIndexFrom(validFederatedMention, validFederatedMentionAfterStart, text, offset, b)
+		if m == nil {
+			break
+		}
+		offset = m[1]
+		if invalidFederatedMentionMatchEnd.MatchString(text[m[1]:]) {
+			continue
+		}
+		atSignStart := m[validFederatedMentionGroupAt*2]
+		e := newMention(text, atSignStart,
+			m[validFederatedMentionGroupUsername*2], m[validFederatedMentionGroupUsername*2+1], -1, -1)
+		e.Text = text[atSignStart:m[1]]
+		e.ByteRange.Stop = m[1]
+		e.domain = text[m[validFederatedMentionGroupDomain*2]:m[1]]
+		e.domainIsSet = true
+		dst = append(dst, e)
+	}
+	dst[base:].fixIndices(text)
+	return dst
+}
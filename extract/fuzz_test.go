@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package extract
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// Checks the invariants of the entities an extractor returns for any
+// text, including malformed UTF-8: they are in order, do not overlap, lie
+// within the text, and their Text and Range agree with their ByteRange
+func checkEntities(t *testing.T, name, text string, entities []*TwitterEntity) {
+	prevStop := 0
+	for _, e := range entities {
+		if e.ByteRange.Start < prevStop || e.ByteRange.Start >= e.ByteRange.Stop || e.ByteRange.Stop > len(text) {
+			t.Fatalf("%s returned an entity out of order or out of bounds for text [%+q]: %v %+v", name, text, e, e.ByteRange)
+		}
+		if e.Text != text[e.ByteRange.Start:e.ByteRange.Stop] {
+			t.Fatalf("%s returned an entity whose text is not its byte range of text [%+q]: %v %+v", name, text, e, e.ByteRange)
+		}
+		start := utf8.RuneCountInString(text[:e.ByteRange.Start])
+		if e.Range.Start != start || e.Range.Stop != start+utf8.RuneCountInString(e.Text) {
+			t.Fatalf("%s returned an entity whose range does not match its byte range for text [%+q]: %v %+v", name, text, e, e.ByteRange)
+		}
+		prevStop = e.ByteRange.Stop
+	}
+}
+
+func FuzzExtractEntities(f *testing.F) {
+	for _, text := range conformanceTexts(f) {
+		f.Add(text)
+	}
+	f.Add("see http://x.com/@a/@b/@c ok")
+	f.Add("\xff@user #\xfe $TWTR\x80 http://example.com/\xc0")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		for _, e := range appendExtractors {
+			entities := e.extract(text)
+			checkEntities(t, "Extract"+e.name, text, entities)
+
+			var expected []TwitterEntity
+			for _, entity := range entities {
+				expected = append(expected, *entity)
+			}
+			if actual := e.append(nil, text); len(actual) != len(expected) || len(actual) > 0 && !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("Append%s returned incorrect value for text [%+q]. Expected:%v Got:%v", e.name, text, expected, actual)
+			}
+		}
+
+		// Every entity ExtractEntities returns is found by the
+		// extractor for its type
+		for _, e := range ExtractEntities(text) {
+			var found []*TwitterEntity
+			switch e.Type {
+			case URL:
+				found = ExtractUrls(text)
+			case HASH_TAG:
+				found = ExtractHashtags(text)
+			case MENTION:
+				found = ExtractMentionsOrLists(text)
+			case CASH_TAG:
+				found = ExtractCashtags(text)
+			}
+			if !containsEntity(found, e) {
+				t.Fatalf("ExtractEntities returned an entity for text [%+q] that its extractor did not: %v", text, e)
+			}
+		}
+	})
+}
+
+// Reports whether entities contains an entity equal to e
+func containsEntity(entities []*TwitterEntity, e *TwitterEntity) bool {
+	for _, entity := range entities {
+		if reflect.DeepEqual(entity, e) {
+			return true
+		}
+	}
+	return false
+}
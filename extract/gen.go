@@ -0,0 +1,274 @@
+//go:build ignore
+// +build ignore
+
+// Generates tld_tables.go from tlds_generic.txt and tlds_country.txt
+//
+// Usage:
+//
+//	go run gen.go [-output tld_tables.go] [-minimal-output tld_tables_minimal.go]
+//
+// Builds with the minimaltables tag use tld_tables_minimal.go, which holds
+// only the TLDs that are written in ASCII.
+//
+// Besides the lists themselves, which are used to build the TLD tries, the
+// output holds the TLD alternations used by the URL regexps. Rather than
+// listing every TLD, these factor out common prefixes, across the generic
+// and country code lists as well as within them, and merge single
+// characters into character classes, which makes the patterns shorter and
+// cheaper to compile. The alternations prefer the same TLD as the plain
+// lists would when several match.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	output        = flag.String("output", "tld_tables.go", "output file")
+	minimalOutput = flag.String("minimal-output", "tld_tables_minimal.go", "output file for builds with the minimaltables tag")
+)
+
+func main() {
+	flag.Parse()
+
+	generic, err := readList("tlds_generic.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	country, err := readList("tlds_country.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	write(*output, "!minimaltables", generic, country)
+	write(*minimalOutput, "minimaltables", asciiOnly(generic), asciiOnly(country))
+}
+
+// Writes the tables for the given TLDs to the named file, which is built
+// only with the given build constraint
+func write(name, constraint string, generic, country []string) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gen.go from tlds_generic.txt and tlds_country.txt; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "//go:build %s\n// +build %s\n\n", constraint, constraint)
+	fmt.Fprintf(&buf, "package extract\n\n")
+	fmt.Fprintf(&buf, "const (\n")
+	writeConst(&buf, "genericTLDs", "The generic TLDs, separated by spaces, in the order they are tried", strings.Join(generic, " "))
+	writeConst(&buf, "countryTLDs", "The country code TLDs, separated by spaces, in the order they are tried", strings.Join(country, " "))
+	writeConst(&buf, "urlValidTLD", "Matches any of the generic and country code TLDs", factor(append(generic, country...)))
+	writeConst(&buf, "urlValidCCTLD", "Matches any of the country code TLDs", factor(country))
+	fmt.Fprintf(&buf, ")\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(name, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Returns the lowercased TLDs listed in the named file, in order
+func readList(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var tlds []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.ContainsAny(line, " |()[]") {
+			return nil, fmt.Errorf("%s: invalid TLD %q", name, line)
+		}
+		tlds = append(tlds, strings.ToLower(line))
+	}
+	return tlds, scanner.Err()
+}
+
+// Returns the TLDs in tlds that consist only of ASCII characters
+func asciiOnly(tlds []string) []string {
+	var result []string
+	for _, tld := range tlds {
+		if strings.IndexFunc(tld, func(r rune) bool { return r >= utf8.RuneSelf }) < 0 {
+			result = append(result, tld)
+		}
+	}
+	return result
+}
+
+// Writes a string constant, split over several lines after spaces or
+// bars where possible
+func writeConst(buf *bytes.Buffer, name, doc, value string) {
+	const lineLength = 100
+
+	fmt.Fprintf(buf, "// %s\n%s = ", doc, name)
+	for first := true; first || value != ""; first = false {
+		n := 0
+		for n < len(value) && n < lineLength {
+			_, size := utf8.DecodeRuneInString(value[n:])
+			n += size
+		}
+		if i := strings.LastIndexAny(value[:n], " |"); n < len(value) && i > 0 {
+			n = i + 1
+		}
+		if !first {
+			buf.WriteString(" +\n\t")
+		}
+		fmt.Fprintf(buf, "%q", value[:n])
+		value = value[n:]
+	}
+	buf.WriteString("\n\n")
+}
+
+// Returns a pattern that matches the same strings as the alternation of
+// alts and, where several of them match a text, prefers the same one.
+// Alternatives that begin with different characters never match at the
+// same position, so they may be reordered and grouped by their first
+// character. Only an empty alternative, which matches wherever any other
+// does, must keep its place relative to the others.
+func factor(alts []string) string {
+	alts = dedupe(alts)
+
+	empty := -1
+	for i, alt := range alts {
+		if alt == "" {
+			empty = i
+		}
+	}
+	if empty < 0 {
+		return alternation(group(alts))
+	}
+	before, after := group(alts[:empty]), group(alts[empty+1:])
+	switch {
+	case len(before) == 0 && len(after) == 0:
+		return ""
+	case len(after) == 0:
+		return atom(alternation(before)) + "?"
+	case len(before) == 0:
+		return atom(alternation(after)) + "??"
+	}
+	return "(?:" + strings.Join(before, "|") + "||" + strings.Join(after, "|") + ")"
+}
+
+// Returns alts without repeated alternatives, which are never chosen
+func dedupe(alts []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, alt := range alts {
+		if !seen[alt] {
+			seen[alt] = true
+			result = append(result, alt)
+		}
+	}
+	return result
+}
+
+// Groups non-empty alternatives by their first character and returns a
+// pattern for each group. The groups that consist of a single character
+// are merged into one character class
+func group(alts []string) []string {
+	var order []rune
+	rests := map[rune][]string{}
+	for _, alt := range alts {
+		r, size := utf8.DecodeRuneInString(alt)
+		if _, ok := rests[r]; !ok {
+			order = append(order, r)
+		}
+		rests[r] = append(rests[r], alt[size:])
+	}
+
+	var singles []rune
+	var patterns []string
+	for _, r := range order {
+		if rest := rests[r]; len(rest) == 1 && rest[0] == "" {
+			singles = append(singles, r)
+			continue
+		}
+		patterns = append(patterns, regexp.QuoteMeta(string(r))+factor(rests[r]))
+	}
+	if len(singles) > 0 {
+		patterns = append([]string{class(singles)}, patterns...)
+	}
+	return patterns
+}
+
+// Returns a character class matching runes, using ranges for runs of
+// consecutive characters
+func class(runes []rune) string {
+	if len(runes) == 1 {
+		return regexp.QuoteMeta(string(runes[0]))
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 {
+			j++
+		}
+		b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		if j-i >= 2 {
+			b.WriteByte('-')
+		}
+		if j > i {
+			b.WriteString(regexp.QuoteMeta(string(runes[j])))
+		}
+		i = j + 1
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Returns a pattern that matches any of patterns
+func alternation(patterns []string) string {
+	if len(patterns) == 1 {
+		return patterns[0]
+	}
+	return "(?:" + strings.Join(patterns, "|") + ")"
+}
+
+// Returns pattern as a single unit to which an operator such as ? may be
+// applied
+func atom(pattern string) string {
+	if utf8.RuneCountInString(pattern) == 1 || isGroup(pattern, '[', ']') || isGroup(pattern, '(', ')') {
+		return pattern
+	}
+	return "(?:" + pattern + ")"
+}
+
+// Reports whether pattern is a single group delimited by open and close.
+// TLDs contain no metacharacters that would need to be skipped
+func isGroup(pattern string, open, close byte) bool {
+	if pattern == "" || pattern[0] != open {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i == len(pattern)-1
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,6 @@
+//go:build !minimaltables
+// +build !minimaltables
+
+package extract
+
+const minimalTables = false
@@ -0,0 +1,96 @@
+package extract
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Most tweets contain no entities at all, and most of those that do contain
+// only a few. Before running an extractor, the text is swept for the bytes
+// that every entity of its kind contains with strings.IndexByte, which is
+// much faster than examining each character, and the extractor starts at
+// the first of them rather than at the start of the text.
+
+// Where each kind of entity may first occur in a text. Each field is a byte
+// offset at which an extractor may start, or -1 if the text contains no
+// entities of that kind
+type triggers struct {
+	mention int // the first @ or ＠
+	hashtag int // the first # or ＃
+	cashtag int // see cashtagSearchStart
+	url     int // see urlSearchStart
+}
+
+// Returns the triggers for every kind of entity in text
+func findTriggers(text string) triggers {
+	return triggers{
+		mention: indexSign(text, '@', "＠"),
+		hashtag: indexSign(text, '#', "＃"),
+		cashtag: cashtagSearchStart(text),
+		url:     urlSearchStart(text),
+	}
+}
+
+// Reports whether text may contain entities of any kind
+func (t triggers) any() bool {
+	return t.mention >= 0 || t.hashtag >= 0 || t.cashtag >= 0 || t.url >= 0
+}
+
+// Returns the byte offset of the first occurrence in text of the ASCII sign
+// c or its full-width form, or -1 if there is none. The full-width form is
+// only searched for before the first c
+func indexSign(text string, c byte, fullWidth string) int {
+	i := strings.IndexByte(text, c)
+	limit := i
+	if i < 0 {
+		limit = len(text)
+	}
+	for j := 0; j < limit; j++ {
+		k := strings.IndexByte(text[j:limit], fullWidth[0])
+		if k < 0 {
+			break
+		}
+		j += k
+		if strings.HasPrefix(text[j:], fullWidth) {
+			return j
+		}
+	}
+	return i
+}
+
+// Returns the byte offset at which to start searching text for cashtags,
+// or -1 if it contains none: the character before the first $, which must
+// be a space, or the $ itself if it starts the text
+func cashtagSearchStart(text string) int {
+	i := strings.IndexByte(text, '$')
+	if i <= 0 {
+		return i
+	}
+	_, size := utf8.DecodeLastRuneInString(text[:i])
+	return i - size
+}
+
+// Returns the byte offset at which to start searching text for URLs, or -1
+// if it contains none. The domain of every URL contains a '.' that is
+// followed by the next label of the domain, so text without a '.' followed
+// by a letter, a digit, or a non-ASCII character, such as ordinary
+// sentences, cannot contain a URL. A URL cannot contain whitespace either,
+// so none starts before the last whitespace that precedes the first such
+// '.', which may itself precede a URL
+func urlSearchStart(text string) int {
+	for i := strings.IndexByte(text, '.'); i >= 0 && i+1 < len(text); {
+		switch c := text[i+1]; {
+		case c >= utf8.RuneSelf, 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			if start := strings.LastIndexAny(text[:i], " \t\n\r"); start > 0 {
+				return start
+			}
+			return 0
+		}
+		j := strings.IndexByte(text[i+1:], '.')
+		if j < 0 {
+			break
+		}
+		i += 1 + j
+	}
+	return -1
+}
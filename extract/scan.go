@@ -0,0 +1,270 @@
+package extract
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hand-written equivalents of the validMentionOrList, validHashtag, and
+// validCashtag regexps. Extraction spends most of its time matching those patterns, and
+// a direct scan for @ and # signs is considerably faster. The scanners
+// reproduce the leftmost-first, non-overlapping semantics of
+// FindAllStringSubmatchIndex exactly, including the case folding applied
+// by (?i): ſ (U+017F) and K (U+212A) match s and k.
+//
+// Build with the regexpextract tag to use the regexps instead, e.g. to
+// cross-check the two implementations.
+
+const (
+	maxUsernameLength = 20
+	maxListSlugLength = 25
+)
+
+// Reports whether r matches (?i)[a-z0-9_]
+func isUsernameChar(r rune) bool {
+	return r == '_' || ('0' <= r && r <= '9') || isAsciiLetter(r)
+}
+
+// Reports whether r matches (?i)[a-z]
+func isAsciiLetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r == '\u017F' || r == '\u212A'
+}
+
+// Reports whether r may precede the @ sign of a mention
+func isMentionBoundary(r rune) bool {
+	if isUsernameChar(r) {
+		return false
+	}
+	switch r {
+	case '!', '#', '$', '%', '&', '*', '@', '＠':
+		return false
+	}
+	return true
+}
+
+// Reports whether text matches ^\s*RT:?$ (case insensitive), i.e. whether
+// a mention immediately following text is a retweet
+func isRetweetPrefix(text string) bool {
+	text = strings.TrimLeft(text, "\t\n\f\r ")
+	text = strings.TrimSuffix(text, ":")
+	return len(text) == 2 && (text[0] == 'R' || text[0] == 'r') && (text[1] == 'T' || text[1] == 't')
+}
+
+func scanMentionsOrLists(dst entitiesT, text string, start int, b *budget) entitiesT {
+	var (
+		pos     int // end of the previous match; the next match starts here or later
+		charged int // the text before this offset has been charged to b
+	)
+	for next := start; next < len(text); {
+		i := strings.IndexAny(text[next:], "@＠")
+		if i < 0 {
+			break
+		}
+		atSignStart := next + i
+		if b.exceeded(atSignStart, atSignStart-charged) {
+			break
+		}
+		charged = atSignStart
+
+		if !precededByBoundary(text, pos, atSignStart, isMentionBoundary) &&
+			!(pos == 0 && isRetweetPrefix(text[:atSignStart])) {
+			_, size := utf8.DecodeRuneInString(text[atSignStart:])
+			next = atSignStart + size
+			continue
+		}
+
+		// [@＠]+
+		screennameStart := atSignStart
+		for screennameStart < len(text) {
+			r, size := utf8.DecodeRuneInString(text[screennameStart:])
+			if r != '@' && r != '＠' {
+				break
+			}
+			screennameStart += size
+		}
+
+		// [a-z0-9_]{1,20}
+		screennameEnd := scanRun(text, screennameStart, maxUsernameLength, isUsernameChar)
+		if screennameEnd == screennameStart {
+			next = screennameStart
+			continue
+		}
+
+		// (/[a-z][a-z0-9_-]{0,24})?
+		listNameStart, listNameEnd := -1, -1
+		end := screennameEnd
+		if strings.HasPrefix(text[end:], "/") {
+			r, size := utf8.DecodeRuneInString(text[end+1:])
+			if isAsciiLetter(r) {
+				listNameStart = end
+				listNameEnd = scanRun(text, end+1+size, maxListSlugLength-1, func(r rune) bool {
+					return r == '-' || isUsernameChar(r)
+				})
+				end = listNameEnd
+			}
+		}
+
+		pos, next = end, end
+		if invalidMentionMatchEnd.MatchString(text[end:]) {
+			continue
+		}
+		dst = append(dst, newMention(text, atSignStart, screennameStart, screennameEnd, listNameStart, listNameEnd))
+	}
+	return dst
+}
+
+// Reports whether r matches [\p{L}\p{M}\p{Nd}] or is one of the special
+// characters allowed within hashtags
+func isHashtagChar(r rune) bool {
+	return isHashtagAlpha(r) || unicode.Is(unicode.Nd, r) || strings.ContainsRune(hashtagSpecialChars, r)
+}
+
+// Reports whether r matches [\p{L}\p{M}]
+func isHashtagAlpha(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
+
+// Reports whether r may precede the # sign of a hashtag
+func isHashtagBoundary(r rune) bool {
+	return r != '&' && !isHashtagChar(r)
+}
+
+func scanHashtags(dst entitiesT, text string, start int, b *budget) entitiesT {
+	var (
+		pos     int // end of the previous match; the next match starts here or later
+		charged int // the text before this offset has been charged to b
+	)
+	for next := start; next < len(text); {
+		i := strings.IndexAny(text[next:], "#＃")
+		if i < 0 {
+			break
+		}
+		hashStart := next + i
+		if b.exceeded(hashStart, hashStart-charged) {
+			break
+		}
+		charged = hashStart
+		_, size := utf8.DecodeRuneInString(text[hashStart:])
+		next = hashStart + size
+
+		if !precededByBoundary(text, pos, hashStart, isHashtagBoundary) {
+			continue
+		}
+
+		// [alnum]*[alpha][alnum]*, i.e. the longest run of hashtag
+		// characters, provided it contains a letter or mark
+		hashtagStart := hashStart + size
+		hashtagEnd := hashtagStart
+		hasAlpha := false
+		for hashtagEnd < len(text) {
+			r, size := utf8.DecodeRuneInString(text[hashtagEnd:])
+			if !isHashtagChar(r) {
+				break
+			}
+			hasAlpha = hasAlpha || isHashtagAlpha(r)
+			hashtagEnd += size
+		}
+		if !hasAlpha {
+			continue
+		}
+
+		pos, next = hashtagEnd, hashtagEnd
+		if invalidHashtagMatchEnd.MatchString(text[hashtagEnd:]) {
+			continue
+		}
+		// A # followed by VS-16 or a combining keycap is the keycap
+		// emoji #️⃣, not the start of a hashtag
+		if r, _ := utf8.DecodeRuneInString(text[hashtagStart:]); r == '\uFE0F' || r == '\u20E3' {
+			continue
+		}
+		dst = append(dst, newHashtag(text, hashStart, hashtagStart, hashtagEnd))
+	}
+	return dst
+}
+
+// Returns the offsets of the cashtag, without its $ sign, in the leftmost
+// match of validCashtag in text, as validCashtag.FindStringSubmatchIndex
+// would, and whether there is one
+func scanCashtag(text string) (int, int, bool) {
+	for i := 0; i < len(text); {
+		j := strings.IndexByte(text[i:], '$')
+		if j < 0 {
+			break
+		}
+		dollar := i + j
+		i = dollar + 1
+
+		// (^|unicodeSpacesSet)
+		if dollar > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(text[:dollar]); !isUnicodeSpace(r) {
+				continue
+			}
+		}
+		if end := cashtagEnd(text, dollar+1); end > 0 {
+			return dollar + 1, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Returns the end of the cashtag that starts at byte offset start, just
+// past a $ sign, or -1 if there is none
+func cashtagEnd(text string, start int) int {
+	// [a-z]{1,6}
+	end := scanRun(text, start, 6, isAsciiLetter)
+	if end == start {
+		return -1
+	}
+	// (?:[\._][a-z]{1,2})?, which is greedy. If the cashtag cannot end
+	// after it, it ends at the . or _, which is punctuation
+	if end < len(text) && (text[end] == '.' || text[end] == '_') {
+		if suffixEnd := scanRun(text, end+1, 2, isAsciiLetter); suffixEnd > end+1 && endsCashtag(text, suffixEnd) {
+			return suffixEnd
+		}
+	}
+	if endsCashtag(text, end) {
+		return end
+	}
+	return -1
+}
+
+// Reports whether a cashtag may end at byte offset i: ($|\s|[punctuationChars])
+func endsCashtag(text string, i int) bool {
+	return i == len(text) || strings.IndexByte("\t\n\f\r !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", text[i]) >= 0
+}
+
+// Reports whether r matches unicodeSpacesSet
+func isUnicodeSpace(r rune) bool {
+	switch {
+	case '\t' <= r && r <= '\r', r == ' ', r == '\u0085', r == '\u00a0', r == '\u1680', r == '\u180e',
+		'\u2000' <= r && r <= '\u200a', r == '\u2028', r == '\u2029', r == '\u202f', r == '\u205f', r == '\u3000':
+		return true
+	}
+	return false
+}
+
+// Reports whether the sign at byte offset i is at the start of the text,
+// or is preceded by a character that satisfies isBoundary and was not
+// consumed by the previous match, which ended at pos
+func precededByBoundary(text string, pos, i int, isBoundary func(rune) bool) bool {
+	if i == 0 {
+		return true
+	}
+	r, size := utf8.DecodeLastRuneInString(text[:i])
+	return i-size >= pos && isBoundary(r)
+}
+
+// Returns the end of the run of at most max characters satisfying f that
+// starts at byte offset start
+func scanRun(text string, start, max int, f func(rune) bool) int {
+	end := start
+	for n := 0; n < max && end < len(text); n++ {
+		r, size := utf8.DecodeRuneInString(text[end:])
+		if !f(r) {
+			break
+		}
+		end += size
+	}
+	return end
+}
@@ -0,0 +1,8 @@
+//go:build !regexpextract
+// +build !regexpextract
+
+package extract
+
+// Mentions and hashtags are found with the hand-written scanners, and URL
+// TLDs with table lookups
+const useRegexp = false
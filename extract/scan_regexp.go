@@ -0,0 +1,8 @@
+//go:build regexpextract
+// +build regexpextract
+
+package extract
+
+// Mentions, hashtags, and URLs are found with the validMentionOrList,
+// validHashtag, and validUrl regexps
+const useRegexp = true
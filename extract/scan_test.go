@@ -0,0 +1,126 @@
+package extract
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Characters that are significant to the mention, hashtag, and cashtag
+// patterns,
+// used to generate random texts for cross-checking
+var scanAlphabet = []string{
+	"@", "＠", "#", "＃", "a", "Z", "ſ", "K", "0", "_", "-", "/", ":", " ", "\t",
+	"R", "T", "t", "!", "&", "*", "$", ".", "é", "́", "日", "١", "‍",
+	"️", "⃣", "・", "\xff", "http://", "RT", "\u3000", "TWTR", "?",
+}
+
+// Returns the texts of all the tests in extract.yml
+func conformanceTexts(t testing.TB) []string {
+	suite, err := conformance.Load("extract.yml")
+	if err != nil {
+		t.Fatalf("Error loading extract.yml: %v", err)
+	}
+
+	var texts []string
+	for _, tests := range suite.Tests {
+		for _, test := range tests {
+			texts = append(texts, test.Text)
+		}
+	}
+	return texts
+}
+
+// Returns the conformance texts, followed by random texts made of
+// characters from scanAlphabet
+func crossCheckTexts(t *testing.T) []string {
+	texts := conformanceTexts(t)
+	texts = append(texts,
+		"RT@user", " rt:@user", "RT @user", "xRT@user", "@@user", "@user@other",
+		"@"+strings.Repeat("a", 25), "@user/"+strings.Repeat("b", 30), "@user/-list",
+		"@ſK", "#ſ", "##tag", "#a#b", "#123", "#١٢٣", "#tag://", "&#tag", "#️⃣",
+	)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		var b strings.Builder
+		for n := rnd.Intn(12); n >= 0; n-- {
+			b.WriteString(scanAlphabet[rnd.Intn(len(scanAlphabet))])
+		}
+		texts = append(texts, b.String())
+	}
+	return texts
+}
+
+func TestScanMentionsOrListsMatchesRegexp(t *testing.T) {
+	for _, text := range crossCheckTexts(t) {
+		expected := regexpMentionsOrLists(nil, text, nil)
+		actual := scanMentionsOrLists(nil, text, 0, nil)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("scanMentionsOrLists returned incorrect value for text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+func TestScanHashtagsMatchesRegexp(t *testing.T) {
+	for _, text := range crossCheckTexts(t) {
+		expected := regexpHashtags(nil, text, nil)
+		actual := scanHashtags(nil, text, 0, nil)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("scanHashtags returned incorrect value for text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+func TestScanCashtagMatchesRegexp(t *testing.T) {
+	texts := append(crossCheckTexts(t),
+		"$TWTR", " $twtr", "\u3000$abc", "x$abc", "$abcdefg", "$abc.de", "$abc.def",
+		"$abc_d!", "$ſK", "$a.b.c", "$a_", "$abc?", "$ $abc", "$1 $abc",
+	)
+	for _, text := range texts {
+		var expected []int
+		if m := validCashtag.FindStringSubmatchIndex(text); m != nil {
+			expected = m[validCashtagGroupCashtag*2 : validCashtagGroupCashtag*2+2]
+		}
+		var actual []int
+		if start, end, ok := scanCashtag(text); ok {
+			actual = []int{start, end}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("scanCashtag returned incorrect value for text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+var benchmarkText = strings.Repeat("RT @user: mentioning @someone/a-list in a tweet about #golang and #日本語 with a URL http://example.com ", 3)
+
+func BenchmarkScanMentionsOrLists(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		scanMentionsOrLists(nil, benchmarkText, 0, nil)
+	}
+}
+
+func BenchmarkRegexpMentionsOrLists(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		regexpMentionsOrLists(nil, benchmarkText, nil)
+	}
+}
+
+func BenchmarkScanHashtags(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		scanHashtags(nil, benchmarkText, 0, nil)
+	}
+}
+
+func BenchmarkRegexpHashtags(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		regexpHashtags(nil, benchmarkText, nil)
+	}
+}
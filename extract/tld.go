@@ -0,0 +1,720 @@
+package extract
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Table-driven equivalents of the validUrl, validAsciiDomain,
+// invalidShortDomain and validSpecialShortDomain regexps. The TLD
+// alternations make up most of those patterns, and matching against them
+// dominates the cost of URL extraction. Instead, the domain's labels are
+// walked by hand and the TLDs that follow each of them are found with a
+// single walk of a trie. Candidate TLDs are tried in the order they appear
+// in the alternations, and the rest of the URL is matched with the much
+// smaller urlTail regexp, so the results are exactly those of the full
+// regexps.
+//
+// Build with the regexpextract tag to use the regexps instead.
+//
+// The TLD lists are kept in tlds_generic.txt and tlds_country.txt, from
+// which gen.go generates tld_tables.go.
+
+//go:generate go run gen.go
+
+// TLD tables built from the lists in tld_tables.go
+var tlds struct {
+	once sync.Once
+
+	// genericTLDs and countryTLDs, and their case folded forms
+	exact  *tldTrie
+	folded *tldTrie
+
+	cc        map[string]bool // countryTLDs
+	specialCC map[string]bool // urlValidSpecialCCTLD
+}
+
+func loadTLDs() {
+	tlds.once.Do(func() {
+		all := strings.Fields(genericTLDs + " " + countryTLDs)
+		tlds.exact = newTLDTrie(all, nil)
+		tlds.folded = newTLDTrie(all, foldRune)
+
+		tlds.cc = map[string]bool{}
+		for _, tld := range strings.Fields(countryTLDs) {
+			tlds.cc[tld] = true
+		}
+		tlds.specialCC = map[string]bool{}
+		for _, tld := range alternatives(urlValidSpecialCCTLD) {
+			tlds.specialCC[tld] = true
+		}
+	})
+}
+
+// Returns the entries of an alternation of literals such as
+// urlValidSpecialCCTLD
+func alternatives(pattern string) []string {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "(?:"), ")")
+	return strings.Split(pattern, "|")
+}
+
+// A trie of TLDs, stored as flat slices. Each TLD is stored with its
+// position in the list the trie was built from, its rank, so that the TLDs
+// matching a text can be tried in the order an alternation would try them
+type tldTrie struct {
+	nodes []tldNode // the root is nodes[0]
+	edges []tldEdge
+	fold  func(rune) rune
+}
+
+type tldNode struct {
+	edges int32 // the offset of the node's edges in tldTrie.edges
+	count int32 // the number of edges, which are sorted by character
+	rank  int32 // the rank of the TLD that ends at the node, or -1
+}
+
+type tldEdge struct {
+	r    rune
+	node int32
+}
+
+// Returns a trie of tlds, ranked by their positions in the list. If a TLD
+// appears more than once, its first position is its rank. If fold is not
+// nil, it is applied to every character of the TLDs and of the texts
+// searched
+func newTLDTrie(tlds []string, fold func(rune) rune) *tldTrie {
+	type buildNode struct {
+		children map[rune]*buildNode
+		rank     int
+	}
+	root := &buildNode{rank: -1}
+	for rank, tld := range tlds {
+		n := root
+		for _, r := range tld {
+			if fold != nil {
+				r = fold(r)
+			}
+			child := n.children[r]
+			if child == nil {
+				if n.children == nil {
+					n.children = map[rune]*buildNode{}
+				}
+				child = &buildNode{rank: -1}
+				n.children[r] = child
+			}
+			n = child
+		}
+		if n.rank < 0 {
+			n.rank = rank
+		}
+	}
+
+	// Number the nodes breadth first, so that each node's edges are
+	// contiguous
+	t := &tldTrie{fold: fold}
+	queue := []*buildNode{root}
+	t.nodes = append(t.nodes, tldNode{rank: -1})
+	for i := 0; i < len(queue); i++ {
+		n := queue[i]
+		runes := make([]rune, 0, len(n.children))
+		for r := range n.children {
+			runes = append(runes, r)
+		}
+		sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+		t.nodes[i] = tldNode{edges: int32(len(t.edges)), count: int32(len(runes)), rank: int32(n.rank)}
+		for _, r := range runes {
+			t.edges = append(t.edges, tldEdge{r: r, node: int32(len(queue))})
+			queue = append(queue, n.children[r])
+			t.nodes = append(t.nodes, tldNode{})
+		}
+	}
+	return t
+}
+
+// Returns the node reached from node n by character r, or -1 if there is
+// none
+func (t *tldTrie) next(n int32, r rune) int32 {
+	node := t.nodes[n]
+	edges := t.edges[node.edges : node.edges+node.count]
+	lo, hi := 0, len(edges)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if edges[mid].r < r {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(edges) && edges[lo].r == r {
+		return edges[lo].node
+	}
+	return -1
+}
+
+// Appends to result the end offsets of the TLDs that match text at byte
+// offset start, in rank order, and returns it
+func (t *tldTrie) matches(text string, start int, result []int) []int {
+	var (
+		rankBuf [8]int32
+		ranks   = rankBuf[:0]
+		base    = len(result)
+	)
+	for i, n := start, int32(0); i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if t.fold != nil {
+			r = t.fold(r)
+		}
+		if n = t.next(n, r); n < 0 {
+			break
+		}
+		i += size
+
+		rank := t.nodes[n].rank
+		if rank < 0 {
+			continue
+		}
+		// Insert in rank order
+		j := len(ranks)
+		result, ranks = append(result, 0), append(ranks, 0)
+		for ; j > 0 && ranks[j-1] > rank; j-- {
+			ranks[j], result[base+j] = ranks[j-1], result[base+j-1]
+		}
+		ranks[j], result[base+j] = rank, i
+	}
+	return result
+}
+
+// The number of submatch indices in a match of validUrl
+const urlMatchLength = 2 * (validUrlGroupQueryString + 1)
+
+// Returns the submatch indices of the leftmost match of validUrl in text.
+// The indices are stored in match, which must have length urlMatchLength,
+// if it is used
+func matchUrl(text string, match []int) []int {
+	if useRegexp {
+		return validUrl.FindStringSubmatchIndex(text)
+	}
+	return findUrl(text, match)
+}
+
+// Returns the indices of the leftmost match of validAsciiDomain in domain,
+// and whether there is one
+func matchAsciiDomain(domain string) (start, end int, ok bool) {
+	if useRegexp {
+		if m := validAsciiDomain.FindStringIndex(domain); m != nil {
+			return m[0], m[1], true
+		}
+		return 0, 0, false
+	}
+	return findAsciiDomain(domain)
+}
+
+// Reports whether domain matches invalidShortDomain, i.e. whether it is a
+// single label followed by a ccTLD
+func isInvalidShortDomain(domain string) bool {
+	if useRegexp {
+		return invalidShortDomain.MatchString(domain)
+	}
+	loadTLDs()
+	return isShortDomain(domain, tlds.cc)
+}
+
+// Reports whether domain matches validSpecialShortDomain
+func isValidSpecialShortDomain(domain string) bool {
+	if useRegexp {
+		return validSpecialShortDomain.MatchString(domain)
+	}
+	loadTLDs()
+	return isShortDomain(domain, tlds.specialCC)
+}
+
+// Returns the submatch indices of the leftmost match of validUrl in text,
+// as validUrl.FindStringSubmatchIndex would, storing them in match
+func findUrl(text string, match []int) []int {
+	loadTLDs()
+
+	nextDot := strings.IndexByte(text, '.')
+	for pos := 0; pos < len(text); {
+		// Every URL has a dot in its domain
+		if nextDot < pos {
+			i := strings.IndexByte(text[pos:], '.')
+			if i < 0 {
+				return nil
+			}
+			nextDot = pos + i
+		}
+
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		hasPreceding := isUrlPrecedingChar(r)
+		if pos == 0 || hasPreceding {
+			if m := matchUrlAt(text, pos, hasPreceding, match); m != nil {
+				return m
+			}
+		}
+		pos += size
+	}
+	return nil
+}
+
+// Returns the submatch indices of the preferred match of validUrl starting
+// at byte offset pos, or nil if there is none. hasPreceding reports whether
+// the character at pos may precede a URL
+func matchUrlAt(text string, pos int, hasPreceding bool, match []int) []int {
+	// ([^[:alnum:]...]|^): a preceding character is tried before ^
+	var urlStarts [2]int
+	n := 0
+	if hasPreceding {
+		_, size := utf8.DecodeRuneInString(text[pos:])
+		urlStarts[n] = pos + size
+		n++
+	}
+	if pos == 0 {
+		urlStarts[n] = pos
+		n++
+	}
+
+	for _, urlStart := range urlStarts[:n] {
+		// (https?://)?: a protocol is tried before none
+		if size := protocolLength(text[urlStart:]); size > 0 {
+			if m := matchDomainAt(text, pos, urlStart, size, match); m != nil {
+				return m
+			}
+		}
+		if m := matchDomainAt(text, pos, urlStart, 0, match); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// Returns the length of the (?i)https?:// protocol at the start of text,
+// or 0 if there is none
+func protocolLength(text string) int {
+	i := 0
+	for _, c := range []byte("http") {
+		if i >= len(text) || text[i]|0x20 != c {
+			return 0
+		}
+		i++
+	}
+	if i < len(text) && text[i]|0x20 == 's' {
+		i++
+	} else if strings.HasPrefix(text[i:], "ſ") {
+		i += len("ſ")
+	}
+	if !strings.HasPrefix(text[i:], "://") {
+		return 0
+	}
+	return i + len("://")
+}
+
+// Returns the length of the match of validTcoUrl at the start of url, or 0
+// if there is none
+func tcoUrlLength(url string) int {
+	if useRegexp {
+		if m := validTcoUrl.FindStringIndex(url); m != nil {
+			return m[1]
+		}
+		return 0
+	}
+
+	i := protocolLength(url)
+	if i == 0 {
+		return 0
+	}
+	n := foldedPrefixLength(url[i:], "t.co/")
+	if n == 0 {
+		return 0
+	}
+	// [a-z0-9]+
+	end := scanRun(url, i+n, len(url), func(r rune) bool {
+		return r != '_' && isUsernameChar(r)
+	})
+	if end == i+n {
+		return 0
+	}
+	return end
+}
+
+// A label of a domain, ending at a dot
+type domainLabel struct {
+	end        int  // just past the dot
+	isNameable bool // whether it matches urlValidDomainName, i.e. has no underscores
+}
+
+// Returns the submatch indices of the preferred match of validUrl whose
+// preceding character starts at pos, whose URL starts at urlStart, and
+// whose protocol is protocolLength bytes long
+func matchDomainAt(text string, pos, urlStart, protocolLength int, match []int) []int {
+	domainStart := urlStart + protocolLength
+
+	// urlValidSubDomain*: collect every label that matches
+	// urlValidSubDomain, i.e. every run of valid characters, underscores
+	// and hyphens that starts and ends with a valid character and is
+	// followed by a dot
+	var buf [8]domainLabel
+	labels := buf[:0]
+	for i := domainStart; i < len(text); {
+		labelStart := i
+		lastIsValid, isNameable := false, true
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if isUrlChar(r) {
+				lastIsValid = true
+			} else if r == '_' || r == '-' {
+				if i == labelStart {
+					break
+				}
+				lastIsValid = false
+				isNameable = isNameable && r == '-'
+			} else {
+				break
+			}
+			i += size
+		}
+		if i == labelStart || !lastIsValid || i >= len(text) || text[i] != '.' {
+			break
+		}
+		i++
+		labels = append(labels, domainLabel{end: i, isNameable: isNameable})
+	}
+
+	// Subdomains are greedy, so the domain name is tried from the last
+	// label back to the first
+	for k := len(labels) - 1; k >= 0; k-- {
+		if !labels[k].isNameable {
+			continue
+		}
+		tldStart := labels[k].end
+
+		var buf [8]int
+		for _, tldEnd := range tlds.folded.matches(text, tldStart, buf[:0]) {
+			if m := matchTailAt(text, pos, urlStart, protocolLength, tldEnd, match); m != nil {
+				return m
+			}
+		}
+
+		// xn--[0-9a-z]+ is the last alternative, and is greedy
+		if prefixLength := foldedPrefixLength(text[tldStart:], "xn--"); prefixLength > 0 {
+			var ends []int
+			for i := tldStart + prefixLength; i < len(text); {
+				r, size := utf8.DecodeRuneInString(text[i:])
+				if r == '_' || !isUsernameChar(r) {
+					break
+				}
+				i += size
+				ends = append(ends, i)
+			}
+			for j := len(ends) - 1; j >= 0; j-- {
+				if m := matchTailAt(text, pos, urlStart, protocolLength, ends[j], match); m != nil {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Stores the submatch indices of validUrl for a URL whose TLD ends at
+// tldEnd in match, and returns match, or nil if the rest of the text does
+// not match urlTail
+func matchTailAt(text string, pos, urlStart, protocolLength, tldEnd int, match []int) []int {
+	var buf [urlTailMatchLength]int
+	tail, ok := scanUrlTail(text[tldEnd:], buf[:])
+	if !ok {
+		tail = urlTail.FindStringSubmatchIndex(text[tldEnd:])
+	}
+	if tail == nil {
+		return nil
+	}
+
+	match[0], match[1] = pos, tldEnd+tail[1]
+	match[2*validUrlGroupAll], match[2*validUrlGroupAll+1] = match[0], match[1]
+	match[2*validUrlGroupBefore], match[2*validUrlGroupBefore+1] = pos, urlStart
+	match[2*validUrlGroupUrl], match[2*validUrlGroupUrl+1] = urlStart, tldEnd+tail[3]
+	match[2*validUrlGroupProtocol], match[2*validUrlGroupProtocol+1] = -1, -1
+	if protocolLength > 0 {
+		match[2*validUrlGroupProtocol], match[2*validUrlGroupProtocol+1] = urlStart, urlStart+protocolLength
+	}
+	match[2*validUrlGroupDomain], match[2*validUrlGroupDomain+1] = urlStart+protocolLength, tldEnd
+
+	// urlTail's port, path, and query string groups
+	for group := validUrlGroupPort; group <= validUrlGroupQueryString; group++ {
+		i := 2 * (group - validUrlGroupPort + 2)
+		match[2*group], match[2*group+1] = -1, -1
+		if tail[i] >= 0 {
+			match[2*group], match[2*group+1] = tldEnd+tail[i], tldEnd+tail[i+1]
+		}
+	}
+	return match
+}
+
+// The number of submatch indices in a match of urlTail
+const urlTailMatchLength = 2 * (validUrlGroupQueryString - validUrlGroupPort + 3)
+
+// Returns the submatch indices of the match of urlTail at the start of s,
+// as urlTail.FindStringSubmatchIndex would, storing them in tail, which
+// must have length urlTailMatchLength. Most URLs end at their TLD or have
+// a simple ASCII path or query string, which are matched by hand. Returns
+// false if s is not one of those, and the regexp must be used, e.g. if it
+// has a port number, balanced parentheses, or non-ASCII characters that
+// the regexp may backtrack over
+func scanUrlTail(s string, tail []int) ([]int, bool) {
+	for i := range tail {
+		tail[i] = -1
+	}
+	i := 0
+	if strings.HasPrefix(s, ":") {
+		return nil, false
+	}
+
+	// (/urlValidPath*)?: a path of urlValidGeneralPathChars ends with
+	// the last urlValidPathEndingChars character, or just after the /
+	if strings.HasPrefix(s, "/") {
+		end, j := 1, 1
+		for ; j < len(s) && isUrlPathChar(s[j]); j++ {
+			if s[j] == '@' {
+				return nil, false
+			}
+			if isUrlPathEndingChar(s[j]) {
+				end = j + 1
+			}
+		}
+		if j < len(s) && (s[j] == '(' || !isSimpleUrlEnd(s[j:])) {
+			return nil, false
+		}
+		tail[6], tail[7] = 0, end
+		i = end
+	}
+
+	// (\?urlValidUrlQueryChars*urlValidUrlQueryEndingChars)?
+	if strings.HasPrefix(s[i:], "?") {
+		end, j := -1, i+1
+		for ; j < len(s) && isUrlQueryChar(s[j]); j++ {
+			if s[j] == '@' {
+				return nil, false
+			}
+			if isUrlQueryEndingChar(s[j]) {
+				end = j + 1
+			}
+		}
+		if j < len(s) && !isSimpleUrlEnd(s[j:]) {
+			return nil, false
+		}
+		if end > 0 {
+			tail[8], tail[9] = i, end
+			i = end
+		}
+	}
+
+	// urlValidEnd
+	tail[2], tail[3] = 0, i
+	tail[0], tail[1] = 0, i
+	if i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isAsciiAlnum(r) || r == '@' || r == '\u017f' || r == '\u212a' {
+			if i > 0 {
+				return nil, false
+			}
+			return nil, true
+		}
+		tail[1] += size
+	}
+	return tail, true
+}
+
+// Reports whether the character at the start of s, which follows a path
+// or query string of ASCII characters, ends it without the regexp having
+// to backtrack: it is ASCII, or not one of latinAccentChars or the
+// characters that (?i) folds to ASCII letters
+func isSimpleUrlEnd(s string) bool {
+	if s[0] < utf8.RuneSelf {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r != '\u212a' && !isAsciiLabelChar(r)
+}
+
+// Reports whether c is an ASCII character of urlValidGeneralPathChars
+func isUrlPathChar(c byte) bool {
+	return isAsciiAlnum(rune(c)) || strings.IndexByte("!*';:=+,.$/%#[]-_~|&@", c) >= 0
+}
+
+// Reports whether c is an ASCII character of urlValidPathEndingChars
+func isUrlPathEndingChar(c byte) bool {
+	return isAsciiAlnum(rune(c)) || strings.IndexByte("=_#/-+", c) >= 0
+}
+
+// Reports whether c is an ASCII character of urlValidUrlQueryChars
+func isUrlQueryChar(c byte) bool {
+	return isAsciiAlnum(rune(c)) || strings.IndexByte("!?*'();:&=+$/%#[]-_.,~|@", c) >= 0
+}
+
+// Reports whether c is an ASCII character of urlValidUrlQueryEndingChars
+func isUrlQueryEndingChar(c byte) bool {
+	return isAsciiAlnum(rune(c)) || strings.IndexByte("_&=#/", c) >= 0
+}
+
+// Returns the indices of the leftmost match of validAsciiDomain in domain,
+// as validAsciiDomain.FindStringIndex would, and whether there is one
+func findAsciiDomain(domain string) (int, int, bool) {
+	loadTLDs()
+
+	for i := 0; i < len(domain); {
+		// The first label starts with the first alphanumeric character of
+		// a run of label characters
+		r, size := utf8.DecodeRuneInString(domain[i:])
+		if !isAsciiAlnum(r) {
+			i += size
+			continue
+		}
+		start := i
+
+		// (?:[[:alnum:]][[:alnum:]_\-...]*\.)+: every label is greedy
+		var buf [8]int
+		labelEnds := buf[:0]
+		for j := start; j < len(domain) && isAsciiAlnum(rune(domain[j])); {
+			j = scanRun(domain, j, len(domain), isAsciiLabelChar)
+			if j >= len(domain) || domain[j] != '.' {
+				break
+			}
+			j++
+			labelEnds = append(labelEnds, j)
+		}
+
+		for k := len(labelEnds) - 1; k >= 0; k-- {
+			if end := tldMatch(domain, labelEnds[k]); end > 0 {
+				return start, end, true
+			}
+		}
+
+		// Starting anywhere else in the first label leads to the same
+		// labels, so continue after it
+		i = scanRun(domain, start, len(domain), isAsciiLabelChar)
+		if i < len(domain) && domain[i] == '.' {
+			i++
+		}
+	}
+	return 0, 0, false
+}
+
+// Returns the end offset of the first TLD alternative that matches text at
+// byte offset start, or 0 if none does
+func tldMatch(text string, start int) int {
+	var buf [8]int
+	if ends := tlds.exact.matches(text, start, buf[:0]); len(ends) > 0 {
+		return ends[0]
+	}
+
+	// xn--[0-9a-z]+
+	if strings.HasPrefix(text[start:], "xn--") {
+		i := start + len("xn--")
+		for i < len(text) && (('0' <= text[i] && text[i] <= '9') || ('a' <= text[i] && text[i] <= 'z')) {
+			i++
+		}
+		if i > start+len("xn--") {
+			return i
+		}
+	}
+	return 0
+}
+
+// Reports whether domain matches \A urlValidDomainName (tlds) \z
+func isShortDomain(domain string, set map[string]bool) bool {
+	i := strings.IndexByte(domain, '.')
+	if i <= 0 || !set[domain[i+1:]] {
+		return false
+	}
+	label := domain[:i]
+	first, _ := utf8.DecodeRuneInString(label)
+	last, _ := utf8.DecodeLastRuneInString(label)
+	if !isUrlChar(first) || !isUrlChar(last) {
+		return false
+	}
+	for _, r := range label {
+		if r != '-' && !isUrlChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether r matches urlValidPrecedingChars, i.e. whether r may
+// precede a URL
+func isUrlPrecedingChar(r rune) bool {
+	if isAsciiLetter(r) || ('0' <= r && r <= '9') || ('\u202a' <= r && r <= '\u202e') {
+		return false
+	}
+	return !strings.ContainsRune("@\uff20$#\uff03", r)
+}
+
+// Reports whether r matches urlValidChars
+func isUrlChar(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isAsciiAlnum(r) || r == '\\'
+	}
+	switch {
+	case isUnicodeSpace(r):
+		return false // unicodeSpaces
+	case r == '\ufffe', r == '\ufeff', r == '\uffff', '\u202a' <= r && r <= '\u202e':
+		return false // invalidChars
+	}
+	return true
+}
+
+// Reports whether r matches [[:alnum:]]
+func isAsciiAlnum(r rune) bool {
+	return ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+// Reports whether r matches [[:alnum:]_\-] or latinAccentChars
+func isAsciiLabelChar(r rune) bool {
+	if r < utf8.RuneSelf {
+		return isAsciiAlnum(r) || r == '_' || r == '-'
+	}
+	switch {
+	case '\u00c0' <= r && r <= '\u024f':
+		return r != '\u00d7' && r != '\u00f7'
+	case '\u0300' <= r && r <= '\u036f', '\u1e00' <= r && r <= '\u1eff':
+		return true
+	}
+	return strings.ContainsRune("\u0253\u0254\u0256\u0257\u0259\u025b\u0263\u0268\u026f\u0272\u0289\u028b\u02bb", r)
+}
+
+// Returns the smallest character that r is equivalent to under simple case
+// folding, which is how (?i) compares characters
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		// An ASCII letter's upper case form is smaller than its other
+		// forms, including the Kelvin sign and long s
+		if 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return r
+	}
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// Returns the length of the prefix of text that equals prefix, ignoring
+// case, or 0 if text does not start with prefix
+func foldedPrefixLength(text, prefix string) int {
+	i := 0
+	for _, p := range prefix {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if size == 0 || foldRune(r) != foldRune(p) {
+			return 0
+		}
+		i += size
+	}
+	return i
+}
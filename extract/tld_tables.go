@@ -0,0 +1,152 @@
+// Code generated by gen.go from tlds_generic.txt and tlds_country.txt; DO NOT EDIT.
+
+//go:build !minimaltables
+// +build !minimaltables
+
+package extract
+
+const (
+	// The generic TLDs, separated by spaces, in the order they are tried
+	genericTLDs = "abb abbott abogado academy accenture accountant accountants aco active actor ads adult aeg aero afl " +
+		"agency aig airforce airtel allfinanz alsace amsterdam android apartments app aquarelle archi army " +
+		"arpa asia associates attorney auction audio auto autos axa azure band bank bar barcelona " +
+		"barclaycard barclays bargains bauhaus bayern bbc bbva bcn beer bentley berlin best bet bharti bible " +
+		"bid bike bing bingo bio biz black blackfriday bloomberg blue bmw bnl bnpparibas boats bond boo " +
+		"boots boutique bradesco bridgestone broker brother brussels budapest build builders business buzz " +
+		"bzh cab cafe cal camera camp cancerresearch canon capetown capital caravan cards care career " +
+		"careers cars cartier casa cash casino cat catering cba cbn ceb center ceo cern cfa cfd chanel " +
+		"channel chat cheap chloe christmas chrome church cisco citic city claims cleaning click clinic " +
+		"clothing cloud club coach codes coffee college cologne com commbank community company computer " +
+		"condos construction consulting contractors cooking cool coop corsica country coupons courses credit " +
+		"creditcard cricket crown crs cruises cuisinella cymru cyou dabur dad dance date dating datsun day " +
+		"dclk deals degree delivery delta democrat dental dentist desi design dev diamonds diet digital " +
+		"direct directory discount dnp docs dog doha domains doosan download drive durban dvag earth eat edu " +
+		"education email emerck energy engineer engineering enterprises epson equipment erni esq estate " +
+		"eurovision eus events everbank exchange expert exposed express fage fail faith family fan fans farm " +
+		"fashion feedback film finance financial firmdale fish fishing fit fitness flights florist flowers " +
+		"flsmidth fly foo football forex forsale forum foundation frl frogans fund furniture futbol fyi gal " +
+		"gallery game garden gbiz gdn gent genting ggee gift gifts gives giving glass gle global globo gmail " +
+		"gmo gmx gold goldpoint golf goo goog google gop gov graphics gratis green gripe group guge guide " +
+		"guitars guru hamburg hangout haus healthcare help here hermes hiphop hitachi hiv hockey holdings " +
+		"holiday homedepot homes honda horse host hosting hoteles hotmail house how hsbc ibm icbc ice icu " +
+		"ifm iinet immo immobilien industries infiniti info ing ink institute insure int international " +
+		"investments ipiranga irish ist istanbul itau iwc java jcb jetzt jewelry jlc jll jobs joburg jprs " +
+		"juegos kaufen kddi kim kitchen kiwi koeln komatsu krd kred kyoto lacaixa lancaster land lasalle lat " +
+		"latrobe law lawyer lds lease leclerc legal lexus lgbt liaison lidl life lighting limited limo link " +
+		"live lixil loan loans lol london lotte lotto love ltda lupin luxe luxury madrid maif maison man " +
+		"management mango market marketing markets marriott mba media meet melbourne meme memorial men menu " +
+		"miami microsoft mil mini mma mobi moda moe mom monash money montblanc mormon mortgage moscow " +
+		"motorcycles mov movie movistar mtn mtpc museum nadex nagoya name navy nec net netbank network " +
+		"neustar new news nexus ngo nhk nico ninja nissan nokia nra nrw ntt nyc office okinawa omega one ong " +
+		"onl online ooo oracle orange org organic osaka otsuka ovh page panerai paris partners parts party " +
+		"pet pharmacy philips photo photography photos physio piaget pics pictet pictures pink pizza place " +
+		"play plumbing plus pohl poker porn post praxi press pro prod productions prof properties property " +
+		"pub qpon quebec racing realtor realty recipes red redstone rehab reise reisen reit ren rent rentals " +
+		"repair report republican rest restaurant review reviews rich ricoh rio rip rocks rodeo rsvp ruhr " +
+		"run ryukyu saarland sakura sale samsung sandvik sandvikcoromant sanofi sap sarl saxo sca scb " +
+		"schmidt scholarships school schule schwarz science scor scot seat seek sener services sew sex sexy " +
+		"shiksha shoes show shriram singles site ski sky skype sncf soccer social software sohu solar " +
+		"solutions sony soy space spiegel spreadbetting srl starhub statoil studio study style sucks " +
+		"supplies supply support surf surgery suzuki swatch swiss sydney systems taipei tatamotors tatar " +
+		"tattoo tax taxi team tech technology tel telefonica temasek tennis thd theater tickets tienda tips " +
+		"tires tirol today tokyo tools top toray toshiba tours town toyota toys trade trading training " +
+		"travel trust tui ubs university uno uol vacations vegas ventures vermögensberater " +
+		"vermögensberatung versicherung vet viajes video villas vin vision vista vistaprint vlaanderen " +
+		"vodka vote voting voto voyage wales walter wang watch webcam website wed wedding weir whoswho wien " +
+		"wiki williamhill win windows wine wme work works world wtc wtf xbox xerox xin xperia xxx xyz yachts " +
+		"yandex yodobashi yoga yokohama youtube zip zone zuerich дети ком москва онлайн " +
+		"орг рус сайт קום بازار شبكة كوم موقع कॉम नेट " +
+		"संगठन คอม みんな グーグル コム 世界 中信 中文网 企业 佛山 " +
+		"信息 健康 八卦 公司 公益 商城 商店 商标 在线 大拿 娱乐 工行 广东 慈善 " +
+		"我爱你 手机 政务 政府 新闻 时尚 机构 淡马锡 游戏 点看 移动 组织机构 " +
+		"网址 网店 网络 谷歌 集团 飞利浦 餐厅 닷넷 닷컴 삼성 onion"
+
+	// The country code TLDs, separated by spaces, in the order they are tried
+	countryTLDs = "ac ad ae af ag ai al am an ao aq ar as at au aw ax az ba bb bd be bf bg bh bi bj bl bm bn bo bq br " +
+		"bs bt bv bw by bz ca cc cd cf cg ch ci ck cl cm cn co cr cu cv cw cx cy cz de dj dk dm do dz ec ee " +
+		"eg eh er es et eu fi fj fk fm fo fr ga gb gd ge gf gg gh gi gl gm gn gp gq gr gs gt gu gw gy hk hm " +
+		"hn hr ht hu id ie il im in io iq ir is it je jm jo jp ke kg kh ki km kn kp kr kw ky kz la lb lc li " +
+		"lk lr ls lt lu lv ly ma mc md me mf mg mh mk ml mm mn mo mp mq mr ms mt mu mv mw mx my mz na nc ne " +
+		"nf ng ni nl no np nr nu nz om pa pe pf pg ph pk pl pm pn pr ps pt pw py qa re ro rs ru rw sa sb sc " +
+		"sd se sg sh si sj sk sl sm sn so sr ss st su sv sx sy sz tc td tf tg th tj tk tl tm tn to tp tr tt " +
+		"tv tw tz ua ug uk um us uy uz va vc ve vg vi vn vu wf ws ye yt za zm zw ελ бел мкд мон " +
+		"рф срб укр қаз հայ الاردن الجزائر السعودية المغرب " +
+		"امارات ایران بھارت تونس سودان سورية عراق عمان فلسطين " +
+		"قطر مصر مليسيا پاکستان भारत বাংলা ভারত ਭਾਰਤ " +
+		"ભારત இந்தியா இலங்கை சிங்கப்பூர் " +
+		"భారత్ ලංකා ไทย გე 中国 中國 台湾 台灣 新加坡 澳門 香港 " +
+		"한국"
+
+	// Matches any of the generic and country code TLDs
+	urlValidTLD = "(?:a(?:[ow]|b(?:b(?:ott)??|ogado)|c(?:o|ademy|c(?:enture|ountants??)|t(?:ive|or))?|d(?:s|ult)?|" +
+		"e(?:g|ro)?|fl?|g(?:ency)?|i(?:g|r(?:force|tel))?|l(?:lfinanz|sace)?|m(?:sterdam)?|n(?:droid)?|p(?:p|" +
+		"artments)|q(?:uarelle)?|r(?:chi|my|pa)?|s(?:ia|sociates)?|t(?:torney)?|u(?:ction|dio|tos??)?|xa?|" +
+		"z(?:ure)?)|b(?:[dfgjqstvwy]|a(?:n[dk]|r(?:c(?:elona|lay(?:s|card))|gains)??|uhaus|yern)?|b(?:c|va)?|" +
+		"cn|e(?:t|er|ntley|rlin|st)?|h(?:arti)?|i(?:[doz]|ble|ke|ngo??)?|l(?:ack(?:friday)??|oomberg|ue)?|" +
+		"mw?|n(?:l|pparibas)?|o(?:ats|nd|o(?:ts)??|utique)?|r(?:adesco|idgestone|o(?:ker|ther)|ussels)?|" +
+		"u(?:dapest|ild(?:ers)??|siness|zz)|zh?)|c(?:[cdgkmnv-xz]|a(?:[bl]|fe|m(?:p|era)|n(?:cerresearch|on)|" +
+		"p(?:etown|ital)|r(?:s|avan|ds|e(?:ers??)??|tier)|s(?:[ah]|ino)|t(?:ering)??)?|b[an]|e(?:[bo]|nter|" +
+		"rn)|f[ad]?|h(?:a(?:t|n(?:el|nel))|eap|loe|r(?:istmas|ome)|urch)?|i(?:sco|t(?:y|ic))?|l(?:aims|" +
+		"eaning|i(?:ck|nic)|o(?:thing|ud)|ub)?|o(?:ach|des|ffee|l(?:lege|ogne)|m(?:m(?:bank|unity)|p(?:any|" +
+		"uter))??|n(?:dos|s(?:truction|ulting)|tractors)|o(?:[lp]|king)|rsica|u(?:ntry|pons|rses))?|r(?:s|" +
+		"edit(?:card)??|icket|own|uises)?|u(?:isinella)?|y(?:mru|ou)?)|d(?:[jkmz]|a(?:[dy]|bur|nce|t(?:e|ing|" +
+		"sun))|clk|e(?:v|als|gree|l(?:ivery|ta)|mocrat|nt(?:al|ist)|si(?:gn)??)?|i(?:amonds|et|gital|" +
+		"rect(?:ory)??|scount)|np|o(?:g|cs|ha|mains|osan|wnload)?|rive|urban|vag)|e(?:[ceght]|a(?:t|rth)|" +
+		"du(?:cation)??|m(?:ail|erck)|n(?:ergy|gineer(?:ing)??|terprises)|pson|quipment|r(?:ni)?|s(?:q|" +
+		"tate)?|u(?:s|rovision)?|ve(?:nts|rbank)|x(?:change|p(?:ert|osed|ress)))|f(?:[jkm]|a(?:ge|i(?:l|th)|" +
+		"mily|ns??|rm|shion)|eedback|i(?:lm|nanc(?:e|ial)|rmdale|sh(?:ing)??|t(?:ness)??)?|l(?:y|ights|" +
+		"o(?:rist|wers)|smidth)|o(?:o(?:tball)??|r(?:ex|sale|um)|undation)?|r(?:l|ogans)?|u(?:nd|rniture|" +
+		"tbol)|yi)|g(?:[fhnpqstwy]|a(?:l(?:lery)??|me|rden)?|b(?:iz)?|dn?|e(?:nt(?:ing)??)?|g(?:ee)?|" +
+		"i(?:fts??|v(?:es|ing))?|l(?:e|ass|ob(?:o|al))?|m(?:[ox]|ail)?|o(?:[pv]|l(?:f|d(?:point)??)|" +
+		"o(?:g(?:le)??)??)|r(?:a(?:phics|tis)|een|ipe|oup)?|u(?:ge|i(?:de|tars)|ru)?)|h(?:[kmnrtu]|a(?:mburg|" +
+		"ngout|us)|e(?:althcare|lp|r(?:e|mes))|i(?:v|phop|tachi)|o(?:w|ckey|l(?:dings|iday)|me(?:s|depot)|" +
+		"nda|rse|st(?:ing)??|t(?:eles|mail)|use)|sbc)|i(?:[deloq]|bm|c(?:[eu]|bc)|fm|inet|" +
+		"m(?:mo(?:bilien)??)?|n(?:[gk]|dustries|f(?:o|initi)|s(?:titute|ure)|t(?:ernational)??|vestments)?|" +
+		"piranga|r(?:ish)?|s(?:t(?:anbul)??)?|t(?:au)?|wc)|j(?:m|ava|cb|e(?:tzt|welry)?|l[cl]|o(?:b(?:s|" +
+		"urg))?|p(?:rs)?|uegos)|k(?:[eghmnpwz]|aufen|ddi|i(?:m|tchen|wi)?|o(?:eln|matsu)|r(?:d|ed)?|" +
+		"y(?:oto)?)|l(?:[bckrsvy]|a(?:caixa|n(?:d|caster)|salle|t(?:robe)??|w(?:yer)??)?|ds|e(?:ase|clerc|" +
+		"gal|xus)|gbt|i(?:aison|dl|fe|ghting|m(?:o|ited)|nk|ve|xil)?|o(?:l|ans??|ndon|tt[eo]|ve)|t(?:da)?|" +
+		"u(?:pin|x(?:e|ury))?)|m(?:[cdf-hklnp-sv-z]|a(?:drid|i(?:f|son)|n(?:agement|go)??|r(?:ket(?:s|ing)??|" +
+		"riott))?|ba|e(?:dia|et|lbourne|m(?:e|orial)|nu??)?|i(?:l|ami|crosoft|ni)|ma?|o(?:[em]|bi|da|n(?:ash|" +
+		"ey|tblanc)|r(?:mon|tgage)|scow|torcycles|v(?:i(?:e|star))??)?|t(?:n|pc)?|u(?:seum)?)|n(?:[cflpuz]|" +
+		"a(?:dex|goya|me|vy)?|e(?:c|t(?:bank|work)??|ustar|ws??|xus)?|go?|hk|i(?:co|nja|ssan)?|o(?:kia)?|" +
+		"r[aw]?|tt|yc)|o(?:ffice|kinawa|m(?:ega)?|n(?:[eg]|l(?:ine)??|ion)|oo|r(?:a(?:cle|nge)|g(?:anic)??)|" +
+		"saka|tsuka|vh)|p(?:[fgkmnstwy]|a(?:ge|nerai|r(?:is|t(?:[sy]|ners)))?|et?|h(?:armacy|ilips|oto(?:s|" +
+		"graphy)??|ysio)?|i(?:aget|c(?:s|t(?:et|ures))|nk|zza)|l(?:a(?:y|ce)|u(?:s|mbing))?|o(?:hl|ker|rn|" +
+		"st)|r(?:axi|ess|o(?:f|d(?:uctions)??|pert(?:y|ies))??)?|ub)|q(?:a|pon|uebec)|r(?:w|acing|" +
+		"e(?:alt(?:y|or)|cipes|d(?:stone)??|hab|i(?:t|sen??)|n(?:t(?:als)??)??|p(?:air|ort|ublican)|" +
+		"st(?:aurant)??|views??)?|i(?:[op]|c(?:h|oh))|o(?:cks|deo)?|s(?:vp)?|u(?:n|hr)?|yukyu)|" +
+		"s(?:[bdgjlmsvxz]|a(?:p|arland|kura|le|msung|n(?:dvik(?:coromant)??|ofi)|rl|xo)?|c(?:[ab]|h(?:midt|" +
+		"o(?:larships|ol)|ule|warz)|ience|o[rt])?|e(?:w|at|ek|ner|rvices|xy??)?|h(?:iksha|o(?:w|es)|riram)?|" +
+		"i(?:ngles|te)?|k(?:i|y(?:pe)??)?|n(?:cf)?|o(?:y|c(?:cer|ial)|ftware|hu|l(?:ar|utions)|ny)?|p(?:ace|" +
+		"iegel|readbetting)|rl?|t(?:a(?:rhub|toil)|ud(?:y|io)|yle)?|u(?:cks|pp(?:l(?:y|ies)|ort)|r(?:f|gery)|" +
+		"zuki)?|w(?:atch|iss)|y(?:dney|stems)?)|t(?:[cdfgj-nptvwz]|a(?:ipei|t(?:a(?:r|motors)|too)|xi??)|" +
+		"e(?:am|ch(?:nology)??|l(?:efonica)??|masek|nnis)|h(?:d|eater)?|i(?:ckets|enda|ps|r(?:es|ol))|o(?:p|" +
+		"day|kyo|ols|ray|shiba|urs|wn|y(?:s|ota))?|r(?:a(?:d(?:e|ing)|ining|vel)|ust)?|ui)|u(?:[agkmsyz]|bs|" +
+		"n(?:o|iversity)|ol)|v(?:[cgnu]|a(?:cations)?|e(?:t|gas|ntures|r(?:mögensberat(?:er|ung)|" +
+		"sicherung))?|i(?:n|ajes|deo|llas|s(?:ion|ta(?:print)??))?|laanderen|o(?:dka|t(?:[eo]|ing)|yage))|" +
+		"w(?:[fs]|a(?:l(?:es|ter)|ng|tch)|e(?:b(?:cam|site)|d(?:ding)??|ir)|hoswho|i(?:en|ki|lliamhill|n(?:e|" +
+		"dows)??)|me|or(?:ks??|ld)|t[cf])|x(?:box|erox|in|peria|xx|yz)|y(?:[et]|a(?:chts|ndex)|o(?:dobashi|" +
+		"ga|kohama|utube))|z(?:[amw]|ip|one|uerich)|дети|ком|м(?:о(?:н|сква)|кд)|" +
+		"о(?:нлайн|рг)|р(?:ф|ус)|с(?:айт|рб)|קום|ب(?:ازار|ھارت)|شبكة|" +
+		"كوم|م(?:وقع|صر|ليسيا)|कॉम|नेट|संगठन|คอม|みんな|" +
+		"グーグル|コム|世界|中(?:[信国國]|文网)|企业|佛山|信息|健康|八卦|公[司益]|" +
+		"商[城店标]|在线|大拿|娱乐|工行|广东|慈善|我爱你|手机|政[务府]|新(?:闻|" +
+		"加坡)|时尚|机构|淡马锡|游戏|点看|移动|组织机构|网[址店络]|谷歌|集团|" +
+		"飞利浦|餐厅|닷[넷컴]|삼성|ελ|бел|укр|қаз|հայ|ا(?:ل(?:اردن|جزائر|" +
+		"سعودية|مغرب)|مارات|یران)|تونس|سو(?:دان|رية)|ع(?:راق|مان)|" +
+		"فلسطين|قطر|پاکستان|भारत|বাংলা|ভারত|ਭਾਰਤ|" +
+		"ભારત|இ(?:ந்தியா|லங்கை)|சிங்கப்பூர்|" +
+		"భారత్|ලංකා|ไทย|გე|台[湾灣]|澳門|香港|한국)"
+
+	// Matches any of the country code TLDs
+	urlValidCCTLD = "(?:a[c-gil-oq-uwxz]|b[abd-jl-oq-tvwyz]|c[acdf-ik-oru-z]|d[ejkmoz]|e[ceghr-u]|f[i-kmor]|" +
+		"g[abd-il-np-uwy]|h[kmnrtu]|i[del-oq-t]|j[emop]|k[eg-imnprwyz]|l[a-cikr-vy]|m[ac-hk-z]|" +
+		"n[ace-gilopruz]|om|p[ae-hk-nr-twy]|qa|r[eosuw]|s[a-eg-or-vx-z]|t[cdf-hj-prtvwz]|u[agkmsyz]|" +
+		"v[aceginu]|w[fs]|y[et]|z[amw]|ελ|бел|м(?:кд|он)|рф|срб|укр|қаз|հայ|" +
+		"ا(?:ل(?:اردن|جزائر|سعودية|مغرب)|مارات|یران)|بھارت|تونس|" +
+		"سو(?:دان|رية)|ع(?:راق|مان)|فلسطين|قطر|م(?:صر|ليسيا)|پاکستان|" +
+		"भारत|বাংলা|ভারত|ਭਾਰਤ|ભારત|இ(?:ந்தியா|" +
+		"லங்கை)|சிங்கப்பூர்|భారత్|ලංකා|ไทย|გე|" +
+		"中[国國]|台[湾灣]|新加坡|澳門|香港|한국)"
+)
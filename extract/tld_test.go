@@ -0,0 +1,208 @@
+package extract
+
+import (
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Fragments that are significant to the URL patterns, used to generate
+// random texts for cross-checking
+var urlAlphabet = []string{
+	"http://", "HTTPS://", "httpſ://", "www", "example", "t", "co", "com", "COM",
+	"comm", "community", "uk", "jp", "tv", "xn--", "p1ai", "ſ", "K", "K", ".",
+	"..", "-", "_", "/", "?", ":", "8080", "#", "(", ")", " ", "@", "$", "=",
+	"&", "é", "ß", "한국", "みんな", "ком", "ΕΛ", "‪", "\xff", "\\",
+	"!", "'", "+", ",", ";", "~", "|", "[", "]", "*", "%", "a", "\u3000", "日",
+}
+
+// Returns the conformance texts, followed by random texts made of
+// fragments from urlAlphabet
+func urlCrossCheckTexts(t *testing.T) []string {
+	texts := conformanceTexts(t)
+	texts = append(texts,
+		"example.com", "www.example.com.foo", "example.comfoo", "example.com한국",
+		"한국twitter.com", "a.co.uk", "a.b_c.com", "a_b.com", "-a.com", "a-.com",
+		"http://xn--80ak6aa92e.com", "xn--p1ai.xn--", "ExAmPle.CoM/PaTh", "http://t.co/abc",
+		"foo.zzz bar.com", "@foo.com", "$foo.com", "foo.ком", "FOO.КОМ",
+	)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		var b strings.Builder
+		for n := rnd.Intn(10); n >= 0; n-- {
+			b.WriteString(urlAlphabet[rnd.Intn(len(urlAlphabet))])
+		}
+		texts = append(texts, b.String())
+	}
+	return texts
+}
+
+func TestFindUrlMatchesRegexp(t *testing.T) {
+	for _, text := range urlCrossCheckTexts(t) {
+		expected := validUrl.FindStringSubmatchIndex(text)
+		actual := findUrl(text, make([]int, urlMatchLength))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("findUrl returned incorrect value for text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+func TestScanUrlTailMatchesRegexp(t *testing.T) {
+	texts := append(urlCrossCheckTexts(t),
+		"/path", "/path/", "/a.b.", "/a?b=c", "/a?b=c.", "?q", "?", "/(a)", "/a@b",
+		"/é", "/aé", "/a ſ", "/a\u212a", "?a=b&c", "?a=b@", ":80/a", "x", "@", ".",
+	)
+	buf := make([]int, urlTailMatchLength)
+	for _, text := range texts {
+		for _, s := range []string{text, text[strings.IndexByte(text, '.')+1:]} {
+			actual, ok := scanUrlTail(s, buf)
+			if !ok {
+				continue
+			}
+			if expected := urlTail.FindStringSubmatchIndex(s); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("scanUrlTail returned incorrect value for text [%+q]. Expected:%v Got:%v", s, expected, actual)
+			}
+		}
+	}
+}
+
+func TestFindAsciiDomainMatchesRegexp(t *testing.T) {
+	for _, text := range urlCrossCheckTexts(t) {
+		expected := validAsciiDomain.FindStringIndex(text)
+		var actual []int
+		if start, end, ok := findAsciiDomain(text); ok {
+			actual = []int{start, end}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("findAsciiDomain returned incorrect value for text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+func TestTcoUrlLengthMatchesRegexp(t *testing.T) {
+	texts := append(urlCrossCheckTexts(t), "http://t.co/abc", "HTTPS://T.CO/ABC_d", "http://t.co/", "httpſ://t.co/K9", "http://t.com/a", "https://t.co/é")
+	for _, text := range texts {
+		expected := 0
+		if m := validTcoUrl.FindStringIndex(text); m != nil {
+			expected = m[1]
+		}
+		if actual := tcoUrlLength(text); actual != expected {
+			t.Errorf("tcoUrlLength returned incorrect value for text [%+q]. Expected:%d Got:%d", text, expected, actual)
+		}
+	}
+}
+
+func TestIsShortDomainMatchesRegexp(t *testing.T) {
+	loadTLDs()
+	texts := append(urlCrossCheckTexts(t), "t.co", "t.tv", "t.uk", "t.com", "t-t.co", "-t.co", "t_t.co", "t.CO", "a.b.co")
+	for _, text := range texts {
+		if expected, actual := invalidShortDomain.MatchString(text), isShortDomain(text, tlds.cc); actual != expected {
+			t.Errorf("isShortDomain returned incorrect value for ccTLD text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+		if expected, actual := validSpecialShortDomain.MatchString(text), isShortDomain(text, tlds.specialCC); actual != expected {
+			t.Errorf("isShortDomain returned incorrect value for special ccTLD text [%+q]. Expected:%v Got:%v", text, expected, actual)
+		}
+	}
+}
+
+func TestGeneratedTLDPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		tlds    []string
+	}{
+		{"urlValidTLD", urlValidTLD, strings.Fields(genericTLDs + " " + countryTLDs)},
+		{"urlValidCCTLD", urlValidCCTLD, strings.Fields(countryTLDs)},
+	}
+
+	for _, test := range tests {
+		reference := `(?:` + strings.Join(test.tlds, "|") + `)`
+		var texts []string
+		for _, tld := range test.tlds {
+			for i := range tld {
+				texts = append(texts, tld[:i])
+			}
+			texts = append(texts, tld, strings.ToUpper(tld), tld+"s", tld+"a.", tld+tld, "ſ"+tld[1:])
+		}
+
+		for _, suffix := range []string{``, `\z`} {
+			expected := regexp.MustCompile(`(?i)\A` + reference + suffix)
+			actual := regexp.MustCompile(`(?i)\A` + test.pattern + suffix)
+			for _, text := range texts {
+				if e, a := expected.FindStringIndex(text), actual.FindStringIndex(text); !reflect.DeepEqual(a, e) {
+					t.Errorf("%s%s matched incorrectly for text [%s]. Expected:%v Got:%v", test.name, suffix, text, e, a)
+				}
+			}
+		}
+	}
+}
+
+func TestTLDTrie(t *testing.T) {
+	trie := newTLDTrie([]string{"community", "com", "co", "ком", "co"}, foldRune)
+	tests := []struct {
+		text     string
+		start    int
+		expected []int
+	}{
+		{"community", 0, []int{9, 3, 2}},
+		{"x.COMMUNITY.org", 2, []int{11, 5, 4}},
+		{"commune", 0, []int{3, 2}},
+		{"c", 0, nil},
+		{"org", 0, nil},
+		{"КОМ", 0, []int{6}},
+		{"\u212aom", 0, nil},
+		{"ſ.co", 3, []int{5}},
+	}
+
+	for _, test := range tests {
+		actual := trie.matches(test.text, test.start, nil)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("matches returned incorrect value for text [%s] at %d. Expected:%v Got:%v", test.text, test.start, test.expected, actual)
+		}
+	}
+
+	exact := newTLDTrie([]string{"com"}, nil)
+	if actual := exact.matches("COM", 0, nil); actual != nil {
+		t.Errorf("matches returned incorrect value for text [COM] without folding. Expected:[] Got:%v", actual)
+	}
+}
+
+var benchmarkUrlText = "Check out http://www.example.com/path?query=1, twitter.com and foo.co.uk " +
+	"(see https://t.co/abc123). Not urls: file.txt, e.g. this.that, v1.2.3 and example.comfoo"
+
+func BenchmarkFindUrl(b *testing.B) {
+	b.ReportAllocs()
+	match := make([]int, urlMatchLength)
+	for i := 0; i < b.N; i++ {
+		for text := benchmarkUrlText; ; {
+			m := findUrl(text, match)
+			if m == nil {
+				break
+			}
+			text = text[m[1]:]
+		}
+	}
+}
+
+func BenchmarkRegexpUrl(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for text := benchmarkUrlText; ; {
+			m := validUrl.FindStringSubmatchIndex(text)
+			if m == nil {
+				break
+			}
+			text = text[m[1]:]
+		}
+	}
+}
+
+func BenchmarkExtractUrls(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ExtractUrls(benchmarkUrlText)
+	}
+}
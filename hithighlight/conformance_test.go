@@ -0,0 +1,11 @@
+package hithighlight
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+func TestHitHighlightConformance(t *testing.T) {
+	conformance.RunHitHighlight(t, NewHighlighter())
+}
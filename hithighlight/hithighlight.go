@@ -0,0 +1,340 @@
+// Package hithighlight provides routines for highlighting search hits in
+// tweets
+//
+// Hits are ranges of characters (runes) in a tweet's text, such as the
+// matches for a search query. Highlighting wraps each hit in <em> tags, or
+// in the tag and class configured on a Highlighter. The implementation is
+// based on the HitHighlighter of the twitter-text-* libraries published by
+// Twitter, and is tested using the hit_highlighting.yml file of the
+// Conformance test suite maintained by Twitter
+// (https://github.com/twitter/twitter-text-conformance).
+//
+// The text may contain HTML, e.g. the output of the autolink package. Hit
+// offsets refer to the text as it is displayed: tags are not counted, and
+// each character reference (such as &amp;) counts as a single character.
+// Text hidden with CSS, such as the invisible parts of an auto-linked URL,
+// is counted. Highlight tags are always properly nested within the
+// existing markup; where a hit starts or ends inside an element such as a
+// link, the highlight is split so that the element is not broken.
+//
+// Clients that render highlights themselves, such as terminal UIs, can
+// split plain text into highlighted and unhighlighted spans instead (see
+// Spans).
+package hithighlight
+
+import (
+	"html"
+	"sort"
+
+	"github.com/kylemcc/twitter-text-go/internal/bufpool"
+)
+
+// The tag used to highlight hits
+const DefaultHighlightTag = "em"
+
+// The units in which hit offsets are expressed
+type Units int
+
+const (
+	// Offsets count characters (runes). This is the default
+	Runes Units = iota
+
+	// Offsets count UTF-16 code units, as returned by the Twitter search
+	// APIs and by JavaScript string functions. Characters outside the
+	// Basic Multilingual Plane, such as most emoji, count as two units.
+	// Hits that start or end in the middle of such a character are
+	// extended to include the whole character
+	UTF16
+)
+
+// A Highlighter highlights hits using a configurable tag and CSS class.
+// The zero value is not usable; create Highlighters with NewHighlighter.
+type Highlighter struct {
+	Tag   string // Name of the tag used to highlight hits, e.g. "em" or "mark"
+	Class string // If set, the CSS class of the highlight tags
+	Units Units  // The units of hit offsets
+
+	// Whether to allocate a new buffer for each highlighted string. By
+	// default, buffers are taken from a pool and reused by later calls
+	NoBufferPool bool
+}
+
+// Returns a new Highlighter that highlights hits with <em> tags
+func NewHighlighter(opts ...Option) *Highlighter {
+	h := &Highlighter{Tag: DefaultHighlightTag}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// An Option overrides a single Highlighter setting
+type Option func(*Highlighter)
+
+// Sets the name of the tag used to highlight hits
+func WithTag(tag string) Option {
+	return func(h *Highlighter) { h.Tag = tag }
+}
+
+// Sets the CSS class of the highlight tags
+func WithClass(class string) Option {
+	return func(h *Highlighter) { h.Class = class }
+}
+
+// Sets the units of hit offsets
+func WithUnits(units Units) Option {
+	return func(h *Highlighter) { h.Units = units }
+}
+
+// Sets whether highlighting reuses pooled buffers (the default) or
+// allocates a new buffer for each call
+func WithBufferPool(enabled bool) Option {
+	return func(h *Highlighter) { h.NoBufferPool = !enabled }
+}
+
+// Highlights the given hits in text by wrapping each of them in <em> tags.
+// Each hit is a [start, end) range of character offsets into the text as
+// displayed. Hits may be given in any order; overlapping hits are merged
+// and hits are clipped to the length of the text. Text is returned
+// unchanged if there are no hits.
+func HitHighlight(text string, hits [][2]int) string {
+	return NewHighlighter().HitHighlight(text, hits)
+}
+
+// Highlights the given hits in text like HitHighlight, but with hit
+// offsets expressed in UTF-16 code units
+func HitHighlightUTF16(text string, hits [][2]int) string {
+	return NewHighlighter(WithUnits(UTF16)).HitHighlight(text, hits)
+}
+
+// Highlights the given hits in text by wrapping each of them in the
+// Highlighter's tag. Hits are interpreted as by the HitHighlight function.
+func (h *Highlighter) HitHighlight(text string, hits [][2]int) string {
+	return h.HighlightGroups(text, []HitGroup{{Hits: hits}})
+}
+
+// A HitGroup is a set of hits that are highlighted with the same tag and
+// class, such as the matches for one of several query terms
+type HitGroup struct {
+	Hits  [][2]int
+	Tag   string // The tag for the group's hits, or "" for the Highlighter's Tag
+	Class string // The class for the group's hits, or "" for the Highlighter's Class
+}
+
+// Highlights several groups of hits in text in a single pass using the
+// default settings. Groups are interpreted as by the HighlightGroups
+// method.
+func HighlightGroups(text string, groups []HitGroup) string {
+	return NewHighlighter().HighlightGroups(text, groups)
+}
+
+// Highlights several groups of hits in text in a single pass, wrapping the
+// hits of each group in the group's tag and class, so that hits for
+// different query terms can be styled differently without highlighting
+// the same text repeatedly. Hits are interpreted as by the HitHighlight
+// function. Where hits of different groups overlap, the group that comes
+// first takes precedence: the overlapping part of a later group's hit is
+// not highlighted by that group, so highlights are never nested.
+func (h *Highlighter) HighlightGroups(text string, groups []HitGroup) string {
+	tokens := tokenize(text)
+	var chars []int // Index of the token of each character
+	for i, t := range tokens {
+		if !t.isTag {
+			chars = append(chars, i)
+		}
+	}
+
+	// Assign each character to the first group with a hit that contains it
+	var ranges []groupRange
+	covered := make([]bool, len(chars))
+	for g, group := range groups {
+		hits := group.Hits
+		if h.Units == UTF16 {
+			hits = utf16ToRunes(hits, tokens)
+		}
+		for _, hit := range normalizeHits(hits, len(chars)) {
+			for i := hit[0]; i < hit[1]; {
+				if covered[i] {
+					i++
+					continue
+				}
+				j := i
+				for ; j < hit[1] && !covered[j]; j++ {
+					covered[j] = true
+				}
+				ranges = append(ranges, groupRange{i, j, g})
+				i = j
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return text
+	}
+	sort.Sort(byRangeStart(ranges))
+
+	openTags := make([]string, len(groups))
+	closeTags := make([]string, len(groups))
+	for g, group := range groups {
+		gh := *h
+		if group.Tag != "" {
+			gh.Tag = group.Tag
+		}
+		if group.Class != "" {
+			gh.Class = group.Class
+		}
+		openTags[g], closeTags[g] = gh.openTag(), gh.closeTag()
+	}
+
+	buf := bufpool.Get(!h.NoBufferPool)
+	if !h.NoBufferPool {
+		defer bufpool.Put(buf)
+	}
+	next := 0
+	for _, r := range ranges {
+		start, end := chars[r.start], chars[r.end-1]
+		end, unbalanced := balance(tokens, start, end)
+
+		for _, t := range tokens[next:start] {
+			buf.WriteString(t.s)
+		}
+		open := false
+		for i := start; i <= end; i++ {
+			if unbalanced[i] {
+				// The tag's element starts or ends outside the hit, so
+				// the highlight can't contain it
+				if open {
+					buf.WriteString(closeTags[r.group])
+					open = false
+				}
+			} else if !open {
+				buf.WriteString(openTags[r.group])
+				open = true
+			}
+			buf.WriteString(tokens[i].s)
+		}
+		if open {
+			buf.WriteString(closeTags[r.group])
+		}
+		next = end + 1
+	}
+	for _, t := range tokens[next:] {
+		buf.WriteString(t.s)
+	}
+	return buf.String()
+}
+
+func (h *Highlighter) openTag() string {
+	if h.Class == "" {
+		return "<" + h.Tag + ">"
+	}
+	return "<" + h.Tag + ` class="` + html.EscapeString(h.Class) + `">`
+}
+
+func (h *Highlighter) closeTag() string {
+	return "</" + h.Tag + ">"
+}
+
+// A range of characters highlighted by the hit group with index group
+type groupRange struct {
+	start, end, group int
+}
+
+type byRangeStart []groupRange
+
+func (r byRangeStart) Len() int           { return len(r) }
+func (r byRangeStart) Less(i, j int) bool { return r[i].start < r[j].start }
+func (r byRangeStart) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// Returns the last token of the highlight for the tokens from start to
+// end, extended to include closing tags that immediately follow end and
+// balance opening tags within the range, and the set of tags in the
+// highlight whose elements start or end outside of it
+func balance(tokens []token, start, end int) (int, map[int]bool) {
+	var stack []int // Unclosed opening tags
+	unbalanced := make(map[int]bool)
+	for i := start; i <= end; i++ {
+		t := tokens[i]
+		switch {
+		case !t.isTag || t.void:
+		case !t.closing:
+			stack = append(stack, i)
+		case len(stack) > 0 && tokens[stack[len(stack)-1]].name == t.name:
+			stack = stack[:len(stack)-1]
+		default:
+			unbalanced[i] = true
+		}
+	}
+	for len(stack) > 0 && end+1 < len(tokens) {
+		t := tokens[end+1]
+		if !t.isTag || !t.closing || tokens[stack[len(stack)-1]].name != t.name {
+			break
+		}
+		stack = stack[:len(stack)-1]
+		end++
+	}
+	for _, i := range stack {
+		unbalanced[i] = true
+	}
+	return end, unbalanced
+}
+
+// Converts hits with offsets in UTF-16 code units to hits with offsets in
+// characters, rounding offsets in the middle of a character outward
+func utf16ToRunes(hits [][2]int, tokens []token) [][2]int {
+	// The UTF-16 offset of each character, and of the end of the text
+	offsets := []int{0}
+	for _, t := range tokens {
+		if !t.isTag {
+			offsets = append(offsets, offsets[len(offsets)-1]+t.utf16Len())
+		}
+	}
+
+	result := make([][2]int, len(hits))
+	for i, hit := range hits {
+		// The last character that starts at or before the start of the
+		// hit, and the first character that ends at or after its end
+		start := sort.SearchInts(offsets, hit[0]+1) - 1
+		if start < 0 {
+			start = 0
+		}
+		result[i] = [2]int{start, sort.SearchInts(offsets, hit[1])}
+	}
+	return result
+}
+
+// Returns the non-empty hits clipped to [0, length), sorted, with
+// overlapping hits merged
+func normalizeHits(hits [][2]int, length int) [][2]int {
+	result := make([][2]int, 0, len(hits))
+	for _, hit := range hits {
+		if hit[0] < 0 {
+			hit[0] = 0
+		}
+		if hit[1] > length {
+			hit[1] = length
+		}
+		if hit[0] < hit[1] {
+			result = append(result, hit)
+		}
+	}
+	sort.Sort(byStart(result))
+
+	n := 0
+	for _, hit := range result {
+		if n > 0 && hit[0] < result[n-1][1] {
+			if hit[1] > result[n-1][1] {
+				result[n-1][1] = hit[1]
+			}
+			continue
+		}
+		result[n] = hit
+		n++
+	}
+	return result[:n]
+}
+
+type byStart [][2]int
+
+func (h byStart) Len() int           { return len(h) }
+func (h byStart) Less(i, j int) bool { return h[i][0] < h[j][0] }
+func (h byStart) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
@@ -0,0 +1,236 @@
+package hithighlight
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+)
+
+func ExampleHitHighlight() {
+	fmt.Println(HitHighlight("this is a test", [][2]int{{0, 4}, {10, 14}}))
+	// Output:
+	// <em>this</em> is a <em>test</em>
+}
+
+func TestHitHighlight(t *testing.T) {
+	link := `<a class="tweet-url username" href="https://twitter.com/username">username</a>`
+	tests := []struct {
+		text     string
+		hits     [][2]int
+		expected string
+	}{
+		{"this is a test", nil, "this is a test"},
+		{"this is a test", [][2]int{{0, 4}}, "<em>this</em> is a test"},
+		{"this is a test", [][2]int{{5, 7}}, "this <em>is</em> a test"},
+		{"this is a test", [][2]int{{10, 14}}, "this is a <em>test</em>"},
+		{"this is a test", [][2]int{{0, 4}, {8, 9}}, "<em>this</em> is <em>a</em> test"},
+		{"this is a test", [][2]int{{0, 14}}, "<em>this is a test</em>"},
+		{"this is a test", [][2]int{{10, 20}}, "this is a <em>test</em>"},
+		{"日本語のテキスト", [][2]int{{4, 8}}, "日本語の<em>テキスト</em>"},
+		{"@" + link + " this is a test", [][2]int{{10, 14}}, "@" + link + " <em>this</em> is a test"},
+		{"@" + link + " this is a test", [][2]int{{0, 9}}, "<em>@" + link + "</em> this is a test"},
+		{"@" + link + " this is a test", [][2]int{{1, 9}}, "@" + `<a class="tweet-url username" href="https://twitter.com/username"><em>username</em></a>` + " this is a test"},
+		{"@" + link, [][2]int{{1, 9}}, "@" + `<a class="tweet-url username" href="https://twitter.com/username"><em>username</em></a>`},
+	}
+
+	for _, test := range tests {
+		actual := HitHighlight(test.text, test.hits)
+		if actual != test.expected {
+			t.Errorf("HitHighlight returned incorrect value for text [%s] and hits %v. Expected:[%s] Got:[%s]", test.text, test.hits, test.expected, actual)
+		}
+	}
+}
+
+func TestHighlighterTagAndClass(t *testing.T) {
+	text := "this is a test"
+	hits := [][2]int{{0, 4}}
+	tests := []struct {
+		h        *Highlighter
+		expected string
+	}{
+		{NewHighlighter(), "<em>this</em> is a test"},
+		{NewHighlighter(WithTag("mark")), "<mark>this</mark> is a test"},
+		{NewHighlighter(WithTag("span"), WithClass("search-hit")), `<span class="search-hit">this</span> is a test`},
+		{NewHighlighter(WithClass(`a"b`)), `<em class="a&#34;b">this</em> is a test`},
+	}
+
+	for _, test := range tests {
+		actual := test.h.HitHighlight(text, hits)
+		if actual != test.expected {
+			t.Errorf("HitHighlight returned incorrect value for text [%s] and hits %v. Expected:[%s] Got:[%s]", text, hits, test.expected, actual)
+		}
+	}
+}
+
+func TestHitHighlightHtml(t *testing.T) {
+	tests := []struct {
+		text     string
+		hits     [][2]int
+		expected string
+	}{
+		{`@<a href="u">username</a> test`, [][2]int{{0, 5}},
+			`<em>@</em><a href="u"><em>user</em>name</a> test`},
+		{`@<a href="u">username</a> test`, [][2]int{{5, 14}},
+			`@<a href="u">user<em>name</em></a><em> test</em>`},
+		{`a <b><i>bold</i></b> c`, [][2]int{{0, 8}},
+			`<em>a <b><i>bold</i></b> c</em>`},
+		{`a <b><i>bold</i></b> c`, [][2]int{{2, 6}},
+			`a <b><i><em>bold</em></i></b> c`},
+		{`a<br>b<!-- c -->d`, [][2]int{{0, 3}},
+			`<em>a<br>b<!-- c -->d</em>`},
+		{"AT&amp;T &lt;3", [][2]int{{0, 4}, {5, 7}},
+			"<em>AT&amp;T</em> <em>&lt;3</em>"},
+		{"1 < 2 > 0", [][2]int{{4, 5}},
+			"1 < <em>2</em> > 0"},
+		{"this is a test", [][2]int{{8, 9}, {0, 4}},
+			"<em>this</em> is <em>a</em> test"},
+		{"this is a test", [][2]int{{0, 6}, {5, 7}},
+			"<em>this is</em> a test"},
+		{"this is a test", [][2]int{{3, 3}, {-1, 1}},
+			"<em>t</em>his is a test"},
+	}
+
+	for _, test := range tests {
+		actual := HitHighlight(test.text, test.hits)
+		if actual != test.expected {
+			t.Errorf("HitHighlight returned incorrect value for text [%s] and hits %v. Expected:[%s] Got:[%s]", test.text, test.hits, test.expected, actual)
+		}
+	}
+}
+
+func TestHitHighlightAutoLinked(t *testing.T) {
+	text := autolink.AutoLink("R&D at @twitter", autolink.WithNoFollow(false))
+	hits := [][2]int{{0, 3}, {7, 12}}
+	expected := `<em>R&amp;D</em> at <em>@</em><a class="tweet-url username" href="https://twitter.com/twitter"><em>twit</em>ter</a>`
+	if actual := HitHighlight(text, hits); actual != expected {
+		t.Errorf("HitHighlight returned incorrect value for text [%s] and hits %v. Expected:[%s] Got:[%s]", text, hits, expected, actual)
+	}
+}
+
+func TestHitHighlightUTF16(t *testing.T) {
+	tests := []struct {
+		text     string
+		hits     [][2]int
+		expected string
+	}{
+		{"this is a test", [][2]int{{10, 14}}, "this is a <em>test</em>"},
+		{"😀 smile 😀", [][2]int{{3, 8}}, "😀 <em>smile</em> 😀"},
+		{"😀 smile 😀", [][2]int{{0, 2}, {9, 11}}, "<em>😀</em> smile <em>😀</em>"},
+		{"😀 smile", [][2]int{{1, 3}}, "<em>😀 </em>smile"},
+		{"&#128512; smile", [][2]int{{3, 8}}, "&#128512; <em>smile</em>"},
+		{`<a href="u">😀</a>x`, [][2]int{{2, 3}}, `<a href="u">😀</a><em>x</em>`},
+	}
+
+	for _, test := range tests {
+		actual := HitHighlightUTF16(test.text, test.hits)
+		if actual != test.expected {
+			t.Errorf("HitHighlightUTF16 returned incorrect value for text [%s] and hits %v. Expected:[%s] Got:[%s]", test.text, test.hits, test.expected, actual)
+		}
+	}
+}
+
+func TestHighlightQuery(t *testing.T) {
+	tests := []struct {
+		text       string
+		terms      []string
+		wholeWords bool
+		expected   string
+	}{
+		{"Tweet about tweets", []string{"tweet"}, false, "<em>Tweet</em> about <em>tweet</em>s"},
+		{"Tweet about tweets", []string{"tweet"}, true, "<em>Tweet</em> about tweets"},
+		{"Tweet about tweets", []string{"TWEET", "about"}, true, "<em>Tweet</em> <em>about</em> tweets"},
+		{"Tweet about tweets", []string{"about tweets"}, false, "Tweet <em>about tweets</em>"},
+		{"ΣΊΣΥΦΟΣ σίσυφος", []string{"σίσυφοσ"}, false, "<em>ΣΊΣΥΦΟΣ</em> <em>σίσυφος</em>"},
+		{"AT&amp;T at&t", []string{"at&t"}, true, "<em>AT&amp;T</em> <em>at&t</em>"},
+		{`<a href="/tweet">link</a> tweet`, []string{"tweet"}, false, `<a href="/tweet">link</a> <em>tweet</em>`},
+		{"aaaa", []string{"aa", "a"}, false, "<em>aaaa</em>"},
+		{"no match", []string{"", "tweet"}, false, "no match"},
+	}
+
+	for _, test := range tests {
+		actual := HighlightQuery(test.text, test.terms, test.wholeWords)
+		if actual != test.expected {
+			t.Errorf("HighlightQuery returned incorrect value for text [%s] and terms %q. Expected:[%s] Got:[%s]", test.text, test.terms, test.expected, actual)
+		}
+	}
+}
+
+func TestQueryHitsIgnoresUnits(t *testing.T) {
+	h := NewHighlighter(WithUnits(UTF16))
+	text := "😀 tweet"
+	expected := "😀 <em>tweet</em>"
+	if actual := h.HighlightQuery(text, []string{"tweet"}, true); actual != expected {
+		t.Errorf("HighlightQuery returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	tests := []struct {
+		text     string
+		hits     [][2]int
+		units    Units
+		expected []Span
+	}{
+		{"this is a test", nil, Runes, []Span{{"this is a test", false}}},
+		{"this is a test", [][2]int{{0, 4}, {10, 14}}, Runes,
+			[]Span{{"this", true}, {" is a ", false}, {"test", true}}},
+		{"this is a test", [][2]int{{5, 7}}, Runes,
+			[]Span{{"this ", false}, {"is", true}, {" a test", false}}},
+		{"<b>&amp;</b> 日本", [][2]int{{0, 3}, {13, 15}}, Runes,
+			[]Span{{"<b>", true}, {"&amp;</b> ", false}, {"日本", true}}},
+		{"😀 smile", [][2]int{{3, 8}}, UTF16,
+			[]Span{{"😀 ", false}, {"smile", true}}},
+		{"", [][2]int{{0, 1}}, Runes, nil},
+	}
+
+	for _, test := range tests {
+		actual := NewHighlighter(WithUnits(test.units)).Spans(test.text, test.hits)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Spans returned incorrect value for text [%s] and hits %v. Expected:[%v] Got:[%v]", test.text, test.hits, test.expected, actual)
+		}
+	}
+}
+
+func TestHighlightGroups(t *testing.T) {
+	text := `one two <a href="u">three</a> one`
+	groups := []HitGroup{
+		{Hits: [][2]int{{0, 3}, {14, 17}}, Class: "term-1"},
+		{Hits: [][2]int{{4, 7}}, Tag: "mark"},
+		{Hits: [][2]int{{2, 13}}, Class: "term-3"},
+	}
+
+	tests := []struct {
+		h        *Highlighter
+		groups   []HitGroup
+		expected string
+	}{
+		{NewHighlighter(), groups,
+			`<em class="term-1">one</em><em class="term-3"> </em><mark>two</mark><em class="term-3"> <a href="u">three</a></em> <em class="term-1">one</em>`},
+		{NewHighlighter(WithTag("span"), WithClass("hit")), groups[:1],
+			`<span class="term-1">one</span> two <a href="u">three</a> <span class="term-1">one</span>`},
+		{NewHighlighter(WithTag("span"), WithClass("hit")), []HitGroup{{Hits: [][2]int{{0, 3}}}},
+			`<span class="hit">one</span> two <a href="u">three</a> one`},
+		{NewHighlighter(), nil, text},
+	}
+
+	for _, test := range tests {
+		actual := test.h.HighlightGroups(text, test.groups)
+		if actual != test.expected {
+			t.Errorf("HighlightGroups returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, test.expected, actual)
+		}
+	}
+}
+
+func TestHitHighlightBufferPool(t *testing.T) {
+	text := "this is a <b>test</b> with a <a href=\"#\">link</a>"
+	hits := [][2]int{{0, 4}, {10, 14}, {22, 26}}
+	expected := NewHighlighter(WithBufferPool(false)).HitHighlight(text, hits)
+	pooled := NewHighlighter()
+	for i := 0; i < 10; i++ {
+		if actual := pooled.HitHighlight(text, hits); actual != expected {
+			t.Errorf("HitHighlight returned incorrect value with pooled buffers. Expected:[%s] Got:[%s]", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package hithighlight
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// A token is either a tag or a single displayed character of HTML text,
+// which may be a character reference
+type token struct {
+	s       string
+	isTag   bool
+	closing bool   // Whether the tag is an end tag
+	void    bool   // Whether the tag has no end tag (e.g. <br>, <img/>, or a comment)
+	name    string // The lower case name of the tag
+}
+
+var (
+	charReference = regexp.MustCompile(`^&(?:#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+	tagStart      = regexp.MustCompile(`^<[a-zA-Z/!?]`)
+	tagName       = regexp.MustCompile(`^</?([a-zA-Z][a-zA-Z0-9-]*)`)
+)
+
+// Elements that have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// Splits HTML text into tags and characters. A < that does not start a
+// tag is treated as a character
+func tokenize(text string) []token {
+	var tokens []token
+	for len(text) > 0 {
+		var t token
+		switch text[0] {
+		case '<':
+			if tagStart.MatchString(text) {
+				if end := strings.IndexByte(text, '>'); end > 0 {
+					t = newTag(text[:end+1])
+				}
+			}
+		case '&':
+			if ref := charReference.FindString(text); ref != "" {
+				t.s = ref
+			}
+		}
+		if t.s == "" {
+			_, size := utf8.DecodeRuneInString(text)
+			t.s = text[:size]
+		}
+		tokens = append(tokens, t)
+		text = text[len(t.s):]
+	}
+	return tokens
+}
+
+func newTag(s string) token {
+	t := token{s: s, isTag: true, closing: strings.HasPrefix(s, "</")}
+	if m := tagName.FindStringSubmatch(s); m != nil {
+		t.name = strings.ToLower(m[1])
+		t.void = voidElements[t.name] || strings.HasSuffix(s, "/>")
+	} else {
+		// Comments, doctypes, and processing instructions
+		t.void = true
+	}
+	return t
+}
+
+// Returns the length of a character token in UTF-16 code units
+func (t token) utf16Len() int {
+	s := t.s
+	if s[0] == '&' {
+		s = html.UnescapeString(s)
+	}
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
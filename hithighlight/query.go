@@ -0,0 +1,94 @@
+package hithighlight
+
+import (
+	"html"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Returns the hits for all occurrences of the given query terms in text, as
+// sorted, non-overlapping character offsets into the text as displayed
+// (see HitHighlight). Terms are matched using Unicode simple case folding,
+// so "tweet" matches "Tweet" and "TWEET". A term may contain spaces, in
+// which case it is matched as a phrase. If wholeWords is true, a term only
+// matches where it is not preceded or followed by a letter, digit, or
+// underscore.
+func QueryHits(text string, terms []string, wholeWords bool) [][2]int {
+	var chars []rune
+	for _, t := range tokenize(text) {
+		if !t.isTag {
+			chars = append(chars, foldRune(t.rune()))
+		}
+	}
+
+	var hits [][2]int
+	for _, term := range terms {
+		var folded []rune
+		for _, r := range term {
+			folded = append(folded, foldRune(r))
+		}
+		if len(folded) == 0 {
+			continue
+		}
+		for start := 0; start+len(folded) <= len(chars); start++ {
+			end := start + len(folded)
+			if !runesEqual(chars[start:end], folded) {
+				continue
+			}
+			if wholeWords && ((start > 0 && isWordRune(chars[start-1])) || (end < len(chars) && isWordRune(chars[end]))) {
+				continue
+			}
+			hits = append(hits, [2]int{start, end})
+		}
+	}
+	return normalizeHits(hits, len(chars))
+}
+
+// Highlights all occurrences of the given query terms in text using <em>
+// tags. Terms are matched as by QueryHits.
+func HighlightQuery(text string, terms []string, wholeWords bool) string {
+	return NewHighlighter().HighlightQuery(text, terms, wholeWords)
+}
+
+// Highlights all occurrences of the given query terms in text using the
+// Highlighter's tag. Terms are matched as by QueryHits.
+func (h *Highlighter) HighlightQuery(text string, terms []string, wholeWords bool) string {
+	runes := *h
+	runes.Units = Runes
+	return runes.HitHighlight(text, QueryHits(text, terms, wholeWords))
+}
+
+// Returns the canonical rune of the case folding orbit of r, so that runes
+// that are equal under simple case folding have the same canonical rune
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Returns the character represented by a character token
+func (t token) rune() rune {
+	s := t.s
+	if s[0] == '&' {
+		s = html.UnescapeString(s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
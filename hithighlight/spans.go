@@ -0,0 +1,62 @@
+package hithighlight
+
+import "unicode/utf8"
+
+// A Span is a run of text in the output of Spans
+type Span struct {
+	Text        string
+	Highlighted bool // Whether the span is a hit
+}
+
+// Splits plain text into highlighted and unhighlighted spans, with hit
+// offsets in characters. Hits are interpreted as by HitHighlight, except
+// that the text is not treated as HTML.
+func Spans(text string, hits [][2]int) []Span {
+	return NewHighlighter().Spans(text, hits)
+}
+
+// Splits plain text into highlighted and unhighlighted spans, for terminal
+// UIs and native clients that render highlights themselves instead of
+// consuming markup. Hit offsets are in the Highlighter's Units and are
+// interpreted as by HitHighlight, except that the text is not treated as
+// HTML: tags and character references are ordinary text. Concatenating the
+// text of the spans yields the original text. The tag and class of the
+// Highlighter are not used.
+func (h *Highlighter) Spans(text string, hits [][2]int) []Span {
+	tokens := plainTokens(text)
+	if h.Units == UTF16 {
+		hits = utf16ToRunes(hits, tokens)
+	}
+	hits = normalizeHits(hits, len(tokens))
+
+	// The byte offset of each character, and of the end of the text
+	offsets := make([]int, 0, len(tokens)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	var spans []Span
+	next := 0
+	for _, hit := range hits {
+		if hit[0] > next {
+			spans = append(spans, Span{Text: text[offsets[next]:offsets[hit[0]]]})
+		}
+		spans = append(spans, Span{Text: text[offsets[hit[0]]:offsets[hit[1]]], Highlighted: true})
+		next = hit[1]
+	}
+	if next < len(tokens) {
+		spans = append(spans, Span{Text: text[offsets[next]:]})
+	}
+	return spans
+}
+
+// Splits plain text into character tokens, without recognizing tags or
+// character references
+func plainTokens(text string) []token {
+	tokens := make([]token, 0, utf8.RuneCountInString(text))
+	for i, r := range text {
+		tokens = append(tokens, token{s: text[i : i+utf8.RuneLen(r)]})
+	}
+	return tokens
+}
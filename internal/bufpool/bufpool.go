@@ -0,0 +1,38 @@
+// Package bufpool provides a pool of byte buffers for the packages that
+// render text, so that repeated calls reuse memory instead of growing a
+// new buffer each time
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Buffers that have grown beyond this capacity are not returned to the
+// pool, so that a single large input does not pin memory indefinitely
+const maxRetainedCap = 64 << 10
+
+var pool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// Returns an empty buffer. If pooled is false, a new buffer is allocated;
+// otherwise, the buffer may come from the pool and should be returned to
+// it with Put once its contents are no longer needed
+func Get(pooled bool) *bytes.Buffer {
+	if !pooled {
+		return new(bytes.Buffer)
+	}
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// Returns a buffer obtained from Get(true) to the pool. The buffer must
+// not be used afterwards
+func Put(buf *bytes.Buffer) {
+	if buf.Cap() > maxRetainedCap {
+		return
+	}
+	pool.Put(buf)
+}
@@ -0,0 +1,30 @@
+package bufpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	for _, pooled := range []bool{true, false} {
+		buf := Get(pooled)
+		if buf.Len() != 0 {
+			t.Errorf("Get(%t) returned a buffer with length %d", pooled, buf.Len())
+		}
+		buf.WriteString("some output")
+		if pooled {
+			Put(buf)
+		}
+	}
+}
+
+func TestPutDiscardsLargeBuffers(t *testing.T) {
+	buf := Get(true)
+	buf.WriteString(strings.Repeat("x", maxRetainedCap+1))
+	Put(buf)
+	for i := 0; i < 10; i++ {
+		if b := Get(true); b == buf {
+			t.Errorf("Get returned a buffer larger than %d bytes from the pool", maxRetainedCap)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Package cmdutil provides the extraction and rendering functions that the
+// twtext and twtextd commands select by name
+package cmdutil
+
+import (
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// The extraction functions, by the names used to select them
+var Extractors = map[string]func(string) []*extract.TwitterEntity{
+	"all":      extract.ExtractEntities,
+	"mentions": extract.ExtractMentionsOrLists,
+	"hashtags": extract.ExtractHashtags,
+	"cashtags": extract.ExtractCashtags,
+	"urls":     extract.ExtractUrls,
+	"emoji":    extract.ExtractEmoji,
+}
+
+// The functions that render the entities in a text, by the names used to
+// select them
+var Renderers = map[string]func(string) string{
+	"html": func(text string) string { return autolink.AutoLink(text) },
+	"ansi": func(text string) string {
+		return autolink.RenderANSI(text, extract.ExtractEntities(text))
+	},
+	"markdown": func(text string) string {
+		return autolink.RenderMarkdown(text, extract.ExtractEntities(text))
+	},
+	"slack": func(text string) string {
+		return autolink.RenderSlack(text, extract.ExtractEntities(text))
+	},
+}
@@ -0,0 +1,66 @@
+// Package lazyregexp provides regular expressions that are compiled on
+// first use rather than at package initialization. The URL and TLD
+// patterns used by extraction and validation are large, and compiling them
+// eagerly costs startup time and memory in programs that never use them.
+//
+// Patterns are compiled by the engine selected with regexpengine.Set when
+// they are first used.
+package lazyregexp
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/kylemcc/twitter-text-go/regexpengine"
+)
+
+// A Regexp is a regular expression that is compiled the first time it is
+// used. It is safe for concurrent use
+type Regexp struct {
+	pattern string
+	once    sync.Once
+	re      regexpengine.Matcher
+}
+
+// Returns a Regexp for the given pattern. Like regexp.MustCompile, the
+// first use of the Regexp panics if the pattern is invalid
+func New(pattern string) *Regexp {
+	return &Regexp{pattern: pattern}
+}
+
+// Returns the compiled regular expression, compiling it if necessary
+func (r *Regexp) Matcher() regexpengine.Matcher {
+	r.once.Do(func() {
+		re, err := regexpengine.Get().Compile(r.pattern)
+		if err != nil {
+			panic("lazyregexp: Compile(" + strconv.Quote(r.pattern) + "): " + err.Error())
+		}
+		r.re = re
+	})
+	return r.re
+}
+
+// Returns the source text of the regular expression
+func (r *Regexp) String() string {
+	return r.pattern
+}
+
+// See regexp.Regexp.MatchString
+func (r *Regexp) MatchString(s string) bool {
+	return r.Matcher().MatchString(s)
+}
+
+// See regexp.Regexp.FindStringIndex
+func (r *Regexp) FindStringIndex(s string) []int {
+	return r.Matcher().FindStringIndex(s)
+}
+
+// See regexp.Regexp.FindStringSubmatchIndex
+func (r *Regexp) FindStringSubmatchIndex(s string) []int {
+	return r.Matcher().FindStringSubmatchIndex(s)
+}
+
+// See regexp.Regexp.FindAllStringSubmatchIndex
+func (r *Regexp) FindAllStringSubmatchIndex(s string, n int) [][]int {
+	return r.Matcher().FindAllStringSubmatchIndex(s, n)
+}
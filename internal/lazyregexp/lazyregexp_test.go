@@ -0,0 +1,68 @@
+package lazyregexp
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/regexpengine"
+)
+
+func TestRegexp(t *testing.T) {
+	r := New(`(a+)(b)?`)
+	if r.re != nil {
+		t.Errorf("New compiled the regexp before its first use")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !r.MatchString("xaab") {
+				t.Errorf("MatchString returned false for a matching string")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if loc := r.FindStringIndex("xaab"); len(loc) != 2 || loc[0] != 1 || loc[1] != 4 {
+		t.Errorf("FindStringIndex returned incorrect value. Expected:[1 4] Got:%v", loc)
+	}
+	if m := r.FindAllStringSubmatchIndex("ab a", -1); len(m) != 2 {
+		t.Errorf("FindAllStringSubmatchIndex returned incorrect number of matches. Expected:2 Got:%d", len(m))
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	r := New(`(`)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Using an invalid Regexp did not panic")
+		}
+	}()
+	r.MatchString("")
+}
+
+// An engine that compiles every pattern as a literal string
+type literalEngine struct{}
+
+func (literalEngine) Compile(pattern string) (regexpengine.Matcher, error) {
+	return regexpengine.Standard.Compile(regexp.QuoteMeta(pattern))
+}
+
+func TestEngine(t *testing.T) {
+	defer regexpengine.Set(nil)
+
+	before := New(`a+`)
+	before.MatchString("")
+
+	regexpengine.Set(literalEngine{})
+	after := New(`a+`)
+	if !after.MatchString("xa+") || after.MatchString("aa") {
+		t.Errorf("Regexp was not compiled with the engine selected when it was first used")
+	}
+	if !before.MatchString("aa") {
+		t.Errorf("Regexp compiled before the engine was selected was compiled again")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unsafeconv
+// +build !unsafeconv
+
+package unsafeconv
+
+// Whether String shares memory with its argument
+const ZeroCopy = false
+
+// Returns the contents of b as a string
+func String(b []byte) string {
+	return string(b)
+}
@@ -0,0 +1,14 @@
+//go:build unsafeconv
+// +build unsafeconv
+
+package unsafeconv
+
+import "unsafe"
+
+// Whether String shares memory with its argument
+const ZeroCopy = true
+
+// Returns the contents of b as a string that shares b's memory
+func String(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
@@ -0,0 +1,10 @@
+// Package unsafeconv converts byte slices to strings for the APIs that
+// accept text as a []byte.
+//
+// By default, String copies the bytes, like string(b). Building with the
+// unsafeconv tag makes it return a string that shares the slice's memory
+// instead, which avoids an allocation and a copy per call. Callers that
+// enable the tag must not modify a slice after passing it to such an API
+// for as long as any result derived from it (including entity text) is in
+// use.
+package unsafeconv
@@ -0,0 +1,20 @@
+package unsafeconv
+
+import "testing"
+
+func TestString(t *testing.T) {
+	b := []byte("text")
+	s := String(b)
+	if s != "text" {
+		t.Errorf("String returned incorrect value. Expected:[text] Got:[%s]", s)
+	}
+
+	b[0] = 'n'
+	if shared := s == "next"; shared != ZeroCopy {
+		t.Errorf("String shared memory with its argument: %v, expected %v", shared, ZeroCopy)
+	}
+
+	if s := String(nil); s != "" {
+		t.Errorf("String returned incorrect value for nil. Expected:[] Got:[%s]", s)
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/kylemcc/twitter-text-go/interop
+
+go 1.19
+
+require (
+	github.com/dghubble/go-twitter v0.0.0-20211115160449-93a8679adecb
+	github.com/g8rswimmer/go-twitter/v2 v2.1.5
+	github.com/kylemcc/twitter-text-go v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/cenkalti/backoff/v4 v4.1.2 // indirect
+	github.com/dghubble/sling v1.4.0 // indirect
+	github.com/google/go-querystring v1.1.0 // indirect
+	golang.org/x/net v0.33.0 // indirect
+)
+
+replace github.com/kylemcc/twitter-text-go => ../
@@ -0,0 +1,80 @@
+// Package gotwitter converts between extracted entities and the entities
+// of tweets returned by the github.com/dghubble/go-twitter client, so that
+// entities stored from the API can be rendered alongside freshly extracted
+// ones:
+//
+//	entities := gotwitter.ToTwitterEntities(tweet.Text, tweet.Entities)
+//	html := autolink.AutoLinkWithEntities(tweet.Text, entities)
+//
+// The client's entities are those of version 1.1 of the Twitter API, whose
+// indices are offsets into the text in characters (code points).
+package gotwitter
+
+import (
+	"unicode/utf8"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// Converts a tweet's entities to extract entities, sorted by their position
+// in text. Media are converted to URL entities. Entities whose indices are
+// out of range or overlap a preceding entity are dropped. entities may be
+// nil
+func ToTwitterEntities(text string, entities *twitter.Entities) []*extract.TwitterEntity {
+	if entities == nil {
+		return nil
+	}
+
+	var result autolink.Entities
+	for _, h := range entities.Hashtags {
+		result.Hashtags = append(result.Hashtags, autolink.HashtagEntity{Text: h.Text, Indices: h.Indices})
+	}
+	for _, u := range entities.Urls {
+		result.Urls = append(result.Urls, urlEntity(u))
+	}
+	for _, m := range entities.Media {
+		result.Urls = append(result.Urls, urlEntity(m.URLEntity))
+	}
+	for _, m := range entities.UserMentions {
+		result.UserMentions = append(result.UserMentions, autolink.UserMentionEntity{
+			ScreenName: m.ScreenName,
+			Name:       m.Name,
+			Id:         m.ID,
+			IdStr:      m.IDStr,
+			Indices:    m.Indices,
+		})
+	}
+	return result.TwitterEntities(text)
+}
+
+func urlEntity(u twitter.URLEntity) autolink.UrlEntity {
+	return autolink.UrlEntity{Url: u.URL, DisplayUrl: u.DisplayURL, ExpandedUrl: u.ExpandedURL, Indices: u.Indices}
+}
+
+// Converts extract entities found in text to a tweet's entities. The
+// client has no cashtag entities, so cashtags are dropped, as are emoji. A
+// mention of a list becomes a mention of the list's owner
+func FromTwitterEntities(text string, entities []*extract.TwitterEntity) *twitter.Entities {
+	result := &twitter.Entities{}
+	for _, e := range entities {
+		indices := twitter.Indices{e.Range.Start, e.Range.Stop}
+		switch e.Type {
+		case extract.URL:
+			displayUrl, _ := e.DisplayUrl()
+			expandedUrl, _ := e.ExpandedUrl()
+			result.Urls = append(result.Urls, twitter.URLEntity{Indices: indices, URL: e.Text, DisplayURL: displayUrl, ExpandedURL: expandedUrl})
+		case extract.HASH_TAG:
+			hashtag, _ := e.Hashtag()
+			result.Hashtags = append(result.Hashtags, twitter.HashtagEntity{Indices: indices, Text: hashtag})
+		case extract.MENTION:
+			screenName, _ := e.ScreenName()
+			if listSlug, ok := e.ListSlug(); ok {
+				indices[1] -= utf8.RuneCountInString(listSlug)
+			}
+			result.UserMentions = append(result.UserMentions, twitter.MentionEntity{Indices: indices, ScreenName: screenName})
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package gotwitter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestToTwitterEntities(t *testing.T) {
+	text := "日本 @user #tag https://t.co/abc https://t.co/img"
+	entities := &twitter.Entities{
+		Hashtags:     []twitter.HashtagEntity{{Indices: twitter.Indices{9, 13}, Text: "tag"}},
+		Urls:         []twitter.URLEntity{{Indices: twitter.Indices{14, 30}, URL: "https://t.co/abc", DisplayURL: "example.com", ExpandedURL: "https://example.com"}},
+		Media:        []twitter.MediaEntity{{URLEntity: twitter.URLEntity{Indices: twitter.Indices{31, 47}, URL: "https://t.co/img"}}},
+		UserMentions: []twitter.MentionEntity{{Indices: twitter.Indices{3, 8}, ScreenName: "user"}, {Indices: twitter.Indices{40, 60}, ScreenName: "bad"}},
+	}
+
+	actual := ToTwitterEntities(text, entities)
+	expected := []string{"@user", "#tag", "https://t.co/abc", "https://t.co/img"}
+	if len(actual) != len(expected) {
+		t.Fatalf("ToTwitterEntities returned incorrect number of entities for text [%s]. Expected:%d Got:%d", text, len(expected), len(actual))
+	}
+	for i, e := range actual {
+		if e.Text != expected[i] {
+			t.Errorf("ToTwitterEntities returned incorrect entity %d for text [%s]. Expected:%s Got:%s", i, text, expected[i], e.Text)
+		}
+	}
+	if display, _ := actual[2].DisplayUrl(); display != "example.com" {
+		t.Errorf("ToTwitterEntities returned incorrect display URL. Expected:example.com Got:%s", display)
+	}
+
+	if actual := ToTwitterEntities(text, nil); actual != nil {
+		t.Errorf("ToTwitterEntities returned incorrect value for nil entities. Got:%v", actual)
+	}
+}
+
+func TestFromTwitterEntities(t *testing.T) {
+	text := "日本 @user/list #tag $TAG example.com"
+	expected := &twitter.Entities{
+		Hashtags:     []twitter.HashtagEntity{{Indices: twitter.Indices{14, 18}, Text: "tag"}},
+		Urls:         []twitter.URLEntity{{Indices: twitter.Indices{24, 35}, URL: "example.com"}},
+		UserMentions: []twitter.MentionEntity{{Indices: twitter.Indices{3, 8}, ScreenName: "user"}},
+	}
+	if actual := FromTwitterEntities(text, extract.ExtractEntities(text)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FromTwitterEntities returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+}
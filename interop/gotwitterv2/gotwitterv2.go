@@ -0,0 +1,74 @@
+// Package gotwitterv2 converts between extracted entities and the entities
+// of tweets returned by the github.com/g8rswimmer/go-twitter/v2 client, so
+// that entities stored from the API can be rendered alongside freshly
+// extracted ones:
+//
+//	entities := gotwitterv2.ToTwitterEntities(tweet.Text, tweet.Entities)
+//	html := autolink.AutoLinkWithEntities(tweet.Text, entities)
+//
+// The client's entities are those of version 2 of the Twitter API, and are
+// converted as in the apiv2 package.
+package gotwitterv2
+
+import (
+	"github.com/g8rswimmer/go-twitter/v2"
+	"github.com/kylemcc/twitter-text-go/apiv2"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// Converts a tweet's entities to extract entities, sorted by their position
+// in text. Entities whose offsets are out of range, split a character, or
+// overlap a preceding entity are dropped. entities may be nil
+func ToTwitterEntities(text string, entities *twitter.EntitiesObj) []*extract.TwitterEntity {
+	if entities == nil {
+		return nil
+	}
+
+	var result apiv2.Entities
+	for _, u := range entities.URLs {
+		result.Urls = append(result.Urls, apiv2.UrlEntity{
+			Start:       u.Start,
+			End:         u.End,
+			Url:         u.URL,
+			ExpandedUrl: u.ExpandedURL,
+			DisplayUrl:  u.DisplayURL,
+			UnwoundUrl:  u.UnwoundURL,
+		})
+	}
+	for _, h := range entities.HashTags {
+		result.Hashtags = append(result.Hashtags, apiv2.HashtagEntity{Start: h.Start, End: h.End, Tag: h.Tag})
+	}
+	for _, m := range entities.Mentions {
+		result.Mentions = append(result.Mentions, apiv2.MentionEntity{Start: m.Start, End: m.End, Username: m.UserName})
+	}
+	for _, c := range entities.CashTags {
+		result.Cashtags = append(result.Cashtags, apiv2.CashtagEntity{Start: c.Start, End: c.End, Tag: c.Tag})
+	}
+	return apiv2.ToTwitterEntities(text, result)
+}
+
+// Converts extract entities found in text to a tweet's entities. Emoji are
+// dropped, and a mention of a list becomes a mention of the list's owner
+func FromTwitterEntities(text string, entities []*extract.TwitterEntity) *twitter.EntitiesObj {
+	converted := apiv2.FromTwitterEntities(text, entities)
+
+	result := &twitter.EntitiesObj{}
+	for _, u := range converted.Urls {
+		result.URLs = append(result.URLs, twitter.EntityURLObj{
+			EntityObj:   twitter.EntityObj{Start: u.Start, End: u.End},
+			URL:         u.Url,
+			ExpandedURL: u.ExpandedUrl,
+			DisplayURL:  u.DisplayUrl,
+		})
+	}
+	for _, h := range converted.Hashtags {
+		result.HashTags = append(result.HashTags, twitter.EntityTagObj{EntityObj: twitter.EntityObj{Start: h.Start, End: h.End}, Tag: h.Tag})
+	}
+	for _, m := range converted.Mentions {
+		result.Mentions = append(result.Mentions, twitter.EntityMentionObj{EntityObj: twitter.EntityObj{Start: m.Start, End: m.End}, UserName: m.Username})
+	}
+	for _, c := range converted.Cashtags {
+		result.CashTags = append(result.CashTags, twitter.EntityTagObj{EntityObj: twitter.EntityObj{Start: c.Start, End: c.End}, Tag: c.Tag})
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package gotwitterv2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/g8rswimmer/go-twitter/v2"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestToTwitterEntities(t *testing.T) {
+	// The emoji is two UTF-16 code units
+	text := "\U0001F600 @user #tag $TAG https://t.co/abc"
+	entities := &twitter.EntitiesObj{
+		URLs:     []twitter.EntityURLObj{{EntityObj: twitter.EntityObj{Start: 19, End: 35}, URL: "https://t.co/abc", ExpandedURL: "https://example.com"}},
+		HashTags: []twitter.EntityTagObj{{EntityObj: twitter.EntityObj{Start: 9, End: 13}, Tag: "tag"}},
+		Mentions: []twitter.EntityMentionObj{{EntityObj: twitter.EntityObj{Start: 3, End: 8}, UserName: "user"}},
+		CashTags: []twitter.EntityTagObj{{EntityObj: twitter.EntityObj{Start: 14, End: 18}, Tag: "TAG"}},
+	}
+
+	actual := ToTwitterEntities(text, entities)
+	expected := []string{"@user", "#tag", "$TAG", "https://t.co/abc"}
+	if len(actual) != len(expected) {
+		t.Fatalf("ToTwitterEntities returned incorrect number of entities for text [%s]. Expected:%d Got:%d", text, len(expected), len(actual))
+	}
+	for i, e := range actual {
+		if e.Text != expected[i] {
+			t.Errorf("ToTwitterEntities returned incorrect entity %d for text [%s]. Expected:%s Got:%s", i, text, expected[i], e.Text)
+		}
+	}
+	if screenName, _ := actual[0].ScreenName(); screenName != "user" {
+		t.Errorf("ToTwitterEntities returned incorrect screen name. Expected:user Got:%s", screenName)
+	}
+
+	if actual := ToTwitterEntities(text, nil); actual != nil {
+		t.Errorf("ToTwitterEntities returned incorrect value for nil entities. Got:%v", actual)
+	}
+}
+
+func TestFromTwitterEntities(t *testing.T) {
+	text := "\U0001F600 @user/list #tag $TAG example.com"
+	expected := &twitter.EntitiesObj{
+		URLs:     []twitter.EntityURLObj{{EntityObj: twitter.EntityObj{Start: 24, End: 35}, URL: "example.com"}},
+		HashTags: []twitter.EntityTagObj{{EntityObj: twitter.EntityObj{Start: 14, End: 18}, Tag: "tag"}},
+		Mentions: []twitter.EntityMentionObj{{EntityObj: twitter.EntityObj{Start: 3, End: 8}, UserName: "user"}},
+		CashTags: []twitter.EntityTagObj{{EntityObj: twitter.EntityObj{Start: 19, End: 23}, Tag: "TAG"}},
+	}
+	if actual := FromTwitterEntities(text, extract.ExtractEntities(text)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("FromTwitterEntities returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+}
@@ -0,0 +1,181 @@
+// Package oteltext wraps the parsing, validation, extraction, and
+// auto-linking functions of the other packages with OpenTelemetry tracing
+// and metrics, so that services embedding them get spans and measurements
+// without writing their own wrappers:
+//
+//	in := oteltext.New()
+//	results := in.ParseTweet(ctx, text)
+//	entities := in.ExtractEntities(ctx, text)
+//
+// Each call starts a span named after the function, e.g.
+// "twitter-text.ParseTweet", as a child of any span in ctx, and records its
+// duration in the twtext.duration histogram and, for extraction, the
+// number of entities found in the twtext.entities histogram. Both are
+// recorded with the twtext.operation attribute naming the function.
+//
+// Spans hold the length of the text in bytes and the results of the call,
+// but never the text itself, which may be private.
+//
+// The global providers are used unless others are given with
+// WithTracerProvider and WithMeterProvider.
+package oteltext
+
+import (
+	"context"
+	"time"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/validate"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The name of the instrumentation scope of the tracer and meter
+const ScopeName = "github.com/kylemcc/twitter-text-go/oteltext"
+
+// The attributes recorded on spans and measurements
+const (
+	OperationKey      = attribute.Key("twtext.operation")
+	TextLengthKey     = attribute.Key("twtext.text.length")
+	WeightedLengthKey = attribute.Key("twtext.weighted_length")
+	ValidKey          = attribute.Key("twtext.valid")
+	EntityCountKey    = attribute.Key("twtext.entity.count")
+)
+
+// Instruments calls to the functions of the other packages. It is safe for
+// concurrent use
+type Instrumentation struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	entities metric.Int64Histogram
+}
+
+// An Option customizes an Instrumentation
+type Option func(*Instrumentation)
+
+// Creates spans with tp rather than the global tracer provider
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(in *Instrumentation) {
+		in.tracerProvider = tp
+	}
+}
+
+// Records metrics with mp rather than the global meter provider
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(in *Instrumentation) {
+		in.meterProvider = mp
+	}
+}
+
+// Returns an Instrumentation using the global providers unless others are
+// given. Instruments that cannot be created are replaced by ones that
+// record nothing, and the error is reported to the global error handler
+func New(opts ...Option) *Instrumentation {
+	in := &Instrumentation{}
+	for _, opt := range opts {
+		opt(in)
+	}
+	if in.tracerProvider == nil {
+		in.tracerProvider = otel.GetTracerProvider()
+	}
+	if in.meterProvider == nil {
+		in.meterProvider = otel.GetMeterProvider()
+	}
+
+	in.tracer = in.tracerProvider.Tracer(ScopeName)
+	meter := in.meterProvider.Meter(ScopeName)
+
+	var err error
+	in.duration, err = meter.Float64Histogram("twtext.duration",
+		metric.WithDescription("The duration of calls to twitter-text functions"),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	in.entities, err = meter.Int64Histogram("twtext.entities",
+		metric.WithDescription("The number of entities extracted from a text"),
+		metric.WithUnit("{entity}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return in
+}
+
+// Starts a span for the named operation on text. The returned function
+// ends it, recording the duration and attrs
+func (in *Instrumentation) start(ctx context.Context, operation, text string) (context.Context, func(attrs ...attribute.KeyValue)) {
+	start := time.Now()
+	ctx, span := in.tracer.Start(ctx, "twitter-text."+operation,
+		trace.WithAttributes(OperationKey.String(operation), TextLengthKey.Int(len(text))))
+	return ctx, func(attrs ...attribute.KeyValue) {
+		span.SetAttributes(attrs...)
+		span.End()
+		in.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(OperationKey.String(operation)))
+	}
+}
+
+// Calls validate.ParseTweet
+func (in *Instrumentation) ParseTweet(ctx context.Context, text string, opts ...validate.Option) validate.ParseResults {
+	_, end := in.start(ctx, "ParseTweet", text)
+	results := validate.ParseTweet(text, opts...)
+	end(WeightedLengthKey.Int(results.WeightedLength), ValidKey.Bool(results.IsValid))
+	return results
+}
+
+// Calls validate.ValidateTweet. An invalid text is not an error of the
+// span; the span records whether the text is valid
+func (in *Instrumentation) ValidateTweet(ctx context.Context, text string, opts ...validate.Option) error {
+	_, end := in.start(ctx, "ValidateTweet", text)
+	err := validate.ValidateTweet(text, opts...)
+	end(ValidKey.Bool(err == nil))
+	return err
+}
+
+// Calls extract.ExtractEntities
+func (in *Instrumentation) ExtractEntities(ctx context.Context, text string) []*extract.TwitterEntity {
+	return in.extract(ctx, "ExtractEntities", text, extract.ExtractEntities)
+}
+
+// Calls extract.ExtractUrls
+func (in *Instrumentation) ExtractUrls(ctx context.Context, text string) []*extract.TwitterEntity {
+	return in.extract(ctx, "ExtractUrls", text, extract.ExtractUrls)
+}
+
+// Calls extract.ExtractMentionsOrLists
+func (in *Instrumentation) ExtractMentionsOrLists(ctx context.Context, text string) []*extract.TwitterEntity {
+	return in.extract(ctx, "ExtractMentionsOrLists", text, extract.ExtractMentionsOrLists)
+}
+
+// Calls extract.ExtractHashtags
+func (in *Instrumentation) ExtractHashtags(ctx context.Context, text string) []*extract.TwitterEntity {
+	return in.extract(ctx, "ExtractHashtags", text, extract.ExtractHashtags)
+}
+
+// Calls extract.ExtractCashtags
+func (in *Instrumentation) ExtractCashtags(ctx context.Context, text string) []*extract.TwitterEntity {
+	return in.extract(ctx, "ExtractCashtags", text, extract.ExtractCashtags)
+}
+
+// Calls f, the named extraction function, recording the number of
+// entities it finds
+func (in *Instrumentation) extract(ctx context.Context, operation, text string, f func(string) []*extract.TwitterEntity) []*extract.TwitterEntity {
+	ctx, end := in.start(ctx, operation, text)
+	entities := f(text)
+	in.entities.Record(ctx, int64(len(entities)), metric.WithAttributes(OperationKey.String(operation)))
+	end(EntityCountKey.Int(len(entities)))
+	return entities
+}
+
+// Calls autolink.AutoLink
+func (in *Instrumentation) AutoLink(ctx context.Context, text string, opts ...autolink.Option) string {
+	_, end := in.start(ctx, "AutoLink", text)
+	result := autolink.AutoLink(text, opts...)
+	end()
+	return result
+}
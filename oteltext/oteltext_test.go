@@ -0,0 +1,187 @@
+package oteltext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/validate"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Returns an Instrumentation recording to the returned recorder and reader
+func newTestInstrumentation() (*Instrumentation, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	in := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	return in, spans, reader
+}
+
+// Returns the attributes of a span as a map
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	result := map[attribute.Key]attribute.Value{}
+	for _, kv := range kvs {
+		result[kv.Key] = kv.Value
+	}
+	return result
+}
+
+func TestResults(t *testing.T) {
+	in, _, _ := newTestInstrumentation()
+	ctx := context.Background()
+	text := "Hello @world #golang http://example.com $GO"
+
+	if expected, actual := validate.ParseTweet(text), in.ParseTweet(ctx, text); actual != expected {
+		t.Errorf("ParseTweet returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+	if expected, actual := validate.ValidateTweet(""), in.ValidateTweet(ctx, ""); actual != expected {
+		t.Errorf("ValidateTweet returned incorrect value for an empty text. Expected:%v Got:%v", expected, actual)
+	}
+	if expected, actual := autolink.AutoLink(text), in.AutoLink(ctx, text); actual != expected {
+		t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:%s Got:%s", text, expected, actual)
+	}
+
+	extractors := []struct {
+		name     string
+		expected func(string) []*extract.TwitterEntity
+		actual   func(context.Context, string) []*extract.TwitterEntity
+	}{
+		{"ExtractEntities", extract.ExtractEntities, in.ExtractEntities},
+		{"ExtractUrls", extract.ExtractUrls, in.ExtractUrls},
+		{"ExtractMentionsOrLists", extract.ExtractMentionsOrLists, in.ExtractMentionsOrLists},
+		{"ExtractHashtags", extract.ExtractHashtags, in.ExtractHashtags},
+		{"ExtractCashtags", extract.ExtractCashtags, in.ExtractCashtags},
+	}
+	for _, e := range extractors {
+		expected, actual := e.expected(text), e.actual(ctx, text)
+		if len(actual) != len(expected) {
+			t.Errorf("%s returned incorrect number of entities for text [%s]. Expected:%d Got:%d", e.name, text, len(expected), len(actual))
+			continue
+		}
+		for i := range actual {
+			if *actual[i] != *expected[i] {
+				t.Errorf("%s returned incorrect entity for text [%s]. Expected:%+v Got:%+v", e.name, text, expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	in, spans, _ := newTestInstrumentation()
+	text := "Hello @world #golang"
+
+	ctx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "parent")
+	in.ParseTweet(ctx, text)
+	in.ValidateTweet(ctx, "")
+	in.ExtractEntities(ctx, text)
+	parent.End()
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("Expected 3 spans. Got:%d", len(ended))
+	}
+	tests := []struct {
+		name     string
+		expected map[attribute.Key]attribute.Value
+	}{
+		{"twitter-text.ParseTweet", map[attribute.Key]attribute.Value{
+			OperationKey:      attribute.StringValue("ParseTweet"),
+			TextLengthKey:     attribute.IntValue(len(text)),
+			WeightedLengthKey: attribute.IntValue(20),
+			ValidKey:          attribute.BoolValue(true),
+		}},
+		{"twitter-text.ValidateTweet", map[attribute.Key]attribute.Value{
+			OperationKey:  attribute.StringValue("ValidateTweet"),
+			TextLengthKey: attribute.IntValue(0),
+			ValidKey:      attribute.BoolValue(false),
+		}},
+		{"twitter-text.ExtractEntities", map[attribute.Key]attribute.Value{
+			OperationKey:   attribute.StringValue("ExtractEntities"),
+			TextLengthKey:  attribute.IntValue(len(text)),
+			EntityCountKey: attribute.IntValue(2),
+		}},
+	}
+	for i, test := range tests {
+		span := ended[i]
+		if span.Name() != test.name {
+			t.Errorf("Span %d has incorrect name. Expected:%s Got:%s", i, test.name, span.Name())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Span %s is not a child of the span in its context", span.Name())
+		}
+		actual := attributes(span.Attributes())
+		if len(actual) != len(test.expected) {
+			t.Errorf("Span %s has incorrect attributes. Expected:%v Got:%v", span.Name(), test.expected, actual)
+			continue
+		}
+		for key, value := range test.expected {
+			if actual[key] != value {
+				t.Errorf("Span %s has incorrect value for %s. Expected:%v Got:%v", span.Name(), key, value.Emit(), actual[key].Emit())
+			}
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	in, _, reader := newTestInstrumentation()
+	ctx := context.Background()
+	in.ExtractEntities(ctx, "@a @b #c")
+	in.ExtractEntities(ctx, "none")
+	in.ExtractHashtags(ctx, "#a")
+	in.ParseTweet(ctx, "text")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Error collecting metrics: %v", err)
+	}
+	if len(rm.ScopeMetrics) != 1 || rm.ScopeMetrics[0].Scope.Name != ScopeName {
+		t.Fatalf("Expected metrics in scope %s. Got:%+v", ScopeName, rm.ScopeMetrics)
+	}
+
+	// The count and sum of each histogram, by operation
+	type point struct {
+		count uint64
+		sum   float64
+	}
+	actual := map[string]map[string]point{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		points := map[string]point{}
+		switch data := m.Data.(type) {
+		case metricdata.Histogram[float64]:
+			for _, p := range data.DataPoints {
+				operation, _ := p.Attributes.Value(OperationKey)
+				points[operation.AsString()] = point{p.Count, -1}
+			}
+		case metricdata.Histogram[int64]:
+			for _, p := range data.DataPoints {
+				operation, _ := p.Attributes.Value(OperationKey)
+				points[operation.AsString()] = point{p.Count, float64(p.Sum)}
+			}
+		}
+		actual[m.Name] = points
+	}
+
+	expected := map[string]map[string]point{
+		"twtext.duration": {"ExtractEntities": {2, -1}, "ExtractHashtags": {1, -1}, "ParseTweet": {1, -1}},
+		"twtext.entities": {"ExtractEntities": {2, 3}, "ExtractHashtags": {1, 1}},
+	}
+	for name, points := range expected {
+		if len(actual[name]) != len(points) {
+			t.Errorf("Metric %s has incorrect data points. Expected:%v Got:%v", name, points, actual[name])
+			continue
+		}
+		for operation, p := range points {
+			if actual[name][operation] != p {
+				t.Errorf("Metric %s has incorrect data point for %s. Expected:%+v Got:%+v", name, operation, p, actual[name][operation])
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package regexpengine_test
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/regexpengine"
+)
+
+// An engine that compiles patterns with the regexp package and counts
+// them. An adapter for another engine would translate the pattern and
+// wrap the result
+type countingEngine struct {
+	compiled int32
+}
+
+func (e *countingEngine) Compile(pattern string) (regexpengine.Matcher, error) {
+	atomic.AddInt32(&e.compiled, 1)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+func ExampleSet() {
+	e := &countingEngine{}
+	regexpengine.Set(e)
+	defer regexpengine.Set(nil)
+
+	if reply := extract.ExtractReplyScreenname("@twitter hello"); reply != nil {
+		fmt.Println(reply.Text)
+	}
+	fmt.Println(atomic.LoadInt32(&e.compiled) > 0)
+	// Output:
+	// @twitter
+	// true
+}
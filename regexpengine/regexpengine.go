@@ -0,0 +1,79 @@
+// Package regexpengine selects the regular expression engine that matches
+// the patterns ported from the twitter-text libraries, which the extract
+// and validate packages use to find and check entities.
+//
+// The default engine is the standard regexp package, which matches in time
+// linear in the length of the text. The patterns were written for
+// JavaScript's engine, though, and a few of them rely on features such as
+// lookbehind that RE2 lacks and that the Go code around them emulates.
+// Set allows an alternative engine to be used instead, e.g. an adapter for
+// a backtracking engine that matches them more faithfully, or one that is
+// faster for a particular workload, trading away the linear time
+// guarantee.
+package regexpengine
+
+import (
+	"regexp"
+	"sync/atomic"
+)
+
+// A compiled pattern. Indices are byte offsets into s, as returned by the
+// methods of regexp.Regexp with the same names, which a *regexp.Regexp
+// implements
+type Matcher interface {
+	MatchString(s string) bool
+	FindStringIndex(s string) []int
+	FindStringSubmatchIndex(s string) []int
+	FindAllStringSubmatchIndex(s string, n int) [][]int
+}
+
+// Compiles patterns into Matchers. Patterns use the syntax of the regexp
+// package, and an engine must match them with the same leftmost-first
+// semantics and number the same submatches for the results of extraction
+// and validation to be correct
+type Engine interface {
+	Compile(pattern string) (Matcher, error)
+}
+
+// The regexp package, which is the default engine
+var Standard Engine = standard{}
+
+type standard struct{}
+
+func (standard) Compile(pattern string) (Matcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return re, nil
+}
+
+// Holds the current engine. An atomic.Value requires every value stored
+// in it to have the same concrete type
+type engineValue struct {
+	Engine
+}
+
+var current atomic.Value
+
+// Selects the engine that compiles patterns from now on. Passing nil
+// selects Standard.
+//
+// Each pattern is compiled the first time it is used, and keeps using the
+// engine that compiled it, so Set should be called before any text is
+// extracted or validated, e.g. from an init function or at the start of
+// main.
+func Set(e Engine) {
+	if e == nil {
+		e = Standard
+	}
+	current.Store(engineValue{e})
+}
+
+// Returns the engine selected by Set, or Standard if none has been
+func Get() Engine {
+	if v, ok := current.Load().(engineValue); ok {
+		return v.Engine
+	}
+	return Standard
+}
@@ -0,0 +1,56 @@
+package regexpengine
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// An engine that compiles patterns with Standard and counts them
+type countingEngine struct {
+	compiled int32
+}
+
+func (e *countingEngine) Compile(pattern string) (Matcher, error) {
+	atomic.AddInt32(&e.compiled, 1)
+	return Standard.Compile(pattern)
+}
+
+func TestSet(t *testing.T) {
+	defer Set(nil)
+
+	if Get() != Standard {
+		t.Errorf("Get returned incorrect value before Set was called. Expected:Standard Got:%v", Get())
+	}
+
+	e := &countingEngine{}
+	Set(e)
+	if Get() != e {
+		t.Errorf("Get returned incorrect value. Expected:%v Got:%v", e, Get())
+	}
+	if _, err := Get().Compile(`a+`); err != nil || e.compiled != 1 {
+		t.Errorf("Compile did not use the engine passed to Set: %v", err)
+	}
+
+	Set(nil)
+	if Get() != Standard {
+		t.Errorf("Get returned incorrect value after Set(nil). Expected:Standard Got:%v", Get())
+	}
+}
+
+func TestStandard(t *testing.T) {
+	m, err := Standard.Compile(`(a+)(b)?`)
+	if err != nil {
+		t.Fatalf("Compile returned an error: %v", err)
+	}
+	if !m.MatchString("xaab") {
+		t.Errorf("MatchString returned false for a matching string")
+	}
+	if loc := m.FindStringSubmatchIndex("xaab"); !reflect.DeepEqual(loc, []int{1, 4, 1, 3, 3, 4}) {
+		t.Errorf("FindStringSubmatchIndex returned incorrect value. Expected:[1 4 1 3 3 4] Got:%v", loc)
+	}
+
+	if m, err := Standard.Compile(`(`); err == nil || m != nil {
+		t.Errorf("Compile returned incorrect value for an invalid pattern. Expected:<nil>, error Got:%v, %v", m, err)
+	}
+}
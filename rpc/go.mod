@@ -0,0 +1,20 @@
+module github.com/kylemcc/twitter-text-go/rpc
+
+go 1.19
+
+require (
+	github.com/kylemcc/twitter-text-go v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+)
+
+replace github.com/kylemcc/twitter-text-go => ../
@@ -0,0 +1,114 @@
+// Package rpc implements the TwitterText gRPC service defined in
+// twtextpb/twtext.proto, for services that standardize on gRPC rather than
+// the JSON endpoints of twtextd. Register it with a gRPC server:
+//
+//	s := grpc.NewServer()
+//	twtextpb.RegisterTwitterTextServer(s, rpc.NewServer(config.V3()))
+//
+// The twtextgrpc command serves it.
+package rpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/rpc/twtextpb"
+	"github.com/kylemcc/twitter-text-go/textpb"
+	"github.com/kylemcc/twitter-text-go/validate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Returns a server that weighs texts under c unless a request names a
+// preset. c must not be modified while the server is in use
+func NewServer(c *config.Config) twtextpb.TwitterTextServer {
+	return &server{config: c}
+}
+
+type server struct {
+	twtextpb.UnimplementedTwitterTextServer
+	config *config.Config
+}
+
+func (s *server) Parse(ctx context.Context, req *twtextpb.ParseRequest) (*twtextpb.ParseResponse, error) {
+	c, err := s.configFor(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	return &twtextpb.ParseResponse{Results: textpb.NewParseResults(validate.ParseTweetWithConfig(req.Text, c))}, nil
+}
+
+func (s *server) Validate(ctx context.Context, req *twtextpb.ValidateRequest) (*twtextpb.ValidateResponse, error) {
+	c, err := s.configFor(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate.ValidateTweetWithConfig(req.Text, c); err != nil {
+		return &twtextpb.ValidateResponse{Valid: false, Error: err.Error()}, nil
+	}
+	return &twtextpb.ValidateResponse{Valid: true}, nil
+}
+
+// The extraction function for each type of entity
+var extractors = map[textpb.Entity_Type]func(string) []*extract.TwitterEntity{
+	textpb.Entity_URL:     extract.ExtractUrls,
+	textpb.Entity_HASHTAG: extract.ExtractHashtags,
+	textpb.Entity_MENTION: extract.ExtractMentionsOrLists,
+	textpb.Entity_CASHTAG: extract.ExtractCashtags,
+	textpb.Entity_EMOJI:   extract.ExtractEmoji,
+}
+
+func (s *server) Extract(ctx context.Context, req *twtextpb.ExtractRequest) (*twtextpb.ExtractResponse, error) {
+	var entities []*extract.TwitterEntity
+	if len(req.Types) == 0 {
+		entities = extract.ExtractEntities(req.Text)
+	}
+	seen := map[textpb.Entity_Type]bool{}
+	for _, t := range req.Types {
+		extractor, ok := extractors[t]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid entity type %v", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			entities = append(entities, extractor(req.Text)...)
+		}
+	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].Range.Start < entities[j].Range.Start
+	})
+
+	return &twtextpb.ExtractResponse{Entities: textpb.NewEntities(entities)}, nil
+}
+
+func (s *server) Autolink(ctx context.Context, req *twtextpb.AutolinkRequest) (*twtextpb.AutolinkResponse, error) {
+	var text string
+	switch req.Render {
+	case twtextpb.AutolinkRequest_HTML:
+		text = autolink.AutoLink(req.Text)
+	case twtextpb.AutolinkRequest_ANSI:
+		text = autolink.RenderANSI(req.Text, extract.ExtractEntities(req.Text))
+	case twtextpb.AutolinkRequest_MARKDOWN:
+		text = autolink.RenderMarkdown(req.Text, extract.ExtractEntities(req.Text))
+	case twtextpb.AutolinkRequest_SLACK:
+		text = autolink.RenderSlack(req.Text, extract.ExtractEntities(req.Text))
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid rendering %v", req.Render)
+	}
+	return &twtextpb.AutolinkResponse{Text: text}, nil
+}
+
+// Returns the named preset, or the server's configuration if name is empty
+func (s *server) configFor(name string) (*config.Config, error) {
+	if name == "" {
+		return s.config, nil
+	}
+	c, err := config.Preset(name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return c, nil
+}
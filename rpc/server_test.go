@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/rpc/twtextpb"
+	"github.com/kylemcc/twitter-text-go/textpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParse(t *testing.T) {
+	s := NewServer(config.V3())
+	tests := []struct {
+		req      *twtextpb.ParseRequest
+		expected *textpb.ParseResults
+	}{
+		{&twtextpb.ParseRequest{Text: "Hello @world"}, &textpb.ParseResults{WeightedLength: 12, Permillage: 42, Valid: true}},
+		{&twtextpb.ParseRequest{Text: "日本", Config: "twitter-v1"}, &textpb.ParseResults{WeightedLength: 2, Permillage: 14, Valid: true}},
+		{&twtextpb.ParseRequest{Text: ""}, &textpb.ParseResults{}},
+	}
+
+	for _, test := range tests {
+		resp, err := s.Parse(context.Background(), test.req)
+		if err != nil || !proto.Equal(resp.Results, test.expected) {
+			t.Errorf("Parse returned incorrect value for request [%v]. Expected:%v Got:%v %v", test.req, test.expected, resp, err)
+		}
+	}
+
+	if _, err := s.Parse(context.Background(), &twtextpb.ParseRequest{Text: "Hello", Config: "unknown"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Parse returned incorrect error for an unknown preset. Expected:%v Got:%v", codes.InvalidArgument, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := NewServer(config.V3())
+	tests := []struct {
+		text     string
+		expected *twtextpb.ValidateResponse
+	}{
+		{"Hello", &twtextpb.ValidateResponse{Valid: true}},
+		{"", &twtextpb.ValidateResponse{Error: "Tweets may not be empty"}},
+	}
+
+	for _, test := range tests {
+		resp, err := s.Validate(context.Background(), &twtextpb.ValidateRequest{Text: test.text})
+		if err != nil || !proto.Equal(resp, test.expected) {
+			t.Errorf("Validate returned incorrect value for text [%s]. Expected:%v Got:%v %v", test.text, test.expected, resp, err)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	s := NewServer(config.V3())
+	text := "#a @b/c $D http://e.com \U0001F600"
+	hashtag := &textpb.Entity{Type: textpb.Entity_HASHTAG, Text: "#a", Start: 0, End: 2, Hashtag: "a"}
+	mention := &textpb.Entity{Type: textpb.Entity_MENTION, Text: "@b/c", Start: 3, End: 7, ScreenName: "b", ListSlug: "/c"}
+	cashtag := &textpb.Entity{Type: textpb.Entity_CASHTAG, Text: "$D", Start: 8, End: 10, Cashtag: "D"}
+	url := &textpb.Entity{Type: textpb.Entity_URL, Text: "http://e.com", Start: 11, End: 23}
+	emoji := &textpb.Entity{Type: textpb.Entity_EMOJI, Text: "\U0001F600", Start: 24, End: 25}
+
+	tests := []struct {
+		types    []textpb.Entity_Type
+		expected []*textpb.Entity
+	}{
+		{nil, []*textpb.Entity{hashtag, mention, cashtag, url}},
+		{[]textpb.Entity_Type{textpb.Entity_MENTION}, []*textpb.Entity{mention}},
+		{[]textpb.Entity_Type{textpb.Entity_EMOJI, textpb.Entity_HASHTAG, textpb.Entity_EMOJI}, []*textpb.Entity{hashtag, emoji}},
+	}
+
+	for _, test := range tests {
+		resp, err := s.Extract(context.Background(), &twtextpb.ExtractRequest{Text: text, Types: test.types})
+		if err != nil || !proto.Equal(resp, &twtextpb.ExtractResponse{Entities: test.expected}) {
+			t.Errorf("Extract returned incorrect value for types %v. Expected:%v Got:%v %v", test.types, test.expected, resp, err)
+		}
+	}
+
+	if _, err := s.Extract(context.Background(), &twtextpb.ExtractRequest{Text: text, Types: []textpb.Entity_Type{textpb.Entity_TYPE_UNSPECIFIED}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Extract returned incorrect error for an unspecified type. Expected:%v Got:%v", codes.InvalidArgument, err)
+	}
+}
+
+func TestAutolink(t *testing.T) {
+	s := NewServer(config.V3())
+	tests := []struct {
+		req      *twtextpb.AutolinkRequest
+		expected string
+	}{
+		{&twtextpb.AutolinkRequest{Text: "@user"}, `@<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`},
+		{&twtextpb.AutolinkRequest{Text: "@user", Render: twtextpb.AutolinkRequest_MARKDOWN}, "[@user](https://twitter.com/user)"},
+	}
+
+	for _, test := range tests {
+		resp, err := s.Autolink(context.Background(), test.req)
+		if err != nil || resp.Text != test.expected {
+			t.Errorf("Autolink returned incorrect value for request [%v]. Expected:%s Got:%v %v", test.req, test.expected, resp, err)
+		}
+	}
+
+	if _, err := s.Autolink(context.Background(), &twtextpb.AutolinkRequest{Text: "@user", Render: 99}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Autolink returned incorrect error for an unknown rendering. Expected:%v Got:%v", codes.InvalidArgument, err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	twtextpb.RegisterTwitterTextServer(s, NewServer(config.V3()))
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp, err := twtextpb.NewTwitterTextClient(conn).Parse(context.Background(), &twtextpb.ParseRequest{Text: "Hello @world"})
+	expected := &textpb.ParseResults{WeightedLength: 12, Permillage: 42, Valid: true}
+	if err != nil || !proto.Equal(resp.GetResults(), expected) {
+		t.Errorf("Parse returned incorrect value over gRPC. Expected:%v Got:%v %v", expected, resp, err)
+	}
+}
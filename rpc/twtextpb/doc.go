@@ -0,0 +1,8 @@
+// Package twtextpb holds the request and response messages and the gRPC
+// client and server interfaces of the TwitterText service defined in
+// twtext.proto. The entity and parse result messages are defined in the
+// textpb package. See the rpc package for the implementation of the
+// server.
+package twtextpb
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative rpc/twtextpb/twtext.proto
@@ -0,0 +1,738 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: rpc/twtextpb/twtext.proto
+
+package twtextpb
+
+import (
+	textpb "github.com/kylemcc/twitter-text-go/textpb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AutolinkRequest_Rendering int32
+
+const (
+	AutolinkRequest_HTML     AutolinkRequest_Rendering = 0
+	AutolinkRequest_ANSI     AutolinkRequest_Rendering = 1
+	AutolinkRequest_MARKDOWN AutolinkRequest_Rendering = 2
+	AutolinkRequest_SLACK    AutolinkRequest_Rendering = 3
+)
+
+// Enum value maps for AutolinkRequest_Rendering.
+var (
+	AutolinkRequest_Rendering_name = map[int32]string{
+		0: "HTML",
+		1: "ANSI",
+		2: "MARKDOWN",
+		3: "SLACK",
+	}
+	AutolinkRequest_Rendering_value = map[string]int32{
+		"HTML":     0,
+		"ANSI":     1,
+		"MARKDOWN": 2,
+		"SLACK":    3,
+	}
+)
+
+func (x AutolinkRequest_Rendering) Enum() *AutolinkRequest_Rendering {
+	p := new(AutolinkRequest_Rendering)
+	*p = x
+	return p
+}
+
+func (x AutolinkRequest_Rendering) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AutolinkRequest_Rendering) Descriptor() protoreflect.EnumDescriptor {
+	return file_rpc_twtextpb_twtext_proto_enumTypes[0].Descriptor()
+}
+
+func (AutolinkRequest_Rendering) Type() protoreflect.EnumType {
+	return &file_rpc_twtextpb_twtext_proto_enumTypes[0]
+}
+
+func (x AutolinkRequest_Rendering) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AutolinkRequest_Rendering.Descriptor instead.
+func (AutolinkRequest_Rendering) EnumDescriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{6, 0}
+}
+
+type ParseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// The preset to weigh the text under, such as "twitter-v3". The server's
+	// default configuration is used if it is empty
+	Config string `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ParseRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type ParseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results *textpb.ParseResults `protobuf:"bytes,1,opt,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ParseResponse) GetResults() *textpb.ParseResults {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// As in ParseRequest
+	Config string `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Why the text is invalid, if it is
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ExtractRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// The types of entities to return. If empty, the URLs, hashtags,
+	// mentions, and cashtags are returned
+	Types []textpb.Entity_Type `protobuf:"varint,2,rep,packed,name=types,proto3,enum=twtext.v1.Entity_Type" json:"types,omitempty"`
+}
+
+func (x *ExtractRequest) Reset() {
+	*x = ExtractRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtractRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractRequest) ProtoMessage() {}
+
+func (x *ExtractRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractRequest.ProtoReflect.Descriptor instead.
+func (*ExtractRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExtractRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ExtractRequest) GetTypes() []textpb.Entity_Type {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type ExtractResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The entities, in the order they appear in the text
+	Entities []*textpb.Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *ExtractResponse) Reset() {
+	*x = ExtractResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtractResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtractResponse) ProtoMessage() {}
+
+func (x *ExtractResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtractResponse.ProtoReflect.Descriptor instead.
+func (*ExtractResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExtractResponse) GetEntities() []*textpb.Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+type AutolinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text   string                    `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Render AutolinkRequest_Rendering `protobuf:"varint,2,opt,name=render,proto3,enum=twtext.v1.AutolinkRequest_Rendering" json:"render,omitempty"`
+}
+
+func (x *AutolinkRequest) Reset() {
+	*x = AutolinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutolinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutolinkRequest) ProtoMessage() {}
+
+func (x *AutolinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutolinkRequest.ProtoReflect.Descriptor instead.
+func (*AutolinkRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AutolinkRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *AutolinkRequest) GetRender() AutolinkRequest_Rendering {
+	if x != nil {
+		return x.Render
+	}
+	return AutolinkRequest_HTML
+}
+
+type AutolinkResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The text with its entities rendered as links
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *AutolinkResponse) Reset() {
+	*x = AutolinkResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_twtextpb_twtext_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutolinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutolinkResponse) ProtoMessage() {}
+
+func (x *AutolinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_twtextpb_twtext_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutolinkResponse.ProtoReflect.Descriptor instead.
+func (*AutolinkResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_twtextpb_twtext_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AutolinkResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+var File_rpc_twtextpb_twtext_proto protoreflect.FileDescriptor
+
+var file_rpc_twtextpb_twtext_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x72, 0x70, 0x63, 0x2f, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x70, 0x62, 0x2f, 0x74,
+	0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x74, 0x77, 0x74,
+	0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x11, 0x74, 0x65, 0x78, 0x74, 0x70, 0x62, 0x2f, 0x74,
+	0x65, 0x78, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x3a, 0x0a, 0x0c, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x42, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x3d, 0x0a, 0x0f, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x3e, 0x0a, 0x10, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x52, 0x0a, 0x0e, 0x45, 0x78, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x2c,
+	0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x16, 0x2e,
+	0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x22, 0x40, 0x0a, 0x0f,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2d, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x9d,
+	0x01, 0x0a, 0x0f, 0x41, 0x75, 0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x3c, 0x0a, 0x06, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x24, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x2e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x06, 0x72, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x22, 0x38, 0x0a, 0x09, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x69, 0x6e,
+	0x67, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x54, 0x4d, 0x4c, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x41,
+	0x4e, 0x53, 0x49, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x41, 0x52, 0x4b, 0x44, 0x4f, 0x57,
+	0x4e, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x4c, 0x41, 0x43, 0x4b, 0x10, 0x03, 0x22, 0x26,
+	0x0a, 0x10, 0x41, 0x75, 0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x32, 0x95, 0x02, 0x0a, 0x0b, 0x54, 0x77, 0x69, 0x74, 0x74,
+	0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x12, 0x3a, 0x0a, 0x05, 0x50, 0x61, 0x72, 0x73, 0x65, 0x12,
+	0x17, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78,
+	0x74, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x43, 0x0a, 0x08, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1a,
+	0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x77, 0x74,
+	0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x07, 0x45, 0x78, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x12, 0x19, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45,
+	0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
+	0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x63,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x08, 0x41, 0x75, 0x74,
+	0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x12, 0x1a, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75,
+	0x74, 0x6f, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31,
+	0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x79, 0x6c,
+	0x65, 0x6d, 0x63, 0x63, 0x2f, 0x74, 0x77, 0x69, 0x74, 0x74, 0x65, 0x72, 0x2d, 0x74, 0x65, 0x78,
+	0x74, 0x2d, 0x67, 0x6f, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_rpc_twtextpb_twtext_proto_rawDescOnce sync.Once
+	file_rpc_twtextpb_twtext_proto_rawDescData = file_rpc_twtextpb_twtext_proto_rawDesc
+)
+
+func file_rpc_twtextpb_twtext_proto_rawDescGZIP() []byte {
+	file_rpc_twtextpb_twtext_proto_rawDescOnce.Do(func() {
+		file_rpc_twtextpb_twtext_proto_rawDescData = protoimpl.X.CompressGZIP(file_rpc_twtextpb_twtext_proto_rawDescData)
+	})
+	return file_rpc_twtextpb_twtext_proto_rawDescData
+}
+
+var file_rpc_twtextpb_twtext_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_rpc_twtextpb_twtext_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_rpc_twtextpb_twtext_proto_goTypes = []interface{}{
+	(AutolinkRequest_Rendering)(0), // 0: twtext.v1.AutolinkRequest.Rendering
+	(*ParseRequest)(nil),           // 1: twtext.v1.ParseRequest
+	(*ParseResponse)(nil),          // 2: twtext.v1.ParseResponse
+	(*ValidateRequest)(nil),        // 3: twtext.v1.ValidateRequest
+	(*ValidateResponse)(nil),       // 4: twtext.v1.ValidateResponse
+	(*ExtractRequest)(nil),         // 5: twtext.v1.ExtractRequest
+	(*ExtractResponse)(nil),        // 6: twtext.v1.ExtractResponse
+	(*AutolinkRequest)(nil),        // 7: twtext.v1.AutolinkRequest
+	(*AutolinkResponse)(nil),       // 8: twtext.v1.AutolinkResponse
+	(*textpb.ParseResults)(nil),    // 9: twtext.v1.ParseResults
+	(textpb.Entity_Type)(0),        // 10: twtext.v1.Entity.Type
+	(*textpb.Entity)(nil),          // 11: twtext.v1.Entity
+}
+var file_rpc_twtextpb_twtext_proto_depIdxs = []int32{
+	9,  // 0: twtext.v1.ParseResponse.results:type_name -> twtext.v1.ParseResults
+	10, // 1: twtext.v1.ExtractRequest.types:type_name -> twtext.v1.Entity.Type
+	11, // 2: twtext.v1.ExtractResponse.entities:type_name -> twtext.v1.Entity
+	0,  // 3: twtext.v1.AutolinkRequest.render:type_name -> twtext.v1.AutolinkRequest.Rendering
+	1,  // 4: twtext.v1.TwitterText.Parse:input_type -> twtext.v1.ParseRequest
+	3,  // 5: twtext.v1.TwitterText.Validate:input_type -> twtext.v1.ValidateRequest
+	5,  // 6: twtext.v1.TwitterText.Extract:input_type -> twtext.v1.ExtractRequest
+	7,  // 7: twtext.v1.TwitterText.Autolink:input_type -> twtext.v1.AutolinkRequest
+	2,  // 8: twtext.v1.TwitterText.Parse:output_type -> twtext.v1.ParseResponse
+	4,  // 9: twtext.v1.TwitterText.Validate:output_type -> twtext.v1.ValidateResponse
+	6,  // 10: twtext.v1.TwitterText.Extract:output_type -> twtext.v1.ExtractResponse
+	8,  // 11: twtext.v1.TwitterText.Autolink:output_type -> twtext.v1.AutolinkResponse
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rpc_twtextpb_twtext_proto_init() }
+func file_rpc_twtextpb_twtext_proto_init() {
+	if File_rpc_twtextpb_twtext_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_rpc_twtextpb_twtext_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ValidateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtractRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtractResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutolinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_rpc_twtextpb_twtext_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutolinkResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_rpc_twtextpb_twtext_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_twtextpb_twtext_proto_goTypes,
+		DependencyIndexes: file_rpc_twtextpb_twtext_proto_depIdxs,
+		EnumInfos:         file_rpc_twtextpb_twtext_proto_enumTypes,
+		MessageInfos:      file_rpc_twtextpb_twtext_proto_msgTypes,
+	}.Build()
+	File_rpc_twtextpb_twtext_proto = out.File
+	file_rpc_twtextpb_twtext_proto_rawDesc = nil
+	file_rpc_twtextpb_twtext_proto_goTypes = nil
+	file_rpc_twtextpb_twtext_proto_depIdxs = nil
+}
@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: rpc/twtextpb/twtext.proto
+
+package twtextpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TwitterText_Parse_FullMethodName    = "/twtext.v1.TwitterText/Parse"
+	TwitterText_Validate_FullMethodName = "/twtext.v1.TwitterText/Validate"
+	TwitterText_Extract_FullMethodName  = "/twtext.v1.TwitterText/Extract"
+	TwitterText_Autolink_FullMethodName = "/twtext.v1.TwitterText/Autolink"
+)
+
+// TwitterTextClient is the client API for TwitterText service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TwitterTextClient interface {
+	// Returns the weighted length, permillage, and validity of a text
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// Reports whether a text is a valid tweet and, if not, why not
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// Returns the entities in a text
+	Extract(ctx context.Context, in *ExtractRequest, opts ...grpc.CallOption) (*ExtractResponse, error)
+	// Renders the entities in a text as links
+	Autolink(ctx context.Context, in *AutolinkRequest, opts ...grpc.CallOption) (*AutolinkResponse, error)
+}
+
+type twitterTextClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTwitterTextClient(cc grpc.ClientConnInterface) TwitterTextClient {
+	return &twitterTextClient{cc}
+}
+
+func (c *twitterTextClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TwitterText_Parse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twitterTextClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, TwitterText_Validate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twitterTextClient) Extract(ctx context.Context, in *ExtractRequest, opts ...grpc.CallOption) (*ExtractResponse, error) {
+	out := new(ExtractResponse)
+	err := c.cc.Invoke(ctx, TwitterText_Extract_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *twitterTextClient) Autolink(ctx context.Context, in *AutolinkRequest, opts ...grpc.CallOption) (*AutolinkResponse, error) {
+	out := new(AutolinkResponse)
+	err := c.cc.Invoke(ctx, TwitterText_Autolink_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TwitterTextServer is the server API for TwitterText service.
+// All implementations must embed UnimplementedTwitterTextServer
+// for forward compatibility
+type TwitterTextServer interface {
+	// Returns the weighted length, permillage, and validity of a text
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	// Reports whether a text is a valid tweet and, if not, why not
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// Returns the entities in a text
+	Extract(context.Context, *ExtractRequest) (*ExtractResponse, error)
+	// Renders the entities in a text as links
+	Autolink(context.Context, *AutolinkRequest) (*AutolinkResponse, error)
+	mustEmbedUnimplementedTwitterTextServer()
+}
+
+// UnimplementedTwitterTextServer must be embedded to have forward compatible implementations.
+type UnimplementedTwitterTextServer struct {
+}
+
+func (UnimplementedTwitterTextServer) Parse(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+func (UnimplementedTwitterTextServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedTwitterTextServer) Extract(context.Context, *ExtractRequest) (*ExtractResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Extract not implemented")
+}
+func (UnimplementedTwitterTextServer) Autolink(context.Context, *AutolinkRequest) (*AutolinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Autolink not implemented")
+}
+func (UnimplementedTwitterTextServer) mustEmbedUnimplementedTwitterTextServer() {}
+
+// UnsafeTwitterTextServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TwitterTextServer will
+// result in compilation errors.
+type UnsafeTwitterTextServer interface {
+	mustEmbedUnimplementedTwitterTextServer()
+}
+
+func RegisterTwitterTextServer(s grpc.ServiceRegistrar, srv TwitterTextServer) {
+	s.RegisterService(&TwitterText_ServiceDesc, srv)
+}
+
+func _TwitterText_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwitterTextServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwitterText_Parse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwitterTextServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwitterText_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwitterTextServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwitterText_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwitterTextServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwitterText_Extract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwitterTextServer).Extract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwitterText_Extract_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwitterTextServer).Extract(ctx, req.(*ExtractRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TwitterText_Autolink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutolinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TwitterTextServer).Autolink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TwitterText_Autolink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TwitterTextServer).Autolink(ctx, req.(*AutolinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TwitterText_ServiceDesc is the grpc.ServiceDesc for TwitterText service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TwitterText_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "twtext.v1.TwitterText",
+	HandlerType: (*TwitterTextServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _TwitterText_Parse_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _TwitterText_Validate_Handler,
+		},
+		{
+			MethodName: "Extract",
+			Handler:    _TwitterText_Extract_Handler,
+		},
+		{
+			MethodName: "Autolink",
+			Handler:    _TwitterText_Autolink_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc/twtextpb/twtext.proto",
+}
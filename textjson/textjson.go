@@ -0,0 +1,191 @@
+// Package textjson defines stable JSON encodings of parse results and
+// entities, for services in other languages that consume the output of
+// this package, e.g. from the twtext and twtextd commands. The encodings
+// follow the shapes used by the twitter-text libraries:
+//
+//	{"version":1,"text":"Hello @world","weightedLength":12,"permillage":42,"valid":true,
+//	 "entities":[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]}
+//
+// Each version of the encodings is described by JSON Schemas published in
+// the schema directory, and available as TweetSchema and EntitiesSchema.
+// Within a version, fields may be added, but fields are never removed,
+// renamed, or given a different meaning; such changes get a new version.
+// A Tweet records the version it was encoded with.
+//
+// Indices count characters (code points), as in the extract package.
+package textjson
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/tweet"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// The version of the encodings defined by this package
+const Version = 1
+
+// The JSON Schemas of the current version of the encodings. The Tweet
+// schema refers to the entities schema by its $id
+var (
+	//go:embed schema/v1/tweet.schema.json
+	TweetSchema string
+
+	//go:embed schema/v1/entities.schema.json
+	EntitiesSchema string
+)
+
+// The results of parsing a text, with the entities found in it
+type Tweet struct {
+	Version        int      `json:"version"`
+	Text           string   `json:"text"`
+	WeightedLength int      `json:"weightedLength"`
+	Permillage     int      `json:"permillage"`
+	Valid          bool     `json:"valid"`
+	Error          string   `json:"error,omitempty"` // Why the text is invalid, if it is
+	Entities       []Entity `json:"entities"`
+}
+
+// Returns the encoding of a parsed document
+func NewTweet(d *tweet.Document) Tweet {
+	results := d.ParseResults()
+	t := Tweet{
+		Version:        Version,
+		Text:           d.Text(),
+		WeightedLength: results.WeightedLength,
+		Permillage:     results.Permillage,
+		Valid:          results.IsValid,
+		Entities:       NewEntities(d.Entities()),
+	}
+	if err := d.Validate(); err != nil {
+		t.Error = err.Error()
+	}
+	return t
+}
+
+// An entity in the shape of the results of extractEntitiesWithIndices,
+// with its type and text
+type Entity struct {
+	Type       string `json:"type"`
+	Text       string `json:"text"`
+	Indices    [2]int `json:"indices"`
+	ScreenName string `json:"screenName,omitempty"`
+	ListSlug   string `json:"listSlug,omitempty"`
+	Hashtag    string `json:"hashtag,omitempty"`
+	Cashtag    string `json:"cashtag,omitempty"`
+	Url        string `json:"url,omitempty"`
+	Emoji      string `json:"emoji,omitempty"`
+}
+
+// The values of Entity.Type, by entity type
+var typeNames = map[extract.EntityType]string{
+	extract.MENTION:  "mention",
+	extract.HASH_TAG: "hashtag",
+	extract.CASH_TAG: "cashtag",
+	extract.URL:      "url",
+	extract.EMOJI:    "emoji",
+}
+
+// Returns the encoding of e
+func NewEntity(e *extract.TwitterEntity) Entity {
+	result := Entity{Type: typeNames[e.Type], Text: e.Text, Indices: [2]int{e.Range.Start, e.Range.Stop}}
+	switch e.Type {
+	case extract.MENTION:
+		result.ScreenName, _ = e.ScreenName()
+		result.ListSlug, _ = e.ListSlug()
+	case extract.HASH_TAG:
+		result.Hashtag, _ = e.Hashtag()
+	case extract.CASH_TAG:
+		result.Cashtag, _ = e.Cashtag()
+	case extract.URL:
+		result.Url = e.Text
+	case extract.EMOJI:
+		result.Emoji = e.Text
+	}
+	return result
+}
+
+// Returns the encodings of entities. The result is never nil, so that no
+// entities are encoded as [] rather than null
+func NewEntities(entities []*extract.TwitterEntity) []Entity {
+	result := make([]Entity, 0, len(entities))
+	for _, e := range entities {
+		result = append(result, NewEntity(e))
+	}
+	return result
+}
+
+// Returned by ToTwitterEntities for an entity whose indices are out of
+// range for its text, or that does not match the text at its indices
+var ErrInvalidIndices = errors.New("textjson: invalid entity indices")
+
+// Converts decoded entities found in text back to extract entities, e.g.
+// to render them with autolink.AutoLinkWithEntities
+func ToTwitterEntities(text string, entities []Entity) ([]*extract.TwitterEntity, error) {
+	// Map character offsets to byte offsets, including the end of text
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	result := make([]*extract.TwitterEntity, 0, len(entities))
+	for _, e := range entities {
+		if e.Indices[0] < 0 || e.Indices[0] > e.Indices[1] || e.Indices[1] >= len(offsets) {
+			return nil, ErrInvalidIndices
+		}
+		start, stop := offsets[e.Indices[0]], offsets[e.Indices[1]]
+		if text[start:stop] != e.Text {
+			return nil, ErrInvalidIndices
+		}
+		switch e.Type {
+		case "mention":
+			result = append(result, extract.NewMentionEntity(text, start, stop, e.ScreenName, e.ListSlug))
+		case "hashtag":
+			result = append(result, extract.NewHashtagEntity(text, start, stop, e.Hashtag))
+		case "cashtag":
+			result = append(result, extract.NewCashtagEntity(text, start, stop, e.Cashtag))
+		case "url":
+			result = append(result, extract.NewUrlEntity(text, start, stop, "", ""))
+		case "emoji":
+			result = append(result, &extract.TwitterEntity{
+				Text:      e.Text,
+				Range:     extract.Range{Start: e.Indices[0], Stop: e.Indices[1]},
+				ByteRange: extract.Range{Start: start, Stop: stop},
+				Type:      extract.EMOJI,
+			})
+		default:
+			return nil, fmt.Errorf("textjson: unknown entity type %q", e.Type)
+		}
+	}
+	return result, nil
+}
+
+// Parse results in the shape of the results of parseTweet
+type ParseResults struct {
+	WeightedLength int  `json:"weightedLength"`
+	Permillage     int  `json:"permillage"`
+	Valid          bool `json:"valid"`
+}
+
+// Returns the encoding of r
+func NewParseResults(r validate.ParseResults) ParseResults {
+	return ParseResults{r.WeightedLength, r.Permillage, r.IsValid}
+}
+
+// Whether a text is valid and, if not, why not
+type Validation struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// Returns the encoding of the error returned by validating a text
+func NewValidation(err error) Validation {
+	if err != nil {
+		return Validation{Valid: false, Error: err.Error()}
+	}
+	return Validation{Valid: true}
+}
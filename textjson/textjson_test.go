@@ -0,0 +1,151 @@
+package textjson
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/tweet"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestNewEntities(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"", `[]`},
+		{"@a/list", `[{"type":"mention","text":"@a/list","indices":[0,7],"screenName":"a","listSlug":"/list"}]`},
+		{"日本 #タグ $GO", `[{"type":"hashtag","text":"#タグ","indices":[3,6],"hashtag":"タグ"},{"type":"cashtag","text":"$GO","indices":[7,10],"cashtag":"GO"}]`},
+		{"see example.com", `[{"type":"url","text":"example.com","indices":[4,15],"url":"example.com"}]`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(NewEntities(extract.ExtractEntities(test.text)))
+		if err != nil || string(b) != test.expected {
+			t.Errorf("NewEntities returned incorrect value for text [%s]. Expected:%s Got:%s %v", test.text, test.expected, b, err)
+		}
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if actual := NewValidation(nil); actual != (Validation{Valid: true}) {
+		t.Errorf("NewValidation returned incorrect value for nil. Got:%+v", actual)
+	}
+	if actual := NewValidation(errors.New("too long")); actual != (Validation{Error: "too long"}) {
+		t.Errorf("NewValidation returned incorrect value for an error. Got:%+v", actual)
+	}
+}
+
+func TestNewTweet(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Hello @world", `{"version":1,"text":"Hello @world","weightedLength":12,"permillage":42,"valid":true,"entities":[{"type":"mention","text":"@world","indices":[6,12],"screenName":"world"}]}`},
+		{"", `{"version":1,"text":"","weightedLength":0,"permillage":0,"valid":false,"error":"Tweets may not be empty","entities":[]}`},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(NewTweet(tweet.Parse(test.text, tweet.WithConfig(config.V3()))))
+		if err != nil || string(b) != test.expected {
+			t.Errorf("NewTweet returned incorrect value for text [%s]. Expected:%s Got:%s %v", test.text, test.expected, b, err)
+		}
+	}
+}
+
+// Texts with entities of every type, in several scripts
+var schemaTexts = []string{
+	"",
+	"Hello @world",
+	"@a/list and @b",
+	"日本 #タグ $GO see example.com \U0001f600",
+	"\U0001f468‍\U0001f469‍\U0001f467 https://t.co/abc #go",
+	strings.Repeat("x", 300),
+}
+
+func TestSchemas(t *testing.T) {
+	loader := gojsonschema.NewSchemaLoader()
+	loader.Draft = gojsonschema.Draft7
+	if err := loader.AddSchemas(gojsonschema.NewStringLoader(EntitiesSchema)); err != nil {
+		t.Fatalf("Error loading the entities schema: %v", err)
+	}
+	tweetSchema, err := loader.Compile(gojsonschema.NewStringLoader(TweetSchema))
+	if err != nil {
+		t.Fatalf("Error compiling the tweet schema: %v", err)
+	}
+	entitiesSchema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(EntitiesSchema))
+	if err != nil {
+		t.Fatalf("Error compiling the entities schema: %v", err)
+	}
+
+	for _, text := range schemaTexts {
+		for _, test := range []struct {
+			schema *gojsonschema.Schema
+			value  interface{}
+		}{
+			{tweetSchema, NewTweet(tweet.Parse(text))},
+			{entitiesSchema, NewEntities(extract.ExtractEntities(text))},
+			{entitiesSchema, NewEntities(extract.ExtractEmoji(text))},
+		} {
+			result, err := test.schema.Validate(gojsonschema.NewGoLoader(test.value))
+			if err != nil {
+				t.Fatalf("Error validating encoding of text [%s]: %v", text, err)
+			}
+			if !result.Valid() {
+				t.Errorf("Encoding of text [%s] does not match the schema: %v", text, result.Errors())
+			}
+		}
+	}
+
+	// A mention must have a screen name
+	result, err := entitiesSchema.Validate(gojsonschema.NewStringLoader(`[{"type":"mention","text":"@a","indices":[0,2]}]`))
+	if err != nil || result.Valid() {
+		t.Errorf("Entities schema accepted a mention without a screen name")
+	}
+}
+
+func TestToTwitterEntities(t *testing.T) {
+	for _, text := range schemaTexts {
+		expected := append(extract.ExtractEntities(text), extract.ExtractEmoji(text)...)
+		b, err := json.Marshal(NewEntities(expected))
+		if err != nil {
+			t.Fatalf("Error encoding entities: %v", err)
+		}
+		var decoded []Entity
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("Error decoding entities: %v", err)
+		}
+		actual, err := ToTwitterEntities(text, decoded)
+		if err != nil {
+			t.Errorf("ToTwitterEntities returned an error for text [%s]: %v", text, err)
+			continue
+		}
+		if len(actual) != len(expected) {
+			t.Errorf("ToTwitterEntities returned incorrect number of entities for text [%s]. Expected:%d Got:%d", text, len(expected), len(actual))
+			continue
+		}
+		for i, e := range actual {
+			if e.Type != expected[i].Type || e.Text != expected[i].Text || e.Range != expected[i].Range || e.ByteRange != expected[i].ByteRange {
+				t.Errorf("ToTwitterEntities returned incorrect entity for text [%s]. Expected:%+v Got:%+v", text, expected[i], e)
+			}
+		}
+	}
+
+	invalid := []Entity{
+		{Type: "mention", Text: "@b", Indices: [2]int{0, 2}, ScreenName: "b"},
+		{Type: "hashtag", Text: "#a", Indices: [2]int{0, 20}, Hashtag: "a"},
+		{Type: "hashtag", Text: "#a", Indices: [2]int{2, 0}, Hashtag: "a"},
+	}
+	for _, e := range invalid {
+		if _, err := ToTwitterEntities("@a", []Entity{e}); err != ErrInvalidIndices {
+			t.Errorf("ToTwitterEntities returned incorrect error for entity %+v. Expected:%v Got:%v", e, ErrInvalidIndices, err)
+		}
+	}
+	if _, err := ToTwitterEntities("@a", []Entity{{Type: "list", Text: "@a", Indices: [2]int{0, 2}}}); err == nil {
+		t.Errorf("ToTwitterEntities returned no error for an unknown type")
+	}
+}
@@ -0,0 +1,538 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: textpb/text.proto
+
+package textpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Entity_Type int32
+
+const (
+	Entity_TYPE_UNSPECIFIED Entity_Type = 0
+	Entity_URL              Entity_Type = 1
+	Entity_HASHTAG          Entity_Type = 2
+	Entity_MENTION          Entity_Type = 3
+	Entity_CASHTAG          Entity_Type = 4
+	Entity_EMOJI            Entity_Type = 5
+)
+
+// Enum value maps for Entity_Type.
+var (
+	Entity_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "URL",
+		2: "HASHTAG",
+		3: "MENTION",
+		4: "CASHTAG",
+		5: "EMOJI",
+	}
+	Entity_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"URL":              1,
+		"HASHTAG":          2,
+		"MENTION":          3,
+		"CASHTAG":          4,
+		"EMOJI":            5,
+	}
+)
+
+func (x Entity_Type) Enum() *Entity_Type {
+	p := new(Entity_Type)
+	*p = x
+	return p
+}
+
+func (x Entity_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Entity_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_textpb_text_proto_enumTypes[0].Descriptor()
+}
+
+func (Entity_Type) Type() protoreflect.EnumType {
+	return &file_textpb_text_proto_enumTypes[0]
+}
+
+func (x Entity_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Entity_Type.Descriptor instead.
+func (Entity_Type) EnumDescriptor() ([]byte, []int) {
+	return file_textpb_text_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// An entity found in a text
+type Entity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type Entity_Type `protobuf:"varint,1,opt,name=type,proto3,enum=twtext.v1.Entity_Type" json:"type,omitempty"`
+	// The text of the entity, including any # or @
+	Text string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// The range of the entity in the text, in characters (code points)
+	Start int32 `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End   int32 `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+	// Set for mentions, without the @, and for mentions of lists, with the
+	// leading slash
+	ScreenName string `protobuf:"bytes,5,opt,name=screen_name,json=screenName,proto3" json:"screen_name,omitempty"`
+	ListSlug   string `protobuf:"bytes,6,opt,name=list_slug,json=listSlug,proto3" json:"list_slug,omitempty"`
+	// Set for hashtags and cashtags, without the # or $
+	Hashtag string `protobuf:"bytes,7,opt,name=hashtag,proto3" json:"hashtag,omitempty"`
+	Cashtag string `protobuf:"bytes,8,opt,name=cashtag,proto3" json:"cashtag,omitempty"`
+}
+
+func (x *Entity) Reset() {
+	*x = Entity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_textpb_text_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entity) ProtoMessage() {}
+
+func (x *Entity) ProtoReflect() protoreflect.Message {
+	mi := &file_textpb_text_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entity.ProtoReflect.Descriptor instead.
+func (*Entity) Descriptor() ([]byte, []int) {
+	return file_textpb_text_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Entity) GetType() Entity_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Entity_TYPE_UNSPECIFIED
+}
+
+func (x *Entity) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Entity) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Entity) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Entity) GetScreenName() string {
+	if x != nil {
+		return x.ScreenName
+	}
+	return ""
+}
+
+func (x *Entity) GetListSlug() string {
+	if x != nil {
+		return x.ListSlug
+	}
+	return ""
+}
+
+func (x *Entity) GetHashtag() string {
+	if x != nil {
+		return x.Hashtag
+	}
+	return ""
+}
+
+func (x *Entity) GetCashtag() string {
+	if x != nil {
+		return x.Cashtag
+	}
+	return ""
+}
+
+// The entities found in a text, in the order they appear in it
+type Entities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entities []*Entity `protobuf:"bytes,1,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *Entities) Reset() {
+	*x = Entities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_textpb_text_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Entities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entities) ProtoMessage() {}
+
+func (x *Entities) ProtoReflect() protoreflect.Message {
+	mi := &file_textpb_text_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entities.ProtoReflect.Descriptor instead.
+func (*Entities) Descriptor() ([]byte, []int) {
+	return file_textpb_text_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Entities) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+// The results of parsing a text
+type ParseResults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The weighted length of the text
+	WeightedLength int32 `protobuf:"varint,1,opt,name=weighted_length,json=weightedLength,proto3" json:"weighted_length,omitempty"`
+	// The weighted length as a proportion of the maximum length, in
+	// thousandths
+	Permillage int32 `protobuf:"varint,2,opt,name=permillage,proto3" json:"permillage,omitempty"`
+	// Whether the text is a valid tweet
+	Valid bool `protobuf:"varint,3,opt,name=valid,proto3" json:"valid,omitempty"`
+}
+
+func (x *ParseResults) Reset() {
+	*x = ParseResults{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_textpb_text_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ParseResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResults) ProtoMessage() {}
+
+func (x *ParseResults) ProtoReflect() protoreflect.Message {
+	mi := &file_textpb_text_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResults.ProtoReflect.Descriptor instead.
+func (*ParseResults) Descriptor() ([]byte, []int) {
+	return file_textpb_text_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ParseResults) GetWeightedLength() int32 {
+	if x != nil {
+		return x.WeightedLength
+	}
+	return 0
+}
+
+func (x *ParseResults) GetPermillage() int32 {
+	if x != nil {
+		return x.Permillage
+	}
+	return 0
+}
+
+func (x *ParseResults) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+// A text with the results of parsing it and the entities found in it
+type Tweet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text    string        `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Results *ParseResults `protobuf:"bytes,2,opt,name=results,proto3" json:"results,omitempty"`
+	// Why the text is invalid, if it is
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// The entities, in the order they appear in the text
+	Entities []*Entity `protobuf:"bytes,4,rep,name=entities,proto3" json:"entities,omitempty"`
+}
+
+func (x *Tweet) Reset() {
+	*x = Tweet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_textpb_text_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tweet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tweet) ProtoMessage() {}
+
+func (x *Tweet) ProtoReflect() protoreflect.Message {
+	mi := &file_textpb_text_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tweet.ProtoReflect.Descriptor instead.
+func (*Tweet) Descriptor() ([]byte, []int) {
+	return file_textpb_text_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Tweet) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Tweet) GetResults() *ParseResults {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *Tweet) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Tweet) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+var File_textpb_text_proto protoreflect.FileDescriptor
+
+var file_textpb_text_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x74, 0x65, 0x78, 0x74, 0x70, 0x62, 0x2f, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x09, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x22, 0xbb,
+	0x02, 0x0a, 0x06, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x2a, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x73, 0x6c, 0x75, 0x67, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x69, 0x73, 0x74, 0x53, 0x6c, 0x75, 0x67, 0x12,
+	0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x74, 0x61, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x68, 0x61, 0x73, 0x68, 0x74, 0x61, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x61, 0x73,
+	0x68, 0x74, 0x61, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x61, 0x73, 0x68,
+	0x74, 0x61, 0x67, 0x22, 0x57, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x54,
+	0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x52, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x48, 0x41,
+	0x53, 0x48, 0x54, 0x41, 0x47, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x45, 0x4e, 0x54, 0x49,
+	0x4f, 0x4e, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x41, 0x53, 0x48, 0x54, 0x41, 0x47, 0x10,
+	0x04, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x4d, 0x4f, 0x4a, 0x49, 0x10, 0x05, 0x22, 0x39, 0x0a, 0x08,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x77, 0x74,
+	0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x08, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x6d, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x65, 0x64, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x65, 0x64, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x6c, 0x6c, 0x61, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x6c, 0x6c, 0x61, 0x67, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x22, 0x93, 0x01, 0x0a, 0x05, 0x54, 0x77, 0x65, 0x65, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2d, 0x0a,
+	0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x77, 0x74, 0x65, 0x78, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x42, 0x2b, 0x5a, 0x29,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x79, 0x6c, 0x65, 0x6d,
+	0x63, 0x63, 0x2f, 0x74, 0x77, 0x69, 0x74, 0x74, 0x65, 0x72, 0x2d, 0x74, 0x65, 0x78, 0x74, 0x2d,
+	0x67, 0x6f, 0x2f, 0x74, 0x65, 0x78, 0x74, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_textpb_text_proto_rawDescOnce sync.Once
+	file_textpb_text_proto_rawDescData = file_textpb_text_proto_rawDesc
+)
+
+func file_textpb_text_proto_rawDescGZIP() []byte {
+	file_textpb_text_proto_rawDescOnce.Do(func() {
+		file_textpb_text_proto_rawDescData = protoimpl.X.CompressGZIP(file_textpb_text_proto_rawDescData)
+	})
+	return file_textpb_text_proto_rawDescData
+}
+
+var file_textpb_text_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_textpb_text_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_textpb_text_proto_goTypes = []interface{}{
+	(Entity_Type)(0),     // 0: twtext.v1.Entity.Type
+	(*Entity)(nil),       // 1: twtext.v1.Entity
+	(*Entities)(nil),     // 2: twtext.v1.Entities
+	(*ParseResults)(nil), // 3: twtext.v1.ParseResults
+	(*Tweet)(nil),        // 4: twtext.v1.Tweet
+}
+var file_textpb_text_proto_depIdxs = []int32{
+	0, // 0: twtext.v1.Entity.type:type_name -> twtext.v1.Entity.Type
+	1, // 1: twtext.v1.Entities.entities:type_name -> twtext.v1.Entity
+	3, // 2: twtext.v1.Tweet.results:type_name -> twtext.v1.ParseResults
+	1, // 3: twtext.v1.Tweet.entities:type_name -> twtext.v1.Entity
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_textpb_text_proto_init() }
+func file_textpb_text_proto_init() {
+	if File_textpb_text_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_textpb_text_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Entity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_textpb_text_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Entities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_textpb_text_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ParseResults); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_textpb_text_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tweet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_textpb_text_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_textpb_text_proto_goTypes,
+		DependencyIndexes: file_textpb_text_proto_depIdxs,
+		EnumInfos:         file_textpb_text_proto_enumTypes,
+		MessageInfos:      file_textpb_text_proto_msgTypes,
+	}.Build()
+	File_textpb_text_proto = out.File
+	file_textpb_text_proto_rawDesc = nil
+	file_textpb_text_proto_goTypes = nil
+	file_textpb_text_proto_depIdxs = nil
+}
@@ -0,0 +1,120 @@
+// Package textpb holds protocol buffer messages for entities and parse
+// results, defined in text.proto, and converts between them and the types
+// of the extract, validate, and tweet packages. The messages are
+// independent of the gRPC service in the rpc package, so that pipelines
+// can serialize extraction results compactly, with a schema:
+//
+//	b, err := proto.Marshal(textpb.NewTweet(tweet.Parse(text)))
+//
+// As in the extract package, entity ranges count characters (code points).
+package textpb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative textpb/text.proto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/tweet"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// Returns the message form of e
+func NewEntity(e *extract.TwitterEntity) *Entity {
+	result := &Entity{Text: e.Text, Start: int32(e.Range.Start), End: int32(e.Range.Stop)}
+	switch e.Type {
+	case extract.URL:
+		result.Type = Entity_URL
+	case extract.HASH_TAG:
+		result.Type = Entity_HASHTAG
+		result.Hashtag, _ = e.Hashtag()
+	case extract.MENTION:
+		result.Type = Entity_MENTION
+		result.ScreenName, _ = e.ScreenName()
+		result.ListSlug, _ = e.ListSlug()
+	case extract.CASH_TAG:
+		result.Type = Entity_CASHTAG
+		result.Cashtag, _ = e.Cashtag()
+	case extract.EMOJI:
+		result.Type = Entity_EMOJI
+	}
+	return result
+}
+
+// Returns the message forms of entities
+func NewEntities(entities []*extract.TwitterEntity) []*Entity {
+	result := make([]*Entity, 0, len(entities))
+	for _, e := range entities {
+		result = append(result, NewEntity(e))
+	}
+	return result
+}
+
+// Returns the message form of r
+func NewParseResults(r validate.ParseResults) *ParseResults {
+	return &ParseResults{
+		WeightedLength: int32(r.WeightedLength),
+		Permillage:     int32(r.Permillage),
+		Valid:          r.IsValid,
+	}
+}
+
+// Returns the message form of a parsed document
+func NewTweet(d *tweet.Document) *Tweet {
+	t := &Tweet{
+		Text:     d.Text(),
+		Results:  NewParseResults(d.ParseResults()),
+		Entities: NewEntities(d.Entities()),
+	}
+	if err := d.Validate(); err != nil {
+		t.Error = err.Error()
+	}
+	return t
+}
+
+// Returned by ToTwitterEntities for an entity whose range is out of range
+// for its text, or that does not match the text at its range
+var ErrInvalidRange = errors.New("textpb: invalid entity range")
+
+// Converts entity messages for text back to extract entities, e.g. to
+// render them with autolink.AutoLinkWithEntities
+func ToTwitterEntities(text string, entities []*Entity) ([]*extract.TwitterEntity, error) {
+	// Map character offsets to byte offsets, including the end of text
+	offsets := make([]int, 0, len(text)+1)
+	for i := range text {
+		offsets = append(offsets, i)
+	}
+	offsets = append(offsets, len(text))
+
+	result := make([]*extract.TwitterEntity, 0, len(entities))
+	for _, e := range entities {
+		if e.Start < 0 || e.Start > e.End || int(e.End) >= len(offsets) {
+			return nil, ErrInvalidRange
+		}
+		start, stop := offsets[e.Start], offsets[e.End]
+		if text[start:stop] != e.Text {
+			return nil, ErrInvalidRange
+		}
+		switch e.Type {
+		case Entity_URL:
+			result = append(result, extract.NewUrlEntity(text, start, stop, "", ""))
+		case Entity_HASHTAG:
+			result = append(result, extract.NewHashtagEntity(text, start, stop, e.Hashtag))
+		case Entity_MENTION:
+			result = append(result, extract.NewMentionEntity(text, start, stop, e.ScreenName, e.ListSlug))
+		case Entity_CASHTAG:
+			result = append(result, extract.NewCashtagEntity(text, start, stop, e.Cashtag))
+		case Entity_EMOJI:
+			result = append(result, &extract.TwitterEntity{
+				Text:      e.Text,
+				Range:     extract.Range{Start: int(e.Start), Stop: int(e.End)},
+				ByteRange: extract.Range{Start: start, Stop: stop},
+				Type:      extract.EMOJI,
+			})
+		default:
+			return nil, fmt.Errorf("textpb: invalid entity type %v", e.Type)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package textpb
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/tweet"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewTweet(t *testing.T) {
+	text := "日本 #タグ @a/list $GO see example.com"
+	expected := &Tweet{
+		Text:    text,
+		Results: &ParseResults{WeightedLength: 50, Permillage: 178, Valid: true},
+		Entities: []*Entity{
+			{Type: Entity_HASHTAG, Text: "#タグ", Start: 3, End: 6, Hashtag: "タグ"},
+			{Type: Entity_MENTION, Text: "@a/list", Start: 7, End: 14, ScreenName: "a", ListSlug: "/list"},
+			{Type: Entity_CASHTAG, Text: "$GO", Start: 15, End: 18, Cashtag: "GO"},
+			{Type: Entity_URL, Text: "example.com", Start: 23, End: 34},
+		},
+	}
+	actual := NewTweet(tweet.Parse(text, tweet.WithConfig(config.V3())))
+	if !proto.Equal(actual, expected) {
+		t.Errorf("NewTweet returned incorrect value for text [%s]. Expected:%v Got:%v", text, expected, actual)
+	}
+
+	actual = NewTweet(tweet.Parse(""))
+	if actual.Error != "Tweets may not be empty" || actual.Results.Valid {
+		t.Errorf("NewTweet returned incorrect value for an empty text. Got:%v", actual)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	texts := []string{
+		"",
+		"Hello @world",
+		"日本 #タグ $GO see example.com \U0001f600",
+		"\U0001f468‍\U0001f469‍\U0001f467 https://t.co/abc #go",
+	}
+
+	for _, text := range texts {
+		expected := append(extract.ExtractEntities(text), extract.ExtractEmoji(text)...)
+		b, err := proto.Marshal(&Entities{Entities: NewEntities(expected)})
+		if err != nil {
+			t.Fatalf("Error marshaling entities: %v", err)
+		}
+		var decoded Entities
+		if err := proto.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("Error unmarshaling entities: %v", err)
+		}
+		actual, err := ToTwitterEntities(text, decoded.Entities)
+		if err != nil {
+			t.Errorf("ToTwitterEntities returned an error for text [%s]: %v", text, err)
+			continue
+		}
+		if len(actual) != len(expected) {
+			t.Errorf("ToTwitterEntities returned incorrect number of entities for text [%s]. Expected:%d Got:%d", text, len(expected), len(actual))
+			continue
+		}
+		for i, e := range actual {
+			if e.Type != expected[i].Type || e.Text != expected[i].Text || e.Range != expected[i].Range || e.ByteRange != expected[i].ByteRange {
+				t.Errorf("ToTwitterEntities returned incorrect entity for text [%s]. Expected:%+v Got:%+v", text, expected[i], e)
+			}
+		}
+	}
+}
+
+func TestToTwitterEntitiesErrors(t *testing.T) {
+	invalid := []*Entity{
+		{Type: Entity_MENTION, Text: "@b", Start: 0, End: 2, ScreenName: "b"},
+		{Type: Entity_HASHTAG, Text: "#a", Start: 0, End: 20, Hashtag: "a"},
+		{Type: Entity_HASHTAG, Text: "#a", Start: 2, End: 0, Hashtag: "a"},
+		{Type: Entity_HASHTAG, Text: "#a", Start: -1, End: 1, Hashtag: "a"},
+	}
+	for _, e := range invalid {
+		if _, err := ToTwitterEntities("@a", []*Entity{e}); err != ErrInvalidRange {
+			t.Errorf("ToTwitterEntities returned incorrect error for entity %v. Expected:%v Got:%v", e, ErrInvalidRange, err)
+		}
+	}
+	if _, err := ToTwitterEntities("@a", []*Entity{{Text: "@a", Start: 0, End: 2}}); err == nil {
+		t.Errorf("ToTwitterEntities returned no error for an unspecified type")
+	}
+}
@@ -0,0 +1,68 @@
+// Package tmpl provides html/template integration for the autolink package
+//
+// The functions in this package return template.HTML values, so their
+// output can be used in templates without being escaped a second time.
+// This is safe because the autolinker escapes all text and attribute values
+// it writes; the functions here always treat their input as unescaped,
+// regardless of the TextIsEscaped setting of the Autolinker in use.
+//
+// The simplest way to use this package is to install the functions
+// returned by FuncMap:
+//
+//	t := template.Must(template.New("tweet").Funcs(tmpl.FuncMap()).Parse(`<p>{{autolink .Text}}</p>`))
+package tmpl
+
+import (
+	"html/template"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+)
+
+// Auto-link all entities in the given text using the default autolink
+// settings
+func AutoLink(text string) template.HTML {
+	return template.HTML(autolink.AutoLink(text, autolink.WithTextIsEscaped(false)))
+}
+
+// Returns a function that auto-links all entities in its input using
+// the supplied Autolinker
+func AutoLinkFunc(a *autolink.Autolinker) func(string) template.HTML {
+	return func(text string) template.HTML {
+		return template.HTML(a.AutoLink(text, autolink.WithTextIsEscaped(false)))
+	}
+}
+
+// Returns a FuncMap containing the following template functions, each of
+// which takes a string and returns template.HTML:
+//
+//	autolink                   auto-link all entities
+//	autolinkUsernamesAndLists  auto-link @username and @username/list references
+//	autolinkHashtags           auto-link #hashtags
+//	autolinkCashtags           auto-link $cashtags
+//	autolinkUrls               auto-link URLs
+//
+// The functions use the default autolink settings.
+func FuncMap() template.FuncMap {
+	return NewFuncMap(autolink.NewAutolinker())
+}
+
+// Returns a FuncMap containing the same functions as FuncMap, using the
+// supplied Autolinker instead of the default settings
+func NewFuncMap(a *autolink.Autolinker) template.FuncMap {
+	unescaped := autolink.WithTextIsEscaped(false)
+	return template.FuncMap{
+		"autolink": AutoLinkFunc(a),
+		"autolinkUsernamesAndLists": func(text string) template.HTML {
+			return template.HTML(a.AutoLinkUsernamesAndLists(text, unescaped))
+		},
+		"autolinkHashtags": func(text string) template.HTML {
+			return template.HTML(a.AutoLinkHashtags(text, unescaped))
+		},
+		"autolinkCashtags": func(text string) template.HTML {
+			return template.HTML(a.AutoLinkCashtags(text, unescaped))
+		},
+		"autolinkUrls": func(text string) template.HTML {
+			return template.HTML(a.AutoLinkUrls(text, unescaped))
+		},
+	}
+}
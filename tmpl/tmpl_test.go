@@ -0,0 +1,55 @@
+package tmpl
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+)
+
+func ExampleFuncMap() {
+	t := template.Must(template.New("tweet").Funcs(FuncMap()).Parse(`<p>{{autolink .}}</p>`))
+	t.Execute(os.Stdout, "<b>hello</b> #world")
+	// Output:
+	// <p>&lt;b&gt;hello&lt;/b&gt; <a href="https://twitter.com/search?q=%23world" title="#world" class="tweet-url hashtag" rel="nofollow">#world</a></p>
+}
+
+func TestFuncMap(t *testing.T) {
+	tests := []struct {
+		tmpl     string
+		text     string
+		expected string
+	}{
+		{`{{autolinkHashtags .}}`, "#tag @user",
+			`<a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag" rel="nofollow">#tag</a> @user`},
+		{`{{autolinkUsernamesAndLists .}}`, "#tag @user",
+			`#tag @<a class="tweet-url username" href="https://twitter.com/user" rel="nofollow">user</a>`},
+		{`{{autolinkCashtags .}}`, "$TAG #tag",
+			`<a href="https://twitter.com/search?q=%24TAG" title="$TAG" class="tweet-url cashtag" rel="nofollow">$TAG</a> #tag`},
+		{`{{autolinkUrls .}}`, "http://example.com #tag",
+			`<a href="http://example.com" rel="nofollow">http://example.com</a> #tag`},
+	}
+
+	for _, test := range tests {
+		tmpl := template.Must(template.New("test").Funcs(FuncMap()).Parse(test.tmpl))
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, test.text); err != nil {
+			t.Errorf("Error executing template [%s]: %v", test.tmpl, err)
+			continue
+		}
+		if actual := buf.String(); actual != test.expected {
+			t.Errorf("Template [%s] returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", test.tmpl, test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestAutoLinkFuncIgnoresTextIsEscaped(t *testing.T) {
+	a := autolink.NewAutolinker(autolink.WithTextIsEscaped(true), autolink.WithNoFollow(false))
+	text := "<script> #tag"
+	expected := template.HTML(`&lt;script&gt; <a href="https://twitter.com/search?q=%23tag" title="#tag" class="tweet-url hashtag">#tag</a>`)
+	if actual := AutoLinkFunc(a)(text); actual != expected {
+		t.Errorf("AutoLinkFunc returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+	}
+}
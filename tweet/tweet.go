@@ -0,0 +1,206 @@
+// Package tweet provides Document, which holds a tweet and the results of
+// parsing it. Each result is computed the first time it is needed and then
+// cached, so a pipeline that validates a tweet, extracts its entities, and
+// auto-links it normalizes and scans the text once rather than once per
+// step.
+//
+//	doc := tweet.Parse(text, tweet.WithConfig(config.V3()))
+//	if err := doc.Validate(); err != nil {
+//		return err
+//	}
+//	store(doc.Hashtags())
+//	html := doc.AutoLink()
+//
+// The results are the same as those of the corresponding functions in the
+// validate, extract, and autolink packages.
+package tweet
+
+import (
+	"sync"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/internal/unsafeconv"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+// A Document is a tweet whose normalized form, weighted length, and
+// entities are computed on first use and cached. A Document is safe for
+// concurrent use.
+//
+// The entity slices returned by a Document are shared by all callers and
+// must not be modified.
+type Document struct {
+	text   string
+	config *config.Config
+
+	normalizeOnce sync.Once
+	normalized    string
+
+	parseOnce sync.Once
+	results   validate.ParseResults
+	err       error
+
+	entities        lazyEntities
+	urls            lazyEntities
+	mentionsOrLists lazyEntities
+	screenNames     lazyEntities
+	hashtags        lazyEntities
+	cashtags        lazyEntities
+}
+
+// An Option customizes how a Document is parsed
+type Option func(*Document)
+
+// Parses the tweet under the given configuration instead of
+// validate.DefaultConfig(). The Document uses a copy of c, so c may be
+// modified afterwards
+func WithConfig(c *config.Config) Option {
+	return func(d *Document) {
+		d.config = c.Clone()
+	}
+}
+
+// Returns a Document for the given text. No work is done until one of the
+// Document's methods is called
+func Parse(text string, opts ...Option) *Document {
+	d := &Document{text: text}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.config == nil {
+		d.config = validate.DefaultConfig()
+	}
+	return d
+}
+
+// Returns a Document for text given as a []byte, such as a field decoded
+// from a stream of JSON. The bytes are copied, unless the package is built
+// with the unsafeconv tag, in which case the Document and the entities it
+// returns share text's memory, and text must not be modified while they
+// are in use
+func ParseBytes(text []byte, opts ...Option) *Document {
+	return Parse(unsafeconv.String(text), opts...)
+}
+
+// Returns the text of the tweet
+func (d *Document) Text() string {
+	return d.text
+}
+
+// Returns a copy of the configuration the tweet is parsed under
+func (d *Document) Config() *config.Config {
+	return d.config.Clone()
+}
+
+// Returns the NFC form of the text. See validate.Normalize
+func (d *Document) Normalized() string {
+	d.normalizeOnce.Do(func() {
+		d.normalized = validate.Normalize(d.text)
+	})
+	return d.normalized
+}
+
+// Returns the weighted length of the tweet. See validate.TweetLength
+func (d *Document) WeightedLength() int {
+	return d.ParseResults().WeightedLength
+}
+
+// Returns the results of parsing the tweet. See validate.ParseTweet
+func (d *Document) ParseResults() validate.ParseResults {
+	d.parse()
+	return d.results
+}
+
+// Reports whether the tweet is valid. See validate.TweetIsValid
+func (d *Document) IsValid() bool {
+	return d.Validate() == nil
+}
+
+// Returns nil if the tweet is valid, or an error describing why it is not.
+// See validate.ValidateTweet
+func (d *Document) Validate() error {
+	d.parse()
+	return d.err
+}
+
+func (d *Document) parse() {
+	d.parseOnce.Do(func() {
+		normalized := d.Normalized()
+
+		// URLs are weighted by their position in the normalized text. In
+		// the common case that the text is already normalized, those are
+		// the same URLs that are extracted from the text itself
+		var urls []*extract.TwitterEntity
+		switch {
+		case d.config.CountURLText:
+			// URLs are weighted as text
+		case normalized == d.text:
+			urls = d.Urls()
+		default:
+			urls = extract.ExtractUrls(normalized)
+		}
+		d.results, d.err = validate.ParseNormalizedTweet(d.text, normalized, urls, d.config)
+	})
+}
+
+// Returns all of the entities in the tweet. See extract.ExtractEntities
+func (d *Document) Entities() []*extract.TwitterEntity {
+	return d.entities.get(d.text, extract.ExtractEntities)
+}
+
+// Returns the URLs in the tweet. See extract.ExtractUrls
+func (d *Document) Urls() []*extract.TwitterEntity {
+	return d.urls.get(d.text, extract.ExtractUrls)
+}
+
+// Returns the @username mentions and lists in the tweet. See
+// extract.ExtractMentionsOrLists
+func (d *Document) MentionsOrLists() []*extract.TwitterEntity {
+	return d.mentionsOrLists.get(d.text, extract.ExtractMentionsOrLists)
+}
+
+// Returns the @username mentions in the tweet, excluding lists. See
+// extract.ExtractMentionedScreenNames
+func (d *Document) MentionedScreenNames() []*extract.TwitterEntity {
+	return d.screenNames.get(d.text, func(string) []*extract.TwitterEntity {
+		var result []*extract.TwitterEntity
+		for _, e := range d.MentionsOrLists() {
+			if _, isList := e.ListSlug(); !isList {
+				result = append(result, e)
+			}
+		}
+		return result
+	})
+}
+
+// Returns the #hashtags in the tweet. See extract.ExtractHashtags
+func (d *Document) Hashtags() []*extract.TwitterEntity {
+	return d.hashtags.get(d.text, extract.ExtractHashtags)
+}
+
+// Returns the $cashtags in the tweet. See extract.ExtractCashtags
+func (d *Document) Cashtags() []*extract.TwitterEntity {
+	return d.cashtags.get(d.text, extract.ExtractCashtags)
+}
+
+// Auto-links all usernames, lists, hashtags, cashtags, and URLs in the
+// tweet using the default settings, overridden by any supplied options.
+// See autolink.AutoLink
+func (d *Document) AutoLink(opts ...autolink.Option) string {
+	return autolink.AutoLinkWithEntities(d.text, d.Entities(), opts...)
+}
+
+// Entities that are extracted on first use
+type lazyEntities struct {
+	once     sync.Once
+	entities []*extract.TwitterEntity
+}
+
+func (l *lazyEntities) get(text string, extractFunc func(string) []*extract.TwitterEntity) []*extract.TwitterEntity {
+	l.once.Do(func() {
+		l.entities = extractFunc(text)
+	})
+	return l.entities
+}
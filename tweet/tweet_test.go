@@ -0,0 +1,160 @@
+package tweet
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/autolink"
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+	"github.com/kylemcc/twitter-text-go/validate"
+)
+
+var texts = []string{
+	"",
+	"just some text",
+	"RT @user: check out http://example.com/path?q=1 #golang $TWTR @list/members",
+	"Café in Montréal: https://t.co/abc123 #café",
+	"日本語のツイート #ハッシュタグ と example.jp",
+	"\U0001f468‍\U0001f469‍\U0001f467 family @mom @dad",
+	"invalid ￾ character",
+	"a very long tweet " + string(make([]byte, 300)),
+}
+
+func TestDocument(t *testing.T) {
+	configs := []*config.Config{config.V1(), config.V2(), config.V3(), config.Bluesky()}
+	for _, c := range configs {
+		for _, text := range texts {
+			doc := Parse(text, WithConfig(c))
+
+			if expected, actual := validate.ParseTweetWithConfig(text, c), doc.ParseResults(); actual != expected {
+				t.Errorf("ParseResults returned incorrect value for text [%s] and version %d. Expected:%+v Got:%+v", text, c.Version, expected, actual)
+			}
+			if expected, actual := validate.ValidateTweetWithConfig(text, c), doc.Validate(); actual != expected {
+				t.Errorf("Validate returned incorrect value for text [%s] and version %d. Expected:%v Got:%v", text, c.Version, expected, actual)
+			}
+			if expected, actual := validate.Normalize(text), doc.Normalized(); actual != expected {
+				t.Errorf("Normalized returned incorrect value for text [%s]. Expected:%q Got:%q", text, expected, actual)
+			}
+
+			entityTests := []struct {
+				name     string
+				expected []*extract.TwitterEntity
+				actual   []*extract.TwitterEntity
+			}{
+				{"Entities", extract.ExtractEntities(text), doc.Entities()},
+				{"Urls", extract.ExtractUrls(text), doc.Urls()},
+				{"MentionsOrLists", extract.ExtractMentionsOrLists(text), doc.MentionsOrLists()},
+				{"MentionedScreenNames", extract.ExtractMentionedScreenNames(text), doc.MentionedScreenNames()},
+				{"Hashtags", extract.ExtractHashtags(text), doc.Hashtags()},
+				{"Cashtags", extract.ExtractCashtags(text), doc.Cashtags()},
+			}
+			for _, test := range entityTests {
+				if !reflect.DeepEqual(test.actual, test.expected) {
+					t.Errorf("%s returned incorrect value for text [%s]. Expected:%v Got:%v", test.name, text, test.expected, test.actual)
+				}
+			}
+
+			if expected, actual := autolink.AutoLink(text), doc.AutoLink(); actual != expected {
+				t.Errorf("AutoLink returned incorrect value for text [%s]. Expected:[%s] Got:[%s]", text, expected, actual)
+			}
+		}
+	}
+}
+
+func TestDocumentDefaultConfig(t *testing.T) {
+	doc := Parse("text")
+	if !reflect.DeepEqual(doc.Config(), validate.DefaultConfig()) {
+		t.Errorf("Parse did not use the default configuration")
+	}
+	if doc.Text() != "text" {
+		t.Errorf("Text returned incorrect value. Expected:[text] Got:[%s]", doc.Text())
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	text := []byte(texts[2])
+	doc := ParseBytes(text, WithConfig(config.V3()))
+	if doc.Text() != texts[2] {
+		t.Errorf("Text returned incorrect value. Expected:[%s] Got:[%s]", texts[2], doc.Text())
+	}
+	if expected, actual := extract.ExtractEntities(texts[2]), doc.Entities(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Entities returned incorrect value. Expected:%v Got:%v", expected, actual)
+	}
+}
+
+func TestDocumentCaches(t *testing.T) {
+	doc := Parse(texts[2])
+	first, second := doc.Entities(), doc.Entities()
+	if len(first) == 0 || &first[0] != &second[0] {
+		t.Errorf("Entities extracted the entities again")
+	}
+	if urls := doc.Urls(); doc.Validate() != nil || &urls[0] != &doc.Urls()[0] {
+		t.Errorf("Validate extracted the URLs of normalized text again")
+	}
+}
+
+func TestDocumentConcurrentUse(t *testing.T) {
+	doc := Parse(texts[2], WithConfig(config.V3()))
+	expected := validate.ParseTweetWithConfig(texts[2], config.V3())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if actual := doc.ParseResults(); actual != expected {
+				t.Errorf("ParseResults returned incorrect value. Expected:%+v Got:%+v", expected, actual)
+			}
+			doc.AutoLink()
+			doc.MentionedScreenNames()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDocumentConfigIsCopied(t *testing.T) {
+	c := config.V3()
+	doc := Parse(texts[2], WithConfig(c))
+	expected := validate.ParseTweetWithConfig(texts[2], config.V3())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if actual := doc.ParseResults(); actual != expected {
+				t.Errorf("ParseResults returned incorrect value. Expected:%+v Got:%+v", expected, actual)
+			}
+		}()
+	}
+	c.MaxWeightedTweetLength = 1
+	doc.Config().Scale = 1
+	wg.Wait()
+
+	if doc.Config().MaxWeightedTweetLength != config.V3().MaxWeightedTweetLength || doc.Config().Scale != config.V3().Scale {
+		t.Errorf("Modifying a configuration changed the configuration of a Document")
+	}
+}
+
+func BenchmarkPipeline(b *testing.B) {
+	c := config.V3()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		validate.ValidateTweetWithConfig(texts[2], c)
+		extract.ExtractEntities(texts[2])
+		autolink.AutoLink(texts[2])
+	}
+}
+
+func BenchmarkDocumentPipeline(b *testing.B) {
+	c := config.V3()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		doc := Parse(texts[2], WithConfig(c))
+		doc.Validate()
+		doc.Entities()
+		doc.AutoLink()
+	}
+}
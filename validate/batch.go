@@ -0,0 +1,102 @@
+package validate
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// The number of tweets a ParseTweets worker claims at a time
+const batchChunkSize = 64
+
+// Sets the maximum number of goroutines ParseTweets uses to parse tweets.
+// Values less than one, and the default, mean runtime.GOMAXPROCS(0). Other
+// functions ignore this option.
+func WithConcurrency(n int) Option {
+	return func(o *options) { o.concurrency = n }
+}
+
+// The results of parsing a batch of tweets
+type BatchResults struct {
+	Results           []ParseResults // The results for each tweet, in the order the tweets were given
+	Valid             int            // The number of valid tweets
+	Invalid           int            // The number of invalid tweets
+	MaxWeightedLength int            // The greatest weighted length of any of the tweets
+}
+
+// Parses a batch of tweets concurrently, returning the results for each
+// tweet in order, as ParseTweet would return them, along with aggregate
+// statistics. The tweets are parsed by a bounded pool of goroutines (see
+// WithConcurrency). Every tweet is parsed under the same configuration,
+// even if SetDefaultConfig is called while the batch is being parsed.
+func ParseTweets(texts []string, opts ...Option) BatchResults {
+	o := options{config: loadDefaultConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	c := o.config
+
+	workers := o.concurrency
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunks := (len(texts) + batchChunkSize - 1) / batchChunkSize; workers > chunks {
+		workers = chunks
+	}
+
+	batch := BatchResults{Results: make([]ParseResults, len(texts))}
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		next int64 // the index of the next chunk to parse
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var stats BatchResults
+			for {
+				start := int(atomic.AddInt64(&next, batchChunkSize)) - batchChunkSize
+				if start >= len(texts) {
+					break
+				}
+				end := start + batchChunkSize
+				if end > len(texts) {
+					end = len(texts)
+				}
+				for i := start; i < end; i++ {
+					results := ParseTweetWithConfig(texts[i], c)
+					batch.Results[i] = results
+					stats.add(results)
+				}
+			}
+
+			mu.Lock()
+			batch.merge(stats)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return batch
+}
+
+// Adds a tweet's results to the statistics
+func (b *BatchResults) add(results ParseResults) {
+	if results.IsValid {
+		b.Valid++
+	} else {
+		b.Invalid++
+	}
+	if results.WeightedLength > b.MaxWeightedLength {
+		b.MaxWeightedLength = results.WeightedLength
+	}
+}
+
+// Adds the statistics of other to b
+func (b *BatchResults) merge(other BatchResults) {
+	b.Valid += other.Valid
+	b.Invalid += other.Invalid
+	if other.MaxWeightedLength > b.MaxWeightedLength {
+		b.MaxWeightedLength = other.MaxWeightedLength
+	}
+}
@@ -0,0 +1,87 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Runs the conformance suites against the package
+type conformanceValidator struct{}
+
+func (conformanceValidator) TweetIsValid(text string) bool {
+	return TweetIsValid(text)
+}
+
+func (conformanceValidator) TweetLength(text string) int {
+	return TweetLength(text)
+}
+
+func (conformanceValidator) UsernameIsValid(username string) bool {
+	return UsernameIsValid(username)
+}
+
+func (conformanceValidator) ListIsValid(list string) bool {
+	return ListIsValid(list)
+}
+
+func (conformanceValidator) HashtagIsValid(hashtag string) bool {
+	return HashtagIsValid(hashtag)
+}
+
+func (conformanceValidator) UrlIsValid(url string, requireProtocol bool) bool {
+	return UrlIsValid(url, requireProtocol, true)
+}
+
+// The configuration of each version
+var conformanceConfigs = map[int]func() *config.Config{1: config.V1, 2: config.V2, 3: config.V3}
+
+func (conformanceValidator) ParseTweet(text string, version int) conformance.ParseResults {
+	results := ParseTweetWithConfig(text, conformanceConfigs[version]())
+	return conformance.ParseResults{
+		WeightedLength:    results.WeightedLength,
+		Permillage:        results.Permillage,
+		Valid:             results.IsValid,
+		DisplayRangeStart: results.DisplayRangeStart,
+		DisplayRangeEnd:   results.DisplayRangeEnd,
+		ValidRangeStart:   results.ValidRangeStart,
+		ValidRangeEnd:     results.ValidRangeEnd,
+	}
+}
+
+func TestTweetIsValid(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "tweets")
+}
+
+func TestTweetLength(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "lengths")
+}
+
+func TestUsernameIsValid(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "usernames")
+}
+
+func TestListIsValid(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "lists")
+}
+
+func TestHashtagIsValid(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "hashtags")
+}
+
+func TestUrlIsValid(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "urls", "urls_without_protocol")
+}
+
+func TestWeightedTweetsCounter(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "WeightedTweetsCounterTest")
+}
+
+func TestWeightedTweetsWithDiscountedEmojiCounter(t *testing.T) {
+	conformance.RunValidate(t, conformanceValidator{}, "WeightedTweetsWithDiscountedEmojiCounterTest")
+}
+
+func TestEmojiLengths(t *testing.T) {
+	conformance.RunEmojiLengths(t, conformanceValidator{})
+}
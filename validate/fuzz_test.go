@@ -0,0 +1,83 @@
+//go:build go1.18
+// +build go1.18
+
+package validate
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/config"
+)
+
+func FuzzParseTweet(f *testing.F) {
+	for _, text := range streamTexts(f) {
+		f.Add(text)
+	}
+	f.Add("\xff\xfe invalid \xc0 UTF-8 http://example.com/\x80 \u202e")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		var tweet Tweet
+		for _, c := range []*config.Config{config.V1(), config.V2(), config.V3()} {
+			results := ParseTweetWithConfig(text, c)
+			err := ValidateTweetWithConfig(text, c)
+
+			if results.WeightedLength < 0 || results.WeightedLength != TweetLength(text, WithConfig(c)) {
+				t.Fatalf("ParseTweetWithConfig returned incorrect length for text [%+q] and version %d: %d", text, c.Version, results.WeightedLength)
+			}
+			if results.Permillage != permillage(results.WeightedLength, c.MaxWeightedTweetLength) {
+				t.Fatalf("ParseTweetWithConfig returned incorrect permillage for text [%+q] and version %d: %+v", text, c.Version, results)
+			}
+			if results.IsValid != (err == nil) {
+				t.Fatalf("ParseTweetWithConfig and ValidateTweetWithConfig disagree for text [%+q] and version %d: %+v, %v", text, c.Version, results, err)
+			}
+			if invalid, ok := err.(InvalidCharacterError); ok {
+				if invalid.Offset < 0 || invalid.Offset >= len(text) {
+					t.Fatalf("ValidateTweetWithConfig returned an out of bounds offset for text [%+q]: %v", text, invalid)
+				}
+				if r, _ := utf8.DecodeRuneInString(text[invalid.Offset:]); r != invalid.Character {
+					t.Fatalf("ValidateTweetWithConfig returned an offset that is not the invalid character for text [%+q]: %v", text, invalid)
+				}
+			}
+
+			ParseTweetInto(text, &tweet, nil, WithConfig(c))
+			if tweet.ParseResults != results || tweet.Err != err {
+				t.Fatalf("ParseTweetInto returned incorrect value for text [%+q] and version %d. Expected:%+v, %v Got:%+v, %v", text, c.Version, results, err, tweet.ParseResults, tweet.Err)
+			}
+
+			// Long runs without a space may be split elsewhere
+			if longestRun(text) <= streamMaxPending-streamChunkSize {
+				streamed, readErr := ParseReader(strings.NewReader(text), WithConfig(c))
+				if readErr != nil || streamed != results {
+					t.Fatalf("ParseReader returned incorrect value for text [%+q] and version %d. Expected:%+v Got:%+v, %v", text, c.Version, results, streamed, readErr)
+				}
+			}
+		}
+	})
+}
+
+func FuzzUrlIsValid(f *testing.F) {
+	for _, url := range conformanceTexts(f, "urls", "urls_without_protocol") {
+		f.Add(url)
+	}
+	f.Add("http://user:pass@[::1]:8080/path?q=%41#frag")
+	f.Add("http://ä.example/\xff")
+
+	f.Fuzz(func(t *testing.T, url string) {
+		for _, allowUnicode := range []bool{false, true} {
+			// Requiring a protocol only rejects URLs
+			if UrlIsValid(url, true, allowUnicode) && !UrlIsValid(url, false, allowUnicode) {
+				t.Fatalf("UrlIsValid accepted [%+q] with a protocol required but not without, allowUnicode %v", url, allowUnicode)
+			}
+		}
+		for _, requireProtocol := range []bool{false, true} {
+			// Allowing Unicode only accepts more URLs
+			if UrlIsValid(url, requireProtocol, false) && !UrlIsValid(url, requireProtocol, true) {
+				t.Fatalf("UrlIsValid accepted [%+q] without Unicode allowed but not with it, requireProtocol %v", url, requireProtocol)
+			}
+			ValidateUrl(url, requireProtocol, false)
+			ValidateUrl(url, requireProtocol, true)
+		}
+	})
+}
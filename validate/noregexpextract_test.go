@@ -0,0 +1,6 @@
+//go:build !regexpextract
+// +build !regexpextract
+
+package validate
+
+const regexpExtract = false
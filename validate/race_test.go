@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package validate
+
+// The race detector randomly drops items put in a sync.Pool, so allocation
+// counts are not meaningful
+const raceEnabled = true
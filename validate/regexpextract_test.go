@@ -0,0 +1,7 @@
+//go:build regexpextract
+// +build regexpextract
+
+package validate
+
+// The regexps allocate their matches, so extraction is not allocation free
+const regexpExtract = true
@@ -0,0 +1,250 @@
+package validate
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+// A Validator weighs text once it has buffered streamChunkSize bytes of
+// it, and buffers at most streamMaxPending bytes while looking for a place
+// to split the text. These are variables so that tests can shrink them
+var (
+	streamChunkSize  = 4 * 1024
+	streamMaxPending = 64 * 1024
+)
+
+// A Validator weighs and validates a tweet that is written to it in pieces,
+// e.g. with io.Copy, using memory that does not grow with the length of the
+// text. This suits tools that validate fields embedded in large files
+// without loading them.
+//
+// Text is weighed as it is written, in pieces split before spaces, which
+// gives the same results as weighing the whole text. A run of more than
+// 64KB without a space is split elsewhere, in which case a URL or grapheme
+// cluster spanning the split may be weighed differently.
+//
+// The zero value is not usable; use NewValidator. A Validator is not safe
+// for concurrent use.
+type Validator struct {
+	config  *config.Config
+	pending []byte // text that has been written but not yet weighed
+	offset  int    // the byte offset of pending within the text
+	weight  int64  // the weight of the text before pending, before it is divided by the configuration's scale
+
+	invalid     int // the byte offset of the first invalid character, or -1
+	invalidChar rune
+
+	// The lengths in UTF-16 code units of the text before pending, of its
+	// NFC form, and of the valid prefix of its NFC form, and whether that
+	// prefix is all of it. See ParseResults
+	units, normalizedUnits int
+	valid                  int
+	allValid               bool
+}
+
+// Returns a Validator for a tweet that is initially empty. The Validator
+// uses a copy of the configuration passed with WithConfig, so the
+// configuration may be modified afterwards
+func NewValidator(opts ...Option) *Validator {
+	c := configFor(opts)
+	if len(opts) > 0 {
+		c = c.Clone()
+	}
+	return &Validator{config: c, invalid: -1, allValid: true}
+}
+
+// Appends p to the tweet. Implements io.Writer; the error is always nil
+func (v *Validator) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		k := len(p)
+		if k > streamChunkSize {
+			k = streamChunkSize
+		}
+		v.pending = append(v.pending, p[:k]...)
+		p = p[k:]
+
+		for len(v.pending) >= streamChunkSize {
+			split := splitPoint(v.pending, len(v.pending) >= streamMaxPending)
+			if split <= 0 {
+				break
+			}
+			v.weigh(split)
+		}
+	}
+	return n, nil
+}
+
+// Weighs the first n bytes of pending and removes them
+func (v *Validator) weigh(n int) {
+	p := v.measure(string(v.pending[:n]))
+	v.weight += p.weight
+	if v.invalid < 0 && p.invalid >= 0 {
+		v.invalid = v.offset + p.invalid
+		v.invalidChar, _ = utf8.DecodeRune(v.pending[p.invalid:])
+	}
+	v.units += p.units
+	v.normalizedUnits += p.normalizedUnits
+	v.valid += p.valid
+	v.allValid = p.allValid
+	v.offset += n
+	v.pending = v.pending[:copy(v.pending, v.pending[n:])]
+}
+
+// A piece of a tweet, measured by Validator.measure
+type piece struct {
+	weight  int64 // the weight of the piece, before it is divided by the configuration's scale
+	invalid int   // the byte offset of its first invalid character, or -1
+
+	// The lengths in UTF-16 code units of the piece, of its NFC form, and
+	// of the prefix of its NFC form that extends the valid prefix of the
+	// text before it, and whether the valid prefix now includes all of it
+	units, normalizedUnits int
+	valid                  int
+	allValid               bool
+}
+
+// Measures a piece of the tweet that follows the text weighed so far
+func (v *Validator) measure(text string) piece {
+	c := v.config
+	normalized := normalize(text)
+	var urls []extract.TwitterEntity
+	if !c.CountURLText {
+		urls = extract.AppendUrls(nil, normalized)
+	}
+	p := piece{units: utf16Length(text), normalizedUnits: utf16Length(normalized)}
+	p.weight, p.invalid = measureWeight(normalized, urls, c)
+	if p.invalid >= 0 && normalized != text {
+		p.invalid = strings.IndexAny(text, invalidChars)
+	}
+
+	// The valid prefix is not measured once it has ended
+	if !v.allValid {
+		return p
+	}
+	if p.invalid < 0 && v.weight+p.weight <= int64(c.MaxWeightedTweetLength)*int64(c.Scale) {
+		p.valid, p.allValid = p.normalizedUnits, true
+	} else {
+		p.valid, p.allValid = validPrefix(normalized, urls, v.weight, c)
+	}
+	return p
+}
+
+// Returns the offset of the last place at which b may be split so that
+// the parts weigh the same as the whole, or 0 if there is none. That is
+// before a space that begins a grapheme cluster: a space cannot be part of
+// a URL and does not compose with a preceding character.
+//
+// If force is true and there is no such place, b is split elsewhere: at a
+// boundary between two ASCII characters, which at worst splits a URL or a
+// word, or failing that at the last boundary of its NFC normalization
+func splitPoint(b []byte, force bool) int {
+	for i := len(b) - 1; i > 0; i-- {
+		if b[i] == ' ' && startsCluster(b, i) {
+			return i
+		}
+	}
+	if !force {
+		return 0
+	}
+
+	for i := len(b) - 1; i > 0; i-- {
+		if b[i] < utf8.RuneSelf && isPrintableASCII(b[i-1]) {
+			return i
+		}
+	}
+	// Don't split an incomplete character at the end of b
+	end := len(b) - 1
+	for end > 0 && !utf8.RuneStart(b[end]) {
+		end--
+	}
+	if utf8.FullRune(b[end:]) {
+		end = len(b)
+	}
+	if i := lastNormalizationBoundary(b[:end]); i > 0 {
+		return i
+	}
+	return end
+}
+
+// Reports whether c is a printable ASCII character or a space
+func isPrintableASCII(c byte) bool {
+	return ' ' <= c && c < 0x7F
+}
+
+// Reports whether the character at offset i of b begins a grapheme
+// cluster. Whether a space does depends only on the character before it
+func startsCluster(b []byte, i int) bool {
+	if isPrintableASCII(b[i-1]) {
+		return true
+	}
+	start := i - 1
+	for start > 0 && !utf8.RuneStart(b[start]) {
+		start--
+	}
+	return firstGraphemeClusterLength(b[start:i+1]) == i-start
+}
+
+// Returns the results of parsing the text written so far and the error,
+// if any, that makes it an invalid tweet
+func (v *Validator) result() (ParseResults, error) {
+	weight, invalid, invalidChar := v.weight, v.invalid, v.invalidChar
+	units, normalizedUnits, valid := v.units, v.normalizedUnits, v.valid
+	if len(v.pending) > 0 {
+		p := v.measure(string(v.pending))
+		weight += p.weight
+		if invalid < 0 && p.invalid >= 0 {
+			invalid = v.offset + p.invalid
+			invalidChar, _ = utf8.DecodeRune(v.pending[p.invalid:])
+		}
+		units += p.units
+		normalizedUnits += p.normalizedUnits
+		valid += p.valid
+	}
+
+	length := scaledLength(weight, v.config)
+	var err error
+	switch {
+	case v.offset+len(v.pending) == 0:
+		err = EmptyError{}
+	case length > v.config.MaxWeightedTweetLength:
+		err = TooLongError(length)
+	case invalid >= 0:
+		err = InvalidCharacterError{Offset: invalid, Character: invalidChar}
+	}
+	return newParseResults(length, err, units, normalizedUnits, valid, v.config), err
+}
+
+// Returns the weighted length of the text written so far. See TweetLength
+func (v *Validator) WeightedLength() int {
+	results, _ := v.result()
+	return results.WeightedLength
+}
+
+// Returns nil if the text written so far is a valid tweet, or an error
+// describing why it is not. See ValidateTweet
+func (v *Validator) Validate() error {
+	_, err := v.result()
+	return err
+}
+
+// Returns the results of parsing the text written so far. See ParseTweet
+func (v *Validator) ParseResults() ParseResults {
+	results, _ := v.result()
+	return results
+}
+
+// Parses a tweet read from r, as ParseTweet would parse the text, without
+// holding all of the text in memory. See Validator. Returns an error only
+// if reading from r fails
+func ParseReader(r io.Reader, opts ...Option) (ParseResults, error) {
+	v := NewValidator(opts...)
+	if _, err := io.Copy(v, r); err != nil {
+		return ParseResults{}, err
+	}
+	return v.ParseResults(), nil
+}
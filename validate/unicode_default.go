@@ -0,0 +1,116 @@
+//go:build !minimaltables
+// +build !minimaltables
+
+package validate
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The Unicode operations that need large tables: normalization, grapheme
+// cluster segmentation, and IDNA host mapping. Builds with the
+// minimaltables tag use the smaller approximations in unicode_minimal.go
+// instead.
+
+var formC = norm.NFC
+
+// The IDNA profile used to validate hosts: the UTS #46 lookup profile,
+// except that underscores are permitted so that they can be allowed in
+// subdomains, and that hyphens are only checked at the ends of labels, as
+// they are by browsers, so that hosts such as r3---sn-abc.example are valid
+var urlHostProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+	idna.StrictDomainName(false),
+	idna.CheckHyphens(false),
+)
+
+// Reuses the iterators used by normalize, which escape to the heap
+var iterPool = sync.Pool{
+	New: func() interface{} { return new(norm.Iter) },
+}
+
+// Returns the NFC form of text. Text that is already normalized, which is
+// the common case, is returned as is. Otherwise, the remainder of the text
+// after the normalized prefix found by the quick check is normalized with
+// a norm.Iter in a single pass, writing directly into the buffer of the
+// returned string instead of an intermediate byte slice.
+//
+// The quick check stops at characters whose NFC quick check property is
+// Maybe, such as combining marks, even when they do not combine with the
+// preceding character, as in Yoruba text. Such text is usually normalized
+// already, so nothing is copied until the iterator returns a segment that
+// differs from the text
+func normalize(text string) string {
+	n := formC.QuickSpanString(text)
+	if n == len(text) {
+		return text
+	}
+
+	var (
+		b       strings.Builder
+		copying bool
+	)
+	it := iterPool.Get().(*norm.Iter)
+	defer func() {
+		// Don't hold on to the text while the iterator is in the pool
+		it.InitString(formC, "")
+		iterPool.Put(it)
+	}()
+	it.InitString(formC, text[n:])
+	for !it.Done() {
+		start := n + it.Pos()
+		segment := it.Next()
+		if !copying {
+			if string(segment) == text[start:n+it.Pos()] {
+				continue
+			}
+			b.Grow(len(text) + utf8.UTFMax)
+			b.WriteString(text[:start])
+			copying = true
+		}
+		b.Write(segment)
+	}
+	if !copying {
+		return text
+	}
+	return b.String()
+}
+
+// Returns the offset of the last place at which b may be split without
+// changing its NFC normalization, or -1 if there is none
+func lastNormalizationBoundary(b []byte) int {
+	return formC.LastBoundary(b)
+}
+
+// Returns the grapheme cluster at the start of s, which must not be empty,
+// and the state to pass when finding the next cluster. The state is -1 at
+// the start of the text
+func firstGraphemeCluster(s string, state int) (string, int) {
+	cluster, _, _, state := uniseg.FirstGraphemeClusterInString(s, state)
+	return cluster, state
+}
+
+// Returns the length of the grapheme cluster at the start of b
+func firstGraphemeClusterLength(b []byte) int {
+	cluster, _, _, _ := uniseg.FirstGraphemeCluster(b, -1)
+	return len(cluster)
+}
+
+// Converts host to its ASCII form for validation, using urlHostProfile
+func hostToASCII(host string) (string, error) {
+	return urlHostProfile.ToASCII(host)
+}
+
+// Converts an ASCII host to its Unicode form for validation, using
+// urlHostProfile
+func hostToUnicode(host string) (string, error) {
+	return urlHostProfile.ToUnicode(host)
+}
@@ -0,0 +1,105 @@
+//go:build minimaltables
+// +build minimaltables
+
+package validate
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Approximations of the Unicode operations in unicode_default.go that need
+// no tables, for builds with the minimaltables tag. ASCII text gives the
+// same results as with the full tables. Other text may not:
+//
+//   - Text is not normalized, so text that is not in NFC, which is rare,
+//     is weighed as written.
+//   - A grapheme cluster is a character followed by any combining
+//     diacritical marks, variation selectors, skin tone modifiers, and
+//     ZERO WIDTH JOINERs with the character after each, a pair of
+//     regional indicators, or CR LF.
+//   - Only hosts written in ASCII are valid URL hosts. Internationalized
+//     domain names must be written in punycode.
+
+// Returns text unchanged. See the note above
+func normalize(text string) string {
+	return text
+}
+
+// Returns the offset of the end of b, since text is not normalized
+func lastNormalizationBoundary(b []byte) int {
+	return len(b)
+}
+
+// Returns the grapheme cluster at the start of s, which must not be empty,
+// and the state to pass when finding the next cluster, which is unused
+func firstGraphemeCluster(s string, state int) (string, int) {
+	return s[:graphemeClusterLength(s)], -1
+}
+
+// Returns the length of the grapheme cluster at the start of b
+func firstGraphemeClusterLength(b []byte) int {
+	return graphemeClusterLength(string(b))
+}
+
+// Returns the length of the approximate grapheme cluster at the start of s
+func graphemeClusterLength(s string) int {
+	if strings.HasPrefix(s, "\r\n") {
+		return 2
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if isRegionalIndicator(r) {
+		if next, size := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(next) {
+			n += size
+		}
+	}
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case r == '\u200D':
+			if n+size < len(s) {
+				_, next := utf8.DecodeRuneInString(s[n+size:])
+				size += next
+			}
+		case '\u0300' <= r && r <= '\u036F', '\uFE00' <= r && r <= '\uFE0F', '\U0001F3FB' <= r && r <= '\U0001F3FF':
+		default:
+			return n
+		}
+		n += size
+	}
+	return n
+}
+
+// Reports whether r is one of the regional indicators that make up flags
+func isRegionalIndicator(r rune) bool {
+	return '\U0001F1E6' <= r && r <= '\U0001F1FF'
+}
+
+// Returned by hostToASCII for hosts that are not valid
+var errInvalidHost = errors.New("invalid host")
+
+// Returns host, lowercased, if it is a valid ASCII host name: each label
+// is between 1 and 63 bytes long, and the whole name at most 253
+func hostToASCII(host string) (string, error) {
+	for i := 0; i < len(host); i++ {
+		if host[i] >= utf8.RuneSelf {
+			return "", errInvalidHost
+		}
+	}
+	if len(host) > 253 {
+		return "", errInvalidHost
+	}
+	for _, label := range strings.Split(host, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return "", errInvalidHost
+		}
+	}
+	return strings.ToLower(host), nil
+}
+
+// Returns host unchanged, since internationalized domain names are not
+// decoded
+func hostToUnicode(host string) (string, error) {
+	return host, nil
+}
@@ -0,0 +1,52 @@
+//go:build minimaltables
+// +build minimaltables
+
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphemeClusterLength(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"ab", 1},
+		{"\r\nx", 2},
+		{"e\u0301\u0308x", 5},
+		{"\U0001F44D\U0001F3FD!", 8},
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467 ", 18},
+		{"\u2764\uFE0F ", 6},
+		{"\U0001F1EF\U0001F1F5\U0001F1FA", 8},
+		{"日本", 3},
+		{"\u200D", 3},
+	}
+
+	for _, test := range tests {
+		if actual := graphemeClusterLength(test.text); actual != test.expected {
+			t.Errorf("graphemeClusterLength returned incorrect value for text [%+q]. Expected:%d Got:%d", test.text, test.expected, actual)
+		}
+	}
+}
+
+func TestMinimalUrlHosts(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://example.com/", true},
+		{"https://EXAMPLE.com/", true},
+		{"https://xn--bcher-kva.example/", true},
+		{"https://bücher.example/", false},
+		{"https://example..com/", false},
+		{"https://" + strings.Repeat("a", 64) + ".com/", false},
+	}
+
+	for _, test := range tests {
+		if actual := ValidateUrl(test.url, true, true) == nil; actual != test.expected {
+			t.Errorf("ValidateUrl returned incorrect value for text [%+q]. Expected:%v Got:%v", test.url, test.expected, actual)
+		}
+	}
+}
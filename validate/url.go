@@ -0,0 +1,381 @@
+package validate
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validation error returned by ValidateUrl. Component is the part of the
+// URL that is invalid: "scheme", "userinfo", "host", "port", "path",
+// "query", or "fragment", or "url" if the URL is not valid UTF-8
+type InvalidUrlError struct {
+	Component string
+}
+
+func (e InvalidUrlError) Error() string {
+	return fmt.Sprintf("Invalid %s in URL", e.Component)
+}
+
+// Checks whether the given text is a valid URL, as UrlIsValid does, by
+// splitting it into its components and checking each one. Returns nil if
+// the URL is valid, or an InvalidUrlError naming the first invalid
+// component.
+//
+// Unlike UrlIsValid, the host is validated with IDNA (UTS #46), so
+// internationalized domain names are checked the way they are resolved,
+// and IPv6 addresses are fully validated. When allowUnicode is true,
+// non-ASCII characters are also allowed in the path, query, and fragment,
+// as they are in an IRI (RFC 3987).
+func ValidateUrl(rawurl string, requireProtocol bool, allowUnicode bool) error {
+	if !utf8.ValidString(rawurl) {
+		return InvalidUrlError{"url"}
+	}
+	c := splitUrl(rawurl)
+
+	if c.hasScheme {
+		if !isUrlScheme(c.scheme) {
+			return InvalidUrlError{"scheme"}
+		}
+	}
+	if requireProtocol && !(c.hasScheme && (strings.EqualFold(c.scheme, "http") || strings.EqualFold(c.scheme, "https"))) {
+		return InvalidUrlError{"scheme"}
+	}
+
+	if err := validateAuthority(c.authority, allowUnicode); err != nil {
+		return err
+	}
+
+	if !isUrlPath(c.path, allowUnicode) {
+		return InvalidUrlError{"path"}
+	}
+	if c.hasQuery && !isUrlQuery(c.query, allowUnicode) {
+		return InvalidUrlError{"query"}
+	}
+	if c.hasFragment && !isUrlQuery(c.fragment, allowUnicode) {
+		return InvalidUrlError{"fragment"}
+	}
+	return nil
+}
+
+// The components of a URL, split as in RFC 3986 Appendix B
+type urlComponents struct {
+	scheme, authority, path, query, fragment string
+	hasScheme, hasQuery, hasFragment         bool
+}
+
+// Splits a URL into its components without validating them. A scheme is
+// only recognized if it is followed by "://"
+func splitUrl(rawurl string) urlComponents {
+	var c urlComponents
+	if i := strings.Index(rawurl, "://"); i > 0 && !strings.ContainsAny(rawurl[:i], ":/?#") {
+		c.scheme, c.hasScheme = rawurl[:i], true
+		rawurl = rawurl[i+len("://"):]
+	}
+	if i := strings.IndexByte(rawurl, '#'); i >= 0 {
+		c.fragment, c.hasFragment = rawurl[i+1:], true
+		rawurl = rawurl[:i]
+	}
+	if i := strings.IndexByte(rawurl, '?'); i >= 0 {
+		c.query, c.hasQuery = rawurl[i+1:], true
+		rawurl = rawurl[:i]
+	}
+	if i := strings.IndexByte(rawurl, '/'); i >= 0 {
+		c.authority, c.path = rawurl[:i], rawurl[i:]
+	} else {
+		c.authority = rawurl
+	}
+	return c
+}
+
+// Reports whether scheme matches [a-z][a-z0-9+\-.]*
+func isUrlScheme(scheme string) bool {
+	for i := 0; i < len(scheme); i++ {
+		c := scheme[i]
+		if !isAsciiLetter(c) && (i == 0 || !(isAsciiDigit(c) || c == '+' || c == '-' || c == '.')) {
+			return false
+		}
+	}
+	return scheme != ""
+}
+
+// Validates the [userinfo@]host[:port] authority of a URL
+func validateAuthority(authority string, allowUnicode bool) error {
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		if !isUrlChars(authority[:i], false, ":") {
+			return InvalidUrlError{"userinfo"}
+		}
+		authority = authority[i+1:]
+	}
+
+	host := authority
+	if i := strings.LastIndexByte(authority, ':'); i >= 0 && !strings.Contains(authority[i:], "]") {
+		host = authority[:i]
+		port, err := strconv.Atoi(authority[i+1:])
+		if err != nil || len(authority[i+1:]) > 5 || port < 0 || port > 65535 || !isAsciiDigit(authority[i+1]) {
+			return InvalidUrlError{"port"}
+		}
+	}
+
+	if !isUrlHost(host, allowUnicode) {
+		return InvalidUrlError{"host"}
+	}
+	return nil
+}
+
+// Reports whether host is an IP address or a valid domain name
+func isUrlHost(host string, allowUnicode bool) bool {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		ip := host[1 : len(host)-1]
+		return strings.Contains(ip, ":") && net.ParseIP(ip) != nil
+	}
+	if host != "" && strings.Trim(host, "0123456789.") == "" {
+		return strings.Count(host, ".") == 3 && net.ParseIP(host) != nil
+	}
+
+	for _, r := range host {
+		if r >= utf8.RuneSelf && !allowUnicode {
+			return false
+		}
+	}
+	ascii, err := hostToASCII(host)
+	if err != nil {
+		return false
+	}
+	unicodeHost, err := hostToUnicode(ascii)
+	if err != nil {
+		return false
+	}
+	for _, label := range strings.Split(unicodeHost, ".") {
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+	}
+
+	// A domain has at least two labels. The top-level domain starts with
+	// a letter, and only subdomains may contain underscores
+	labels := strings.Split(ascii, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for i, label := range labels {
+		tld, subdomain := i == len(labels)-1, i < len(labels)-2
+		if !isDomainLabel(label, subdomain) || (tld && !isAsciiLetter(label[0])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether label, an ASCII domain label, starts and ends with a
+// letter or digit and otherwise contains only letters, digits, hyphens,
+// and, if it is a subdomain, underscores
+func isDomainLabel(label string, subdomain bool) bool {
+	if label == "" || !isAsciiAlnum(label[0]) || !isAsciiAlnum(label[len(label)-1]) {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !isAsciiAlnum(c) && c != '-' && !(subdomain && c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether path is empty or consists of segments of pchars, each
+// preceded by a '/'
+func isUrlPath(path string, allowUnicode bool) bool {
+	return (path == "" || path[0] == '/') && isUrlChars(path, allowUnicode, ":@/")
+}
+
+// Reports whether a query or fragment consists of pchars, '/', and '?'
+func isUrlQuery(query string, allowUnicode bool) bool {
+	return isUrlChars(query, allowUnicode, ":@/?")
+}
+
+// Reports whether s consists of unreserved characters, percent-encoded
+// bytes, sub-delims, and the given extra characters. Non-ASCII characters
+// are unreserved if allowUnicode is true
+func isUrlChars(s string, allowUnicode bool, extra string) bool {
+	for _, r := range s {
+		switch {
+		case r >= utf8.RuneSelf:
+			if !allowUnicode || !isIriChar(r) {
+				return false
+			}
+		case isAsciiAlnum(byte(r)), strings.ContainsRune("-._~!$&'()*+,;=%", r), strings.ContainsRune(extra, r):
+		default:
+			return false
+		}
+	}
+	// Every % must start a valid percent-encoding
+	if strings.Contains(s, "%") {
+		if _, err := url.PathUnescape(s); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether r is a ucschar, a non-ASCII character allowed in an IRI
+// by RFC 3987
+func isIriChar(r rune) bool {
+	switch {
+	case 0xA0 <= r && r <= 0xD7FF, 0xF900 <= r && r <= 0xFDCF, 0xFDF0 <= r && r <= 0xFFEF:
+		return true
+	case 0x10000 <= r && r <= 0xEFFFD:
+		// Excluding the noncharacters at the end of each plane
+		return r&0xFFFF <= 0xFFFD
+	}
+	return false
+}
+
+// The component parsers used by UrlIsValid follow. They are stricter than
+// RFC 3986 in the same ways as the patterns of the twitter-text libraries:
+// domain names must have a top-level domain that starts with a letter,
+// and only subdomains may contain underscores. They are also more lenient
+// in the same ways: | is allowed as a pchar, and the contents of an IPv6
+// address are not checked.
+
+// Reports whether path is empty or consists of segments of pchars, each
+// preceded by a '/'
+func isRfcPath(path string) bool {
+	return (path == "" || path[0] == '/') && isRfcChars(path, ":@|/")
+}
+
+// Reports whether a query or fragment consists of pchars, '/', and '?'
+func isRfcQuery(query string) bool {
+	return isRfcChars(query, ":@|/?")
+}
+
+// Reports whether s consists of ASCII unreserved characters,
+// percent-encoded bytes, sub-delims, and the given extra characters
+func isRfcChars(s string, extra string) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case isAsciiAlnum(c), strings.IndexByte("-._~!$&'()*+,;=", c) >= 0, strings.IndexByte(extra, c) >= 0:
+		case c == '%' && i+2 < len(s) && isHexDigit(s[i+1]) && isHexDigit(s[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether authority is [userinfo@]host[:port], where host is an IP
+// address or a domain name, which may contain non-ASCII characters if
+// allowUnicode is true
+func isRfcAuthority(authority string, allowUnicode bool) bool {
+	if i := strings.IndexByte(authority, '@'); i >= 0 {
+		if !isRfcChars(authority[:i], ":") {
+			return false
+		}
+		authority = authority[i+1:]
+	}
+
+	host := authority
+	if i := strings.LastIndexByte(authority, ':'); i >= 0 && !strings.Contains(authority[i:], "]") {
+		host = authority[:i]
+		if port := authority[i+1:]; port == "" || len(port) > 5 || strings.Trim(port, "0123456789") != "" {
+			return false
+		}
+	}
+
+	switch {
+	case len(host) > 2 && host[0] == '[' && host[len(host)-1] == ']':
+		return strings.Trim(host[1:len(host)-1], "0123456789abcdefABCDEF:.") == ""
+	case isIpv4(host):
+		return true
+	}
+
+	// (subdomain.)*domain.tld
+	labels := strings.Count(host, ".") + 1
+	if labels < 2 {
+		return false
+	}
+	for i := 0; i < labels; i++ {
+		label := host
+		if j := strings.IndexByte(host, '.'); j >= 0 {
+			label, host = host[:j], host[j+1:]
+		}
+		tld, subdomain := i == labels-1, i < labels-2
+		if !isRfcLabel(label, subdomain, tld, allowUnicode) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether label is a valid label of a domain name: it starts and
+// ends with a letter or digit, the first character of a top-level domain
+// is a letter, and it otherwise contains only letters, digits, hyphens,
+// and, in a subdomain, underscores. Non-ASCII characters are allowed
+// anywhere if allowUnicode is true
+func isRfcLabel(label string, subdomain, tld, allowUnicode bool) bool {
+	if label == "" {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		switch {
+		case c >= utf8.RuneSelf:
+			if !allowUnicode {
+				return false
+			}
+		case i == 0 && tld:
+			if !isAsciiLetter(c) {
+				return false
+			}
+		case i == 0 || i == len(label)-1:
+			if !isAsciiAlnum(c) {
+				return false
+			}
+		case !isAsciiAlnum(c) && c != '-' && !(subdomain && c == '_'):
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether host is a dotted-decimal IPv4 address, without leading
+// zeros
+func isIpv4(host string) bool {
+	for i := 0; i < 4; i++ {
+		octet := host
+		if i < 3 {
+			j := strings.IndexByte(host, '.')
+			if j < 0 {
+				return false
+			}
+			octet, host = host[:j], host[j+1:]
+		}
+		if octet == "" || len(octet) > 3 || (len(octet) > 1 && octet[0] == '0') || strings.Trim(octet, "0123456789") != "" {
+			return false
+		}
+		if n, _ := strconv.Atoi(octet); n > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexDigit(c byte) bool {
+	return isAsciiDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
+func isAsciiLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isAsciiDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isAsciiAlnum(c byte) bool {
+	return isAsciiLetter(c) || isAsciiDigit(c)
+}
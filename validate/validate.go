@@ -3,28 +3,107 @@ package validate
 
 import (
 	"fmt"
+	"math/bits"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"unicode/utf8"
 
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/emoji"
 	"github.com/kylemcc/twitter-text-go/extract"
-	"golang.org/x/text/unicode/norm"
 )
 
 const (
-	maxLength           = 140
-	shortUrlLength      = 23
-	shortHttpsUrlLength = 23
-	invalidChars        = "\uFFFE\uFEFF\uFFFF\u202A\u202B\u202C\u202D\u202E"
+	invalidChars = "\uFFFE\uFEFF\uFFFF\u202A\u202B\u202C\u202D\u202E"
 )
 
-var formC = norm.NFC
+// Holds the *config.Config used when no configuration is passed with
+// WithConfig. Initially, this is the version 1 configuration, which
+// counts every character as one, with a limit of 140 characters. A stored
+// configuration is never modified or handed to callers, so it may be read
+// by any number of goroutines without locking
+var defaultConfig atomic.Value
+
+func init() {
+	defaultConfig.Store(config.V1())
+}
+
+// Returns a copy of the configuration used when no configuration is
+// passed with WithConfig. Modifying the copy does not change the default
+// configuration; use SetDefaultConfig.
+func DefaultConfig() *config.Config {
+	return loadDefaultConfig().Clone()
+}
+
+// Returns the stored default configuration, which must not be modified
+func loadDefaultConfig() *config.Config {
+	return defaultConfig.Load().(*config.Config)
+}
+
+// Replaces the configuration used when no configuration is passed with
+// WithConfig, e.g. to raise the maximum tweet length. The configuration is
+// checked with its Validate method first; if it is invalid, the error is
+// returned and the default configuration is unchanged. It is safe to call
+// SetDefaultConfig while tweets are being validated concurrently; each
+// call of a validation function uses either the old or the new
+// configuration throughout. A copy of c is stored, so c may be modified
+// afterwards.
+func SetDefaultConfig(c *config.Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	defaultConfig.Store(c.Clone())
+	return nil
+}
+
+// An Option overrides a setting for a single call of TweetLength,
+// TweetIsValid, ValidateTweet, ParseTweet, or ParseTweets
+type Option func(*options)
+
+type options struct {
+	config      *config.Config
+	concurrency int
+}
+
+// Sets the configuration used to compute the length of a tweet, in place
+// of the default configuration. This allows a single process to validate
+// tweets for several products with different rules concurrently. The
+// configuration may be shared by concurrent calls, but must not be
+// modified while a call that uses it is in progress; NewValidator stores a
+// copy of it.
+func WithConfig(c *config.Config) Option {
+	return func(o *options) { o.config = c }
+}
+
+// Reuses options between calls, so that passing options does not
+// allocate
+var optionsPool = sync.Pool{
+	New: func() interface{} { return new(options) },
+}
+
+// Returns the configuration to use for a call with the given options
+func configFor(opts []Option) *config.Config {
+	if len(opts) == 0 {
+		return loadDefaultConfig()
+	}
+	o := optionsPool.Get().(*options)
+	o.config = loadDefaultConfig()
+	for _, opt := range opts {
+		opt(o)
+	}
+	c := o.config
+	*o = options{}
+	optionsPool.Put(o)
+	return c
+}
 
 // Validation error returned when text is too long to be a valid tweet.
 // The value of the error is the actual length of the input string
 type TooLongError int
 
 func (e TooLongError) Error() string {
-	return fmt.Sprintf("Length %d exceeds %d characters", int(e), maxLength)
+	return fmt.Sprintf("Length %d exceeds the maximum tweet length", int(e))
 }
 
 // Validation error returned when text is empty
@@ -58,24 +137,228 @@ func (e InvalidCharacterError) Error() string {
 //     … The NFC of {U+0065, U+0301} is {U+00E9}, which is a single character and a +display_length+ of 1
 //
 // The string could also contain U+00E9 already, in which case the canonicalization will not change the value.
-func TweetLength(text string) int {
-	length := utf8.RuneCountInString(formC.String(text))
-
-	urls := extract.ExtractUrls(text)
-	for _, url := range urls {
-		length -= url.Range.Length()
-		if strings.HasPrefix(url.Text, "https://") {
-			length += shortHttpsUrlLength
+//
+// Each character is weighted according to the configuration in use, and
+// each URL, with or without a protocol, counts as the configuration's
+// TransformedURLLength (the length of a t.co URL).
+func TweetLength(text string, opts ...Option) int {
+	return weightedLength(text, configFor(opts))
+}
+
+// Returns the weighted length of text under the given configuration
+func weightedLength(text string, c *config.Config) int {
+	normalized := normalize(text)
+	var urls []extract.TwitterEntity
+	if !c.CountURLText {
+		urls = extract.AppendUrls(nil, normalized)
+	}
+	length, _ := measure(normalized, urls, c)
+	return length
+}
+
+// Returns the weighted length of normalized text containing the given URLs
+// under the given configuration, and the byte offset within the text of
+// its first invalid character, or -1 if there is none. Both are found in
+// the same pass over the text. The URLs are ignored if the configuration
+// counts URLs as text; they cannot contain invalid characters
+func measure(normalized string, urls []extract.TwitterEntity, c *config.Config) (int, int) {
+	weight, invalid := measureWeight(normalized, urls, c)
+	return scaledLength(weight, c), invalid
+}
+
+// Returns the weight of normalized text as measure does, before it is
+// divided by the configuration's scale
+func measureWeight(normalized string, urls []extract.TwitterEntity, c *config.Config) (int64, int) {
+	if c.CountURLText {
+		return charactersWeight(normalized, c)
+	}
+
+	var weighted int64
+	invalid := -1
+	offset := 0
+	for k := range urls {
+		url := &urls[k]
+		weight, i := charactersWeight(normalized[offset:url.ByteRange.Start], c)
+		if invalid < 0 && i >= 0 {
+			invalid = offset + i
+		}
+		weighted += weight + int64(c.TransformedURLLength)*int64(c.Scale)
+		offset = url.ByteRange.Stop
+	}
+	weight, i := charactersWeight(normalized[offset:], c)
+	if invalid < 0 && i >= 0 {
+		invalid = offset + i
+	}
+	return weighted + weight, invalid
+}
+
+// Returns the NFC form of text, which is what weighted lengths are computed
+// from. See normalize. Builds with the minimaltables tag do not normalize
+// text and return it unchanged
+func Normalize(text string) string {
+	return normalize(text)
+}
+
+// Returns the sum of the weights of the characters in s, and the byte
+// offset of the first invalid character in s, or -1 if there is none. If
+// emoji parsing is enabled, each emoji counts as a single character with
+// the default weight, regardless of the number of characters it is made
+// of. If grapheme clusters are counted, each cluster counts as a single
+// character with the weight of its first character
+func charactersWeight(s string, c *config.Config) (int64, int) {
+	if isASCII(s) {
+		// All of the invalid characters are non-ASCII
+		return asciiWeight(s, c), -1
+	}
+	return unicodeWeight(s, c)
+}
+
+// Reports whether r is one of invalidChars
+func isInvalidChar(r rune) bool {
+	switch r {
+	case '\uFFFE', '\uFEFF', '\uFFFF':
+		return true
+	}
+	return '\u202A' <= r && r <= '\u202E'
+}
+
+// Returns the sum of the weights of the characters in s, which may contain
+// any characters, and the offset of the first invalid character in s, or
+// -1 if there is none
+func unicodeWeight(s string, c *config.Config) (int64, int) {
+	w := c.Weigher()
+	var weight int64
+	invalid := -1
+	state := -1
+	for i := 0; i < len(s); {
+		if c.EmojiParsingEnabled {
+			if n := emoji.Match(s[i:]); n > 0 {
+				weight += int64(c.DefaultWeight)
+				i += n
+				state = -1
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if invalid < 0 && isInvalidChar(r) {
+			invalid = i
+		}
+		if c.CountGraphemeClusters {
+			var cluster string
+			cluster, state = firstGraphemeCluster(s[i:], state)
+			// The rest of the cluster is not weighed, but may contain
+			// an invalid character
+			if invalid < 0 && len(cluster) > size {
+				if j := strings.IndexAny(cluster[size:], invalidChars); j >= 0 {
+					invalid = i + size + j
+				}
+			}
+			size = len(cluster)
+		}
+		weight += int64(w.Weight(r))
+		i += size
+	}
+	return weight, invalid
+}
+
+// Returns the length in UTF-16 code units of the longest prefix of
+// normalized text containing the given URLs that contains no invalid
+// character and is within the configuration's maximum length when it
+// follows text of the given weight, before it is divided by the
+// configuration's scale, and whether that prefix is all of the text. The
+// text is divided into URLs, emoji, and characters or grapheme clusters as
+// measureWeight divides it, and the prefix ends at the end of one of them
+func validPrefix(normalized string, urls []extract.TwitterEntity, weight int64, c *config.Config) (int, bool) {
+	max := int64(c.MaxWeightedTweetLength) * int64(c.Scale)
+	w := c.Weigher()
+	units := 0
+	state := -1
+	for i := 0; i < len(normalized); {
+		var size int
+		if !c.CountURLText && len(urls) > 0 && urls[0].ByteRange.Start == i {
+			size = urls[0].ByteRange.Stop - i
+			weight += int64(c.TransformedURLLength) * int64(c.Scale)
+			urls = urls[1:]
+			state = -1
+		} else if n := emojiLength(normalized[i:], c); n > 0 {
+			size = n
+			weight += int64(c.DefaultWeight)
+			state = -1
 		} else {
-			length += shortUrlLength
+			var r rune
+			r, size = utf8.DecodeRuneInString(normalized[i:])
+			if c.CountGraphemeClusters {
+				var cluster string
+				cluster, state = firstGraphemeCluster(normalized[i:], state)
+				size = len(cluster)
+			}
+			weight += int64(w.Weight(r))
+		}
+		unit := normalized[i : i+size]
+		if weight > max || strings.ContainsAny(unit, invalidChars) {
+			return units, false
 		}
+		units += utf16Length(unit)
+		i += size
 	}
-	return length
+	return units, true
+}
+
+// Returns the length of the emoji at the start of s, or 0 if there is none
+// or emoji parsing is disabled
+func emojiLength(s string, c *config.Config) int {
+	if !c.EmojiParsingEnabled {
+		return 0
+	}
+	return emoji.Match(s)
+}
+
+// Returns the length of s in UTF-16 code units, which is how the
+// reference implementations measure the ranges in ParseResults
+func utf16Length(s string) int {
+	if isASCII(s) {
+		return len(s)
+	}
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// Reports whether s consists only of ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the sum of the weights of the characters in s, which must be
+// ASCII. ASCII text contains no emoji, and the only grapheme cluster made
+// of more than one ASCII character is CR LF, so the characters can be
+// weighed directly
+func asciiWeight(s string, c *config.Config) int64 {
+	w := c.Weigher()
+	var weight int64
+	for i := 0; i < len(s); i++ {
+		if c.CountGraphemeClusters && s[i] == '\n' && i > 0 && s[i-1] == '\r' {
+			continue
+		}
+		weight += int64(w.Weight(rune(s[i])))
+	}
+	return weight
 }
 
 // Checks whether a string is a valid tweet and returns true or false
-func TweetIsValid(text string) bool {
-	err := ValidateTweet(text)
+func TweetIsValid(text string, opts ...Option) bool {
+	err := ValidateTweet(text, opts...)
 	return err == nil
 }
 
@@ -85,18 +368,226 @@ func TweetIsValid(text string) bool {
 // - The text is too long
 // - The text is empty
 // - The text contains invalid characters
-func ValidateTweet(text string) error {
+func ValidateTweet(text string, opts ...Option) error {
+	return ValidateTweetWithConfig(text, configFor(opts))
+}
+
+// Checks whether a string is a valid tweet under the given configuration.
+// Returns nil if the string is valid, or an error as described for
+// ValidateTweet. Equivalent to ValidateTweet(text, WithConfig(c)).
+func ValidateTweetWithConfig(text string, c *config.Config) error {
+	_, err := validateTweet(text, c)
+	return err
+}
+
+// Returns the weighted length of text and the error, if any, that makes it
+// an invalid tweet. The text is normalized and weighed once, and invalid
+// characters are found in the same pass
+func validateTweet(text string, c *config.Config) (int, error) {
+	normalized := normalize(text)
+	var urls []extract.TwitterEntity
+	if !c.CountURLText {
+		urls = extract.AppendUrls(nil, normalized)
+	}
+	length, invalid := measure(normalized, urls, c)
+	return length, checkTweet(text, normalized, length, invalid, c)
+}
+
+// Returns the error, if any, that makes text an invalid tweet, given its
+// normalized form, its weighted length, and the offset of the first invalid
+// character in its normalized form, as returned by measure
+func checkTweet(text, normalized string, length, invalid int, c *config.Config) error {
 	if text == "" {
 		return EmptyError{}
-	} else if length := TweetLength(text); length > maxLength {
+	} else if length > c.MaxWeightedTweetLength {
 		return TooLongError(length)
-	} else if i := strings.IndexAny(text, invalidChars); i > -1 {
-		r, _ := utf8.DecodeRuneInString(text[i:])
-		return InvalidCharacterError{Offset: i, Character: r}
+	}
+
+	// Normalization does not add or remove invalid characters, but it
+	// does move them
+	if invalid >= 0 && normalized != text {
+		invalid = strings.IndexAny(text, invalidChars)
+	}
+	if invalid >= 0 {
+		r, _ := utf8.DecodeRuneInString(text[invalid:])
+		return InvalidCharacterError{Offset: invalid, Character: r}
 	}
 	return nil
 }
 
+// The results of parsing a tweet. As in the reference implementations,
+// the ranges are of offsets into the text in UTF-16 code units, and
+// include both ends
+type ParseResults struct {
+	WeightedLength int  // The weighted length of the tweet
+	Permillage     int  // The weighted length as a proportion of the maximum length, in thousandths
+	IsValid        bool // Whether the tweet is valid
+
+	DisplayRangeStart int // The range of the text to display: all of it
+	DisplayRangeEnd   int
+	ValidRangeStart   int // The range of the longest prefix of the text that is a valid tweet
+	ValidRangeEnd     int
+}
+
+// Parses a tweet, returning its weighted length and whether it is valid
+func ParseTweet(text string, opts ...Option) ParseResults {
+	return ParseTweetWithConfig(text, configFor(opts))
+}
+
+// Parses a tweet under the given configuration, returning its weighted
+// length and whether it is valid. Equivalent to
+// ParseTweet(text, WithConfig(c)).
+func ParseTweetWithConfig(text string, c *config.Config) ParseResults {
+	normalized := normalize(text)
+	var urls []extract.TwitterEntity
+	if !c.CountURLText {
+		urls = extract.AppendUrls(nil, normalized)
+	}
+	length, invalid := measure(normalized, urls, c)
+	err := checkTweet(text, normalized, length, invalid, c)
+	return parseResults(text, normalized, urls, length, err, c)
+}
+
+// Parses a tweet under the given configuration given its NFC form (see
+// Normalize) and the URLs extract.ExtractUrls finds in that form, for
+// callers that have already computed them. The URLs are ignored if the
+// configuration counts URLs as text. Returns the results of
+// ParseTweetWithConfig(text, c) and the error ValidateTweetWithConfig(text,
+// c) would return.
+func ParseNormalizedTweet(text, normalized string, urls []*extract.TwitterEntity, c *config.Config) (ParseResults, error) {
+	var values []extract.TwitterEntity
+	if !c.CountURLText && len(urls) > 0 {
+		values = make([]extract.TwitterEntity, len(urls))
+		for i, url := range urls {
+			values[i] = *url
+		}
+	}
+	length, invalid := measure(normalized, values, c)
+	err := checkTweet(text, normalized, length, invalid, c)
+	return parseResults(text, normalized, values, length, err, c), err
+}
+
+// A tweet parsed by ParseTweetInto. The memory a Tweet holds is reused by
+// each call, so a Tweet must not be used by more than one goroutine at a
+// time
+type Tweet struct {
+	Text       string
+	Normalized string // The NFC form of Text. See Normalize
+	ParseResults
+	Err error // The error ValidateTweet returns for Text
+
+	urls []extract.TwitterEntity // the URLs in Normalized
+}
+
+// Parses a tweet as ParseTweet does, storing the results in out, for
+// services that parse many tweets and want to avoid allocating for each
+// of them. If entities is not nil, the entities extract.ExtractEntities
+// returns for text are stored in it, reusing its memory. For example:
+//
+//	var (
+//		t        validate.Tweet
+//		entities []extract.TwitterEntity
+//	)
+//	for _, text := range texts {
+//		validate.ParseTweetInto(text, &t, &entities)
+//		...
+//	}
+//
+// Once out and entities have grown large enough, parsing a valid tweet in
+// NFC does not allocate, unless the extract package is built with the
+// regexpextract tag.
+func ParseTweetInto(text string, out *Tweet, entities *[]extract.TwitterEntity, opts ...Option) {
+	c := configFor(opts)
+	normalized := normalize(text)
+	out.urls = out.urls[:0]
+	if !c.CountURLText {
+		out.urls = extract.AppendUrls(out.urls, normalized)
+	}
+	length, invalid := measure(normalized, out.urls, c)
+
+	out.Text, out.Normalized = text, normalized
+	out.Err = checkTweet(text, normalized, length, invalid, c)
+	out.ParseResults = parseResults(text, normalized, out.urls, length, out.Err, c)
+	if entities != nil {
+		*entities = extract.AppendEntities((*entities)[:0], text)
+	}
+}
+
+// Returns the ParseResults for a tweet given its text, its NFC form and the
+// URLs in that form, its weighted length, and its validation error
+func parseResults(text, normalized string, urls []extract.TwitterEntity, length int, err error, c *config.Config) ParseResults {
+	units := utf16Length(text)
+	normalizedUnits := units
+	if normalized != text {
+		normalizedUnits = utf16Length(normalized)
+	}
+	// All of a valid tweet is valid
+	valid := normalizedUnits
+	if err != nil {
+		valid, _ = validPrefix(normalized, urls, 0, c)
+	}
+	return newParseResults(length, err, units, normalizedUnits, valid, c)
+}
+
+// Returns the ParseResults for a tweet with the given weighted length and
+// validation error, whose text and NFC form are the given numbers of UTF-16
+// code units long, and the valid prefix of whose NFC form is valid code
+// units long. Like the reference implementations, the end of the valid
+// range is moved by the difference the normalization makes to the length
+func newParseResults(length int, err error, units, normalizedUnits, valid int, c *config.Config) ParseResults {
+	results := ParseResults{WeightedLength: length, IsValid: err == nil}
+	if c.MaxWeightedTweetLength > 0 {
+		results.Permillage = permillage(length, c.MaxWeightedTweetLength)
+	}
+	if units > 0 {
+		results.DisplayRangeEnd = units - 1
+	}
+	if valid > 0 {
+		results.ValidRangeEnd = valid - 1
+	}
+	results.ValidRangeEnd += units - normalizedUnits
+	return results
+}
+
+// Weights are summed as int64, so that they are exact on 32-bit platforms
+// too, and divided exactly. Both divisions round down, as the other
+// twitter-text implementations do. Some of them compute the permillage in
+// floating point, which can come out one lower than the exact value for
+// lengths beyond the maximum, but never for lengths up to it.
+
+// The largest int
+const maxInt = int(^uint(0) >> 1)
+
+// Returns the weighted length of text with the given total weight: the
+// weight divided by the configuration's scale, rounded down. A length that
+// does not fit in an int is clamped to the largest int
+func scaledLength(weight int64, c *config.Config) int {
+	length := weight / int64(c.Scale)
+	if length > int64(maxInt) {
+		return maxInt
+	}
+	return int(length)
+}
+
+// Returns length as a proportion of max, which must be positive, in
+// thousandths, rounded down. length * 1000 / max, but computed as the
+// thousandths of the whole and fractional parts of length / max so that
+// it cannot overflow. A permillage that does not fit in an int is clamped
+// to the largest int
+func permillage(length, max int) int {
+	whole, fraction := length/max, length%max
+	if whole > maxInt/1000 {
+		return maxInt
+	}
+	hi, lo := bits.Mul64(uint64(fraction), 1000)
+	// fraction < max, so the quotient fits in 64 bits and is less than 1000
+	thousandths, _ := bits.Div64(hi, lo, uint64(max))
+	if int(thousandths) > maxInt-whole*1000 {
+		return maxInt
+	}
+	return whole*1000 + int(thousandths)
+}
+
 // Returns true if the given text represents a valid @username
 func UsernameIsValid(username string) bool {
 	if username == "" {
@@ -140,44 +631,20 @@ func UrlIsValid(url string, requireProtocol bool, allowUnicode bool) bool {
 		return false
 	}
 
-	match := validateUrlUnencodedRe.FindStringSubmatchIndex(url)
-	if match == nil || url[match[0]:match[1]] != url {
+	// The URL is split into its components as in RFC 3986 Appendix B, and
+	// each component is checked against the ABNF in the RFC
+	c := splitUrl(url)
+	if requireProtocol && !(c.hasScheme && (strings.EqualFold(c.scheme, "http") || strings.EqualFold(c.scheme, "https"))) {
 		return false
 	}
-
-	if requireProtocol {
-		schemeStart := match[validateUrlUnencodedGroupScheme*2]
-		schemeEnd := match[validateUrlUnencodedGroupScheme*2+1]
-		if !protocolRe.MatchString(url[schemeStart:schemeEnd]) {
-			return false
-		}
-	}
-
-	pathStart := match[validateUrlUnencodedGroupPath*2]
-	pathEnd := match[validateUrlUnencodedGroupPath*2+1]
-	if !validateUrlPathRe.MatchString(url[pathStart:pathEnd]) {
+	if !isRfcPath(c.path) {
 		return false
 	}
-
-	queryStart := match[validateUrlUnencodedGroupQuery*2]
-	queryEnd := match[validateUrlUnencodedGroupQuery*2+1]
-	if queryStart > 0 && !validateUrlQueryRe.MatchString(url[queryStart:queryEnd]) {
+	if c.hasQuery && !isRfcQuery(c.query) {
 		return false
 	}
-
-	fragmentStart := match[validateUrlUnencodedGroupFragment*2]
-	fragmentEnd := match[validateUrlUnencodedGroupFragment*2+1]
-	if fragmentStart > 0 && !validateUrlFragmentRe.MatchString(url[fragmentStart:fragmentEnd]) {
+	if c.hasFragment && !isRfcQuery(c.fragment) {
 		return false
 	}
-
-	authorityStart := match[validateUrlUnencodedGroupAuthority*2]
-	authorityEnd := match[validateUrlUnencodedGroupAuthority*2+1]
-	authority := url[authorityStart:authorityEnd]
-
-	if allowUnicode {
-		return validateUrlUnicodeAuthorityRe.MatchString(authority)
-	} else {
-		return validateUrlAuthorityRe.MatchString(authority)
-	}
+	return isRfcAuthority(c.authority, allowUnicode)
 }
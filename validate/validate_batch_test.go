@@ -0,0 +1,72 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+)
+
+// Returns n tweets of varying lengths and validity
+func batchTexts(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		switch i % 4 {
+		case 0:
+			texts[i] = strings.Repeat("a", i%300)
+		case 1:
+			texts[i] = "see http://example.com/" + strings.Repeat("日", i%200)
+		case 2:
+			texts[i] = "invalid ￾ character"
+		default:
+			texts[i] = "a tweet with a #hashtag"
+		}
+	}
+	return texts
+}
+
+func TestParseTweets(t *testing.T) {
+	c := config.V3()
+	for _, n := range []int{0, 1, 63, 64, 65, 1000} {
+		texts := batchTexts(n)
+		for _, concurrency := range []int{0, 1, 3, 100} {
+			batch := ParseTweets(texts, WithConfig(c), WithConcurrency(concurrency))
+
+			expected := BatchResults{Results: make([]ParseResults, n)}
+			for i, text := range texts {
+				expected.Results[i] = ParseTweet(text, WithConfig(c))
+				expected.add(expected.Results[i])
+			}
+			if !reflect.DeepEqual(batch, expected) {
+				t.Errorf("ParseTweets returned incorrect value for %d tweets with concurrency %d. Expected:%+v Got:%+v",
+					n, concurrency, expected, batch)
+			}
+		}
+	}
+}
+
+func TestParseTweetsStatistics(t *testing.T) {
+	batch := ParseTweets([]string{"valid", "", strings.Repeat("a", 141), "also valid"})
+	if batch.Valid != 2 || batch.Invalid != 2 || batch.MaxWeightedLength != 141 {
+		t.Errorf("ParseTweets returned incorrect statistics. Expected:{Valid:2 Invalid:2 MaxWeightedLength:141} Got:%+v", batch)
+	}
+}
+
+func BenchmarkParseTweet(b *testing.B) {
+	texts := batchTexts(10000)
+	c := config.V3()
+	for i := 0; i < b.N; i++ {
+		for _, text := range texts {
+			ParseTweet(text, WithConfig(c))
+		}
+	}
+}
+
+func BenchmarkParseTweets(b *testing.B) {
+	texts := batchTexts(10000)
+	c := config.V3()
+	for i := 0; i < b.N; i++ {
+		ParseTweets(texts, WithConfig(c))
+	}
+}
@@ -1,46 +1,134 @@
 package validate
 
 import (
-	"io/ioutil"
+	"strings"
 	"testing"
 
-	goyaml "gopkg.in/yaml.v1"
+	"github.com/kylemcc/twitter-text-go/config"
+	"golang.org/x/text/unicode/norm"
 )
 
-func TestTweetLength(t *testing.T) {
-	contents, err := ioutil.ReadFile(validateYmlPath)
-	if err != nil {
-		t.Errorf("Error reading validate.yml: %v", err)
-		t.FailNow()
+func TestWeightedLength(t *testing.T) {
+	if minimalTables {
+		t.Skip("text is not normalized without the full tables")
 	}
 
-	var testData map[interface{}]interface{}
-	err = goyaml.Unmarshal(contents, &testData)
-	if err != nil {
-		t.Fatalf("error unmarshaling data: %v\n", err)
+	tests := []struct {
+		text     string
+		c        *config.Config
+		expected int
+	}{
+		{"hello", config.V1(), 5},
+		{"日本語", config.V1(), 3},
+		{"hello", config.V2(), 5},
+		{"日本語", config.V2(), 6},
+		{"http://example.com 日本", config.V2(), 28},
+		{"caf\u0065\u0301 \u2018quoted\u2019", config.V2(), 13},
+		{"see https://example.com/a/very/long/path", &config.Config{Scale: 1, DefaultWeight: 1, TransformedURLLength: 11}, 15},
 	}
 
-	tests, ok := testData["tests"]
-	if !ok {
-		t.Errorf("Conformance file was not in expected format.")
-		t.FailNow()
+	for _, test := range tests {
+		actual := weightedLength(test.text, test.c)
+		if actual != test.expected {
+			t.Errorf("weightedLength returned incorrect value for text [%s] and version %d. Expected:%d Got:%d", test.text, test.c.Version, test.expected, actual)
+		}
+	}
+}
+
+// Every way of weighing a tweet counts URLs, with or without a protocol,
+// as the configuration's TransformedURLLength rather than 23
+func TestTransformedURLLength(t *testing.T) {
+	c := config.V3()
+	c.TransformedURLLength = 5
+	text := strings.Repeat("a", 265) + " http://example.com/a/long/path example.org"
+	expected := ParseResults{277, 989, true, 0, 307, 0, 307}
+
+	if actual := TweetLength(text, WithConfig(c)); actual != expected.WeightedLength {
+		t.Errorf("TweetLength returned incorrect value for text [%s]. Expected:%d Got:%d", text, expected.WeightedLength, actual)
+	}
+	if actual := TweetLength(text, WithConfig(config.V3())); actual != 313 {
+		t.Errorf("TweetLength returned incorrect value for text [%s] and version 3. Expected:%d Got:%d", text, 313, actual)
+	}
+	if err := ValidateTweet(text, WithConfig(c)); err != nil {
+		t.Errorf("ValidateTweet returned incorrect value for text [%s]. Expected:<nil> Got:%v", text, err)
+	}
+	if actual := ParseTweet(text, WithConfig(c)); actual != expected {
+		t.Errorf("ParseTweet returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+	var tweet Tweet
+	if ParseTweetInto(text, &tweet, nil, WithConfig(c)); tweet.ParseResults != expected {
+		t.Errorf("ParseTweetInto stored incorrect results for text [%s]. Expected:%+v Got:%+v", text, expected, tweet.ParseResults)
+	}
+	if actual, err := ParseReader(strings.NewReader(text), WithConfig(c)); actual != expected || err != nil {
+		t.Errorf("ParseReader returned incorrect value for text [%s]. Expected:%+v Got:%+v, %v", text, expected, actual, err)
 	}
 
-	lengthTests, ok := tests.(map[interface{}]interface{})["lengths"]
-	if !ok {
-		t.Errorf("Conformance file did not contain length tests")
-		t.FailNow()
+	// The URL that makes the tweet too long is outside of the valid range
+	c.TransformedURLLength = 10
+	expected = ParseResults{287, 1025, false, 0, 307, 0, 296}
+	if actual := ParseTweet(text, WithConfig(c)); actual != expected {
+		t.Errorf("ParseTweet returned incorrect value for text [%s] and a transformed URL length of 10. Expected:%+v Got:%+v", text, expected, actual)
 	}
+}
+
+func TestNormalize(t *testing.T) {
+	if minimalTables {
+		t.Skip("text is not normalized without the full tables")
+	}
+
+	tests := []string{
+		"",
+		"already normalized",
+		"cafe\u0301",
+		"e\u0301 at the start",
+		"\u1100\u1161\u11a8 hangul jamo",
+		norm.NFD.String("한국어 텍스트 and ünïcödé"),
+		"a\u0307\u0323 reordered marks",
+		"\u1eb9\u0301 normalized, but not by the quick check",
+		"\u1eb9\u0301 then e\u0301",
+	}
+
+	for _, text := range tests {
+		expected := norm.NFC.String(text)
+		if actual := normalize(text); actual != expected {
+			t.Errorf("normalize returned incorrect value for text [%+q]. Expected:[%+q] Got:[%+q]", text, expected, actual)
+		}
+	}
+}
+
+func TestTweetLengthAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with the race detector")
+	}
+	tests := []string{
+		"Hello world. How are you?",
+		"A longer tweet, with punctuation: commas; colons! e.g. this one...\r\nAnd lines",
+		// Normalized, but not by the quick check: the combining acute
+		// accent may combine with a preceding character, but not with ẹ
+		"Ẹ ku \u1eb9\u0301",
+	}
+	c := config.V3()
+
+	for _, text := range tests {
+		if allocs := testing.AllocsPerRun(100, func() { TweetLength(text) }); allocs != 0 {
+			t.Errorf("TweetLength allocated %v times for text [%s]", allocs, text)
+		}
+		if allocs := testing.AllocsPerRun(100, func() { TweetLength(text, WithConfig(c)) }); allocs != 0 {
+			t.Errorf("TweetLength with WithConfig allocated %v times for text [%s]", allocs, text)
+		}
+	}
+}
 
-	for _, testCase := range lengthTests.([]interface{}) {
-		test := testCase.(map[interface{}]interface{})
-		text, _ := test["text"]
-		description, _ := test["description"]
-		expected, _ := test["expected"]
+func TestAsciiWeight(t *testing.T) {
+	tests := []string{"", "plain text", "line\r\nbreaks\n\rand\r\r\n", "tabs\tand ~symbols~ #1 *2"}
+	configs := []*config.Config{config.V1(), config.V2(), config.V3(), config.Bluesky()}
 
-		actual := TweetLength(text.(string))
-		if actual != expected {
-			t.Errorf("TweetLength returned incorrect value for test [%s]. Expected:%v Got:%v", description, expected, actual)
+	for _, text := range tests {
+		for _, c := range configs {
+			expected, _ := unicodeWeight(text, c)
+			if actual := asciiWeight(text, c); actual != expected {
+				t.Errorf("asciiWeight returned incorrect value for text [%q] and version %d. Expected:%d Got:%d", text, c.Version, expected, actual)
+			}
 		}
 	}
 }
@@ -0,0 +1,288 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/extract"
+)
+
+func TestParseTweetWithConfig(t *testing.T) {
+	loaded, err := config.LoadConfig(strings.NewReader(`{"version": 2, "maxWeightedTweetLength": 10, "scale": 100, "defaultWeight": 200, "transformedURLLength": 23, "ranges": [{"start": 0, "end": 4351, "weight": 100}]}`))
+	if err != nil {
+		t.Fatalf("Error loading configuration: %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		c        *config.Config
+		expected ParseResults
+	}{
+		{"hello", config.V1(), ParseResults{5, 35, true, 0, 4, 0, 4}},
+		{"日本語", config.V2(), ParseResults{6, 21, true, 0, 2, 0, 2}},
+		{strings.Repeat("a", 141), config.V1(), ParseResults{141, 1007, false, 0, 140, 0, 139}},
+		{strings.Repeat("a", 141), config.V2(), ParseResults{141, 503, true, 0, 140, 0, 140}},
+		{"", config.V2(), ParseResults{0, 0, false, 0, 0, 0, 0}},
+		{"hello", loaded, ParseResults{5, 500, true, 0, 4, 0, 4}},
+		{"日本語日本語", loaded, ParseResults{12, 1200, false, 0, 5, 0, 4}},
+		{"ab\uFEFFcd", config.V2(), ParseResults{6, 21, false, 0, 4, 0, 1}},
+		{"\U0001f600 " + strings.Repeat("a", 278) + " \U0001f600", config.V3(), ParseResults{284, 1014, false, 0, 283, 0, 279}},
+	}
+
+	for _, test := range tests {
+		actual := ParseTweetWithConfig(test.text, test.c)
+		if actual != test.expected {
+			t.Errorf("ParseTweetWithConfig returned incorrect value for text [%s]. Expected:%+v Got:%+v", test.text, test.expected, actual)
+		}
+	}
+}
+
+// The valid range is moved by the difference the normalization makes to
+// the length, as in the reference implementations
+func TestParseTweetRangesNormalized(t *testing.T) {
+	if minimalTables {
+		t.Skip("Builds with the minimaltables tag do not normalize text")
+	}
+	text := strings.Repeat("a", 280) + "e\u0301"
+	expected := ParseResults{281, 1003, false, 0, 281, 0, 280}
+	if actual := ParseTweetWithConfig(text, config.V2()); actual != expected {
+		t.Errorf("ParseTweetWithConfig returned incorrect value for text [%s]. Expected:%+v Got:%+v", text, expected, actual)
+	}
+}
+
+func TestValidateTweetWithConfig(t *testing.T) {
+	text := strings.Repeat("a", 200)
+	if err := ValidateTweet(text); err != TooLongError(200) {
+		t.Errorf("ValidateTweet returned incorrect value for text [%s]. Expected:%v Got:%v", text, TooLongError(200), err)
+	}
+	if err := ValidateTweetWithConfig(text, config.V2()); err != nil {
+		t.Errorf("ValidateTweetWithConfig returned incorrect value for text [%s]. Expected:<nil> Got:%v", text, err)
+	}
+}
+
+func TestSetDefaultConfig(t *testing.T) {
+	defer SetDefaultConfig(DefaultConfig())
+
+	text := strings.Repeat("a", 200)
+	c := config.V2()
+	SetDefaultConfig(c)
+	c.MaxWeightedTweetLength = 100
+	if !TweetIsValid(text) {
+		t.Errorf("TweetIsValid returned false for text [%s] with the version 2 configuration", text)
+	}
+
+	SetDefaultConfig(config.V1())
+	if TweetIsValid(text) {
+		t.Errorf("TweetIsValid returned true for text [%s] with the version 1 configuration", text)
+	}
+
+	invalid := config.V2()
+	invalid.Scale = 0
+	if err := SetDefaultConfig(invalid); err == nil {
+		t.Errorf("SetDefaultConfig did not return an error for an invalid configuration")
+	}
+	if DefaultConfig().Version != 1 {
+		t.Errorf("SetDefaultConfig replaced the default configuration with an invalid configuration")
+	}
+}
+
+func TestSetDefaultConfigConcurrently(t *testing.T) {
+	defer SetDefaultConfig(DefaultConfig())
+
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			if i%2 == 0 {
+				SetDefaultConfig(config.V1())
+			} else {
+				SetDefaultConfig(config.V2())
+			}
+		}
+		done <- true
+	}()
+	for i := 0; i < 100; i++ {
+		if length := TweetLength("日本語"); length != 3 && length != 6 {
+			t.Errorf("TweetLength returned incorrect value for text [日本語]. Expected:3 or 6 Got:%d", length)
+		}
+	}
+	<-done
+}
+
+func TestDefaultConfigIsCopied(t *testing.T) {
+	defer SetDefaultConfig(DefaultConfig())
+
+	c := config.V2()
+	SetDefaultConfig(c)
+	c.DefaultWeight = 1
+	DefaultConfig().DefaultWeight = 1
+	if length := TweetLength("日本語"); length != 6 {
+		t.Errorf("Modifying a configuration changed the default configuration. TweetLength returned incorrect value for text [日本語]. Expected:6 Got:%d", length)
+	}
+}
+
+// Modifies configurations that have been passed to SetDefaultConfig,
+// NewValidator, and tweets being parsed, and the copies returned by
+// DefaultConfig, while other goroutines parse tweets. Any configuration
+// that is read after it has been passed in, rather than copied, is
+// reported by the race detector
+func TestConfigsModifiedConcurrently(t *testing.T) {
+	defer SetDefaultConfig(DefaultConfig())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				c := config.V2()
+				SetDefaultConfig(c)
+				c.MaxWeightedTweetLength = 1
+
+				DefaultConfig().Scale = 1
+			}
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				c := config.V2()
+				v := NewValidator(WithConfig(c))
+				c.Scale = 1
+				v.Write([]byte("日本語"))
+				if length := v.WeightedLength(); length != 6 {
+					t.Errorf("WeightedLength returned incorrect value for text [日本語]. Expected:6 Got:%d", length)
+				}
+
+				if length := TweetLength("日本語"); length != 3 && length != 6 {
+					t.Errorf("TweetLength returned incorrect value for text [日本語]. Expected:3 or 6 Got:%d", length)
+				}
+				ParseTweets([]string{"日本語", "http://example.com"})
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithConfig(t *testing.T) {
+	text := strings.Repeat("日", 100)
+	tests := []struct {
+		opts   []Option
+		length int
+		valid  bool
+	}{
+		{nil, 100, true},
+		{[]Option{WithConfig(config.V1())}, 100, true},
+		{[]Option{WithConfig(config.V2())}, 200, true},
+		{[]Option{WithConfig(&config.Config{Version: 1, MaxWeightedTweetLength: 150, Scale: 1, DefaultWeight: 2})}, 200, false},
+	}
+
+	for _, test := range tests {
+		if actual := TweetLength(text, test.opts...); actual != test.length {
+			t.Errorf("TweetLength returned incorrect value for text [%s]. Expected:%d Got:%d", text, test.length, actual)
+		}
+		if actual := TweetIsValid(text, test.opts...); actual != test.valid {
+			t.Errorf("TweetIsValid returned incorrect value for text [%s]. Expected:%v Got:%v", text, test.valid, actual)
+		}
+		if actual := ParseTweet(text, test.opts...); actual.WeightedLength != test.length || actual.IsValid != test.valid {
+			t.Errorf("ParseTweet returned incorrect value for text [%s]. Expected:%d %v Got:%+v", text, test.length, test.valid, actual)
+		}
+	}
+}
+
+func TestPresets(t *testing.T) {
+	if minimalTables {
+		t.Skip("grapheme clusters are approximated without the full tables")
+	}
+
+	tests := []struct {
+		preset string
+		text   string
+		length int
+	}{
+		{config.PresetMastodon, "see https://example.com/a/very/long/path", 27},
+		{config.PresetMastodon, "日本語", 3},
+		{config.PresetGeneric, "see https://example.com/a/very/long/path", 27},
+		{config.PresetBluesky, "see https://example.com/a/very/long/path", 40},
+		{config.PresetBluesky, "é̈ \U0001f468‍\U0001f469‍\U0001f467 \U0001f1ef\U0001f1f5", 5},
+	}
+
+	for _, test := range tests {
+		c, err := config.Preset(test.preset)
+		if err != nil {
+			t.Fatalf("Error loading preset [%s]: %v", test.preset, err)
+		}
+		if actual := TweetLength(test.text, WithConfig(c)); actual != test.length {
+			t.Errorf("TweetLength returned incorrect value for text [%s] and preset [%s]. Expected:%d Got:%d", test.text, test.preset, test.length, actual)
+		}
+	}
+
+	text := strings.Repeat("a", 500)
+	mastodon, _ := config.Preset(config.PresetMastodon)
+	if !TweetIsValid(text, WithConfig(mastodon)) || TweetIsValid(text+"a", WithConfig(mastodon)) {
+		t.Errorf("TweetIsValid returned incorrect values for the mastodon preset")
+	}
+}
+
+func TestParseTweetInto(t *testing.T) {
+	var (
+		tweet    Tweet
+		entities []extract.TwitterEntity
+	)
+	for _, c := range []*config.Config{config.V1(), config.V3()} {
+		for _, text := range streamTexts(t) {
+			ParseTweetInto(text, &tweet, &entities, WithConfig(c))
+			if tweet.Text != text || tweet.Normalized != Normalize(text) {
+				t.Errorf("ParseTweetInto stored incorrect text for text [%s]. Got:[%s] [%s]", text, tweet.Text, tweet.Normalized)
+			}
+			if expected := ParseTweetWithConfig(text, c); tweet.ParseResults != expected {
+				t.Errorf("ParseTweetInto stored incorrect results for text [%s] and version %d. Expected:%+v Got:%+v", text, c.Version, expected, tweet.ParseResults)
+			}
+			if expected := ValidateTweetWithConfig(text, c); tweet.Err != expected {
+				t.Errorf("ParseTweetInto stored incorrect error for text [%s] and version %d. Expected:%v Got:%v", text, c.Version, expected, tweet.Err)
+			}
+
+			var expected []extract.TwitterEntity
+			for _, e := range extract.ExtractEntities(text) {
+				expected = append(expected, *e)
+			}
+			if len(entities) != len(expected) || (len(expected) > 0 && !reflect.DeepEqual(entities, expected)) {
+				t.Errorf("ParseTweetInto stored incorrect entities for text [%s]. Expected:%v Got:%v", text, expected, entities)
+			}
+		}
+	}
+
+	// The entities are optional
+	ParseTweetInto("a #hashtag", &tweet, nil)
+	if !tweet.IsValid || tweet.WeightedLength != 10 {
+		t.Errorf("ParseTweetInto stored incorrect results without entities. Got:%+v", tweet.ParseResults)
+	}
+}
+
+func TestParseTweetIntoAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	if regexpExtract {
+		t.Skip("the regexps allocate their matches")
+	}
+	var (
+		tweet    Tweet
+		entities []extract.TwitterEntity
+	)
+	texts := []string{
+		"a tweet mentioning @username with a url http://t.co/abcde, $TWTR and a #hashtag \U0001F600",
+		"RT @user: see example.com/path?query=1 and https://twitter.com/user/status/123 #日本語",
+		"日本語のツイート",
+	}
+	c := config.V3()
+	for _, text := range texts {
+		ParseTweetInto(text, &tweet, &entities, WithConfig(c))
+		if allocs := testing.AllocsPerRun(100, func() { ParseTweetInto(text, &tweet, &entities, WithConfig(c)) }); allocs != 0 {
+			t.Errorf("ParseTweetInto allocated %v times for text [%s]", allocs, text)
+		}
+	}
+}
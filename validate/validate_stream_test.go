@@ -0,0 +1,165 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/conformance"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Returns the texts of the tests in the named sections of validate.yml
+func conformanceTexts(t testing.TB, sections ...string) []string {
+	suite, err := conformance.Load("validate.yml")
+	if err != nil {
+		t.Fatalf("Error loading validate.yml: %v", err)
+	}
+
+	var texts []string
+	for _, section := range sections {
+		for _, test := range suite.Tests[section] {
+			texts = append(texts, test.Text)
+		}
+	}
+	return texts
+}
+
+// Returns the texts of the tweets and length tests in validate.yml, and
+// longer texts that are split into several pieces by a Validator
+func streamTexts(t testing.TB) []string {
+	return append(conformanceTexts(t, "tweets", "lengths"),
+		strings.Repeat("see http://example.com/path and example.org ", 20),
+		strings.Repeat("日本語のテキスト ", 30),
+		strings.Repeat("\U0001F468\u200D\U0001F469\u200D\U0001F467 family ", 20),
+		strings.Repeat("a", 100)+" \uFEFF "+strings.Repeat("b ", 100),
+		norm.NFD.String(strings.Repeat("café ünïcödé 한국어 ", 20))+"\u202E",
+		strings.Repeat("x", 300),
+		strings.Repeat("日", 300),
+		strings.Repeat("http://example.com/", 20),
+	)
+}
+
+func TestValidator(t *testing.T) {
+	defer func(chunkSize, maxPending int) {
+		streamChunkSize, streamMaxPending = chunkSize, maxPending
+	}(streamChunkSize, streamMaxPending)
+	streamChunkSize, streamMaxPending = 16, 64
+
+	for _, c := range []*config.Config{config.V1(), config.V3()} {
+		for _, text := range streamTexts(t) {
+			expectedResults := ParseTweetWithConfig(text, c)
+			expectedErr := ValidateTweetWithConfig(text, c)
+
+			for _, size := range []int{1, 7, len(text) + 1} {
+				v := NewValidator(WithConfig(c))
+				for rest := text; rest != ""; {
+					n := size
+					if n > len(rest) {
+						n = len(rest)
+					}
+					v.Write([]byte(rest[:n]))
+					rest = rest[n:]
+
+					if cap(v.pending) > 2*streamMaxPending+size {
+						t.Fatalf("Validator buffered %d bytes of text [%s]", cap(v.pending), text)
+					}
+				}
+
+				// Long runs without a space may be split elsewhere
+				if longestRun(text) > streamMaxPending-streamChunkSize {
+					continue
+				}
+				if actual := v.ParseResults(); actual != expectedResults {
+					t.Errorf("ParseResults returned incorrect value for text [%s] written %d bytes at a time. Expected:%+v Got:%+v", text, size, expectedResults, actual)
+				}
+				if actual := v.Validate(); actual != expectedErr {
+					t.Errorf("Validate returned incorrect value for text [%s] written %d bytes at a time. Expected:%v Got:%v", text, size, expectedErr, actual)
+				}
+				if actual := v.WeightedLength(); actual != expectedResults.WeightedLength {
+					t.Errorf("WeightedLength returned incorrect value for text [%s] written %d bytes at a time. Expected:%d Got:%d", text, size, expectedResults.WeightedLength, actual)
+				}
+			}
+		}
+	}
+}
+
+// Returns the length of the longest run of text without a space
+func longestRun(text string) int {
+	longest := 0
+	for _, run := range strings.Split(text, " ") {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	return longest
+}
+
+func TestValidatorBoundedMemory(t *testing.T) {
+	v := NewValidator()
+	line := []byte(strings.Repeat("a tweet with http://example.com and 日本語 ", 100))
+	for i := 0; i < 1000; i++ {
+		v.Write(line)
+	}
+	if cap(v.pending) > 2*streamMaxPending {
+		t.Errorf("Validator buffered %d bytes of %d", cap(v.pending), 1000*len(line))
+	}
+
+	// A run without spaces is split once it reaches streamMaxPending
+	v = NewValidator()
+	for i := 0; i < 1000; i++ {
+		v.Write([]byte(strings.Repeat("日本語", 100)))
+	}
+	if cap(v.pending) > 2*streamMaxPending {
+		t.Errorf("Validator buffered %d bytes of a run without spaces", cap(v.pending))
+	}
+	if expected := TweetLength(strings.Repeat("日本語", 100000)); v.WeightedLength() != expected {
+		t.Errorf("WeightedLength returned incorrect value for a run without spaces. Expected:%d Got:%d", expected, v.WeightedLength())
+	}
+}
+
+func TestSplitPoint(t *testing.T) {
+	if minimalTables {
+		t.Skip("text is not normalized without the full tables")
+	}
+
+	tests := []struct {
+		text     string
+		force    bool
+		expected int
+	}{
+		{"no spaces", false, 2},
+		{"nospaces", false, 0},
+		{"日本 語", false, 6},
+		// A space after a prepended concatenation mark is part of its cluster
+		{"\u0600 1", false, 0},
+		{"日本語", false, 0},
+		{"日本語", true, 9},
+		{"ab日本", true, 1},
+		{"e\u0301e\u0301", true, 3},
+		{"日本\xe8\xaa", true, 6},
+	}
+
+	for _, test := range tests {
+		actual := splitPoint([]byte(test.text), test.force)
+		if actual != test.expected {
+			t.Errorf("splitPoint returned incorrect value for text [%q] with force %v. Expected:%d Got:%d", test.text, test.force, test.expected, actual)
+		}
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	text := strings.Repeat("a tweet with a #hashtag ", 20)
+	expected := ParseTweet(text)
+	actual, err := ParseReader(iotest.OneByteReader(strings.NewReader(text)))
+	if err != nil || actual != expected {
+		t.Errorf("ParseReader returned incorrect value for text [%s]. Expected:%+v Got:%+v, %v", text, expected, actual, err)
+	}
+
+	readErr := errors.New("read error")
+	if _, err := ParseReader(iotest.ErrReader(readErr)); err != readErr {
+		t.Errorf("ParseReader returned incorrect error. Expected:%v Got:%v", readErr, err)
+	}
+}
@@ -1,10 +0,0 @@
-package validate
-
-import (
-	"os"
-	"path"
-)
-
-var cwd, _ = os.Getwd()
-var parentDir = path.Dir(cwd)
-var validateYmlPath = path.Join(parentDir, "conformance", "validate.yml")
@@ -1,46 +1,36 @@
 package validate
 
 import (
-	"io/ioutil"
 	"testing"
 
-	goyaml "gopkg.in/yaml.v1"
+	"github.com/kylemcc/twitter-text-go/config"
 )
 
-func TestTweetIsValid(t *testing.T) {
-	contents, err := ioutil.ReadFile(validateYmlPath)
-	if err != nil {
-		t.Errorf("Error reading validate.yml: %v", err)
-		t.FailNow()
+func TestValidateTweetInvalidCharacter(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected error
+	}{
+		{"valid text", nil},
+		{"bom \uFEFF here", InvalidCharacterError{Character: '\uFEFF', Offset: 4}},
+		{"rtl \u202Eoverride \uFFFF", InvalidCharacterError{Character: '\u202E', Offset: 4}},
+		{"http://example.com \uFFFE", InvalidCharacterError{Character: '\uFFFE', Offset: 19}},
+		// The decomposed é before the invalid character is normalized to a
+		// single character, but the offset is within the original text
+		{"e\u0301 \u202A", InvalidCharacterError{Character: '\u202A', Offset: 4}},
+		// U+0600 is prepended to the invalid character, which is then in
+		// the middle of a grapheme cluster
+		{"\u0600\uFFFE", InvalidCharacterError{Character: '\uFFFE', Offset: 2}},
 	}
 
-	var testData map[interface{}]interface{}
-	err = goyaml.Unmarshal(contents, &testData)
-	if err != nil {
-		t.Fatalf("error unmarshaling data: %v\n", err)
-	}
-
-	tests, ok := testData["tests"]
-	if !ok {
-		t.Errorf("Conformance file was not in expected format.")
-		t.FailNow()
-	}
-
-	tweetTests, ok := tests.(map[interface{}]interface{})["tweets"]
-	if !ok {
-		t.Errorf("Conformance file did not contain tweet tests")
-		t.FailNow()
-	}
-
-	for _, testCase := range tweetTests.([]interface{}) {
-		test := testCase.(map[interface{}]interface{})
-		text, _ := test["text"]
-		description, _ := test["description"]
-		expected, _ := test["expected"]
-
-		actual := TweetIsValid(text.(string))
-		if actual != expected {
-			t.Errorf("TweetIsValid returned incorrect value for test [%s]. Expected:%v Got:%v", description, expected, actual)
+	for _, test := range tests {
+		for _, c := range []*config.Config{config.V1(), config.V3(), config.Bluesky()} {
+			if actual := ValidateTweet(test.text, WithConfig(c)); actual != test.expected {
+				t.Errorf("ValidateTweet returned incorrect value for text [%+q] and version %d. Expected:%v Got:%v", test.text, c.Version, test.expected, actual)
+			}
+			if _, actual := ParseNormalizedTweet(test.text, Normalize(test.text), nil, c); actual != test.expected {
+				t.Errorf("ParseNormalizedTweet returned incorrect error for text [%+q] and version %d. Expected:%v Got:%v", test.text, c.Version, test.expected, actual)
+			}
 		}
 	}
 }
@@ -0,0 +1,166 @@
+package validate
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kylemcc/twitter-text-go/config"
+	"github.com/kylemcc/twitter-text-go/conformance"
+)
+
+// Returns the weighted length tests in the named section of validate.yml
+func weightedTests(t *testing.T, section string) []conformance.Case {
+	suite, err := conformance.Load("validate.yml")
+	if err != nil {
+		t.Fatalf("Error loading validate.yml: %v", err)
+	}
+
+	tests, ok := suite.Tests[section]
+	if !ok {
+		t.Fatalf("Conformance file did not contain '%s' key", section)
+	}
+	return tests
+}
+
+func TestEmojiParsingEnabled(t *testing.T) {
+	tests := []struct {
+		text string
+		v2   int
+		v3   int
+	}{
+		{"This is a test.", 15, 15},
+		{"日本語", 6, 6},
+		{"\U0001f600\U0001f600", 4, 4},
+		{"\U0001f468‍\U0001f469‍\U0001f467‍\U0001f466", 11, 2},
+		{"\U0001f44d\U0001f3fd", 4, 2},
+		{"❤️", 4, 2},
+		{"❤", 2, 2},
+		{"\U0001f1ef\U0001f1f5", 4, 2},
+		{"1️⃣", 5, 2},
+		{"©️ ©", 5, 4},
+		{"\U0001f3f4\U000e0067\U000e0062\U000e0073\U000e0063\U000e0074\U000e007f", 14, 2},
+	}
+
+	for _, test := range tests {
+		if actual := ParseTweetWithConfig(test.text, config.V2()).WeightedLength; actual != test.v2 {
+			t.Errorf("ParseTweetWithConfig returned incorrect length for text [%s] and version 2. Expected:%d Got:%d", test.text, test.v2, actual)
+		}
+		if actual := ParseTweetWithConfig(test.text, config.V3()).WeightedLength; actual != test.v3 {
+			t.Errorf("ParseTweetWithConfig returned incorrect length for text [%s] and version 3. Expected:%d Got:%d", test.text, test.v3, actual)
+		}
+	}
+}
+
+func TestPermillage(t *testing.T) {
+	tests := [][2]int{
+		{0, 280}, {1, 280}, {279, 280}, {280, 280}, {281, 280}, {2261, 280},
+		{603, 150}, {1001, 1000}, {maxInt, 1}, {maxInt, 280}, {maxInt / 1000, 1},
+		{maxInt, maxInt}, {maxInt - 1, maxInt},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		tests = append(tests, [2]int{rnd.Intn(100000), 1 + rnd.Intn(1000)}, [2]int{int(rnd.Int31()), 1 + int(rnd.Int31())})
+	}
+
+	for _, test := range tests {
+		length, max := test[0], test[1]
+		product := new(big.Int).Mul(big.NewInt(int64(length)), big.NewInt(1000))
+		exact := product.Div(product, big.NewInt(int64(max)))
+		expected := maxInt
+		if exact.IsInt64() && exact.Int64() <= int64(maxInt) {
+			expected = int(exact.Int64())
+		}
+		if actual := permillage(length, max); actual != expected {
+			t.Errorf("permillage returned incorrect value for length %d and maximum %d. Expected:%d Got:%d", length, max, expected, actual)
+		}
+	}
+}
+
+func TestScaledLength(t *testing.T) {
+	for _, test := range []struct {
+		weight   int64
+		scale    int
+		expected int
+	}{
+		{0, 100, 0},
+		{99, 100, 0},
+		{100, 100, 1},
+		{28099, 100, 280},
+		{int64(maxInt), 1, maxInt},
+		{int64(maxInt), 100, maxInt / 100},
+	} {
+		c := &config.Config{Scale: test.scale}
+		if actual := scaledLength(test.weight, c); actual != test.expected {
+			t.Errorf("scaledLength returned incorrect value for weight %d and scale %d. Expected:%d Got:%d", test.weight, test.scale, test.expected, actual)
+		}
+	}
+}
+
+type weightedLengthTest struct {
+	text           string
+	config         *config.Config
+	weightedLength int
+	permillage     int
+}
+
+// Known weighted lengths and permillages, so that TestWeightedLengthNoDrift
+// checks the rounding rules without the conformance suites. The last
+// case weighs 4.5 characters, which only rounds to 4 if the weights are
+// summed before they are divided by the scale
+var weightedLengthTests = []weightedLengthTest{
+	{"hello", config.V1(), 5, 35},
+	{strings.Repeat("a", 139), config.V1(), 139, 992},
+	{"This is a test.", config.V2(), 15, 53},
+	{"日本語", config.V2(), 6, 21},
+	{"\u2018hi\u2019", config.V2(), 4, 14},
+	{"\U0001f600", config.V2(), 2, 7},
+	{"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466", config.V2(), 11, 39},
+	{strings.Repeat("a", 280), config.V2(), 280, 1000},
+	{strings.Repeat("日", 141), config.V2(), 282, 1007},
+	{"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466", config.V3(), 2, 7},
+	{"abc", &config.Config{MaxWeightedTweetLength: 7, Scale: 100, DefaultWeight: 150}, 4, 571},
+}
+
+// The known weighted lengths and the conformance expectations are
+// reproduced by the rounding rules alone, and multiplying the scale and
+// every weight of a configuration by the same factor changes no result,
+// as it would if any step rounded early
+func TestWeightedLengthNoDrift(t *testing.T) {
+	tests := append([]weightedLengthTest(nil), weightedLengthTests...)
+	sections := []struct {
+		name   string
+		config *config.Config
+	}{
+		{"WeightedTweetsCounterTest", config.V2()},
+		{"WeightedTweetsWithDiscountedEmojiCounterTest", config.V3()},
+	}
+	for _, section := range sections {
+		for _, test := range weightedTests(t, section.name) {
+			tests = append(tests, weightedLengthTest{test.Text, section.config, test.Expected.Results.WeightedLength, test.Expected.Results.Permillage})
+		}
+	}
+
+	for _, test := range tests {
+		if actual := permillage(test.weightedLength, test.config.MaxWeightedTweetLength); actual != test.permillage {
+			t.Errorf("permillage returned incorrect value for text [%s]. Expected:%d Got:%d", test.text, test.permillage, actual)
+		}
+
+		expectedResults := ParseTweetWithConfig(test.text, test.config)
+		if expectedResults.WeightedLength != test.weightedLength || expectedResults.Permillage != test.permillage {
+			t.Errorf("ParseTweetWithConfig returned incorrect value for text [%s]. Expected:%d, %d Got:%d, %d", test.text, test.weightedLength, test.permillage, expectedResults.WeightedLength, expectedResults.Permillage)
+		}
+		for _, factor := range []int{3, 7, 1000} {
+			c := test.config.Clone()
+			c.Scale *= factor
+			c.DefaultWeight *= factor
+			for i := range c.Ranges {
+				c.Ranges[i].Weight *= factor
+			}
+			if actual := ParseTweetWithConfig(test.text, c); actual != expectedResults {
+				t.Errorf("ParseTweetWithConfig returned incorrect value for text [%s] with weights scaled by %d. Expected:%+v Got:%+v", test.text, factor, expectedResults, actual)
+			}
+		}
+	}
+}